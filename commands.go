@@ -0,0 +1,538 @@
+package main
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"ticert/config"
+	"ticert/dto/request"
+	"ticert/repository"
+	"ticert/service"
+	"ticert/utils/errs"
+	"ticert/utils/migrate"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+const (
+	serveUsage   = "usage: main [serve]"
+	migrateUsage = "usage: main migrate up [N] | down [N] | status | create NAME"
+	rollupUsage  = "usage: main rollup rebuild|check"
+	adminUsage   = "usage: main admin create EMAIL FIRST_NAME LAST_NAME | promote EMAIL"
+	userUsage    = "usage: main user reset-password EMAIL | revoke-sessions EMAIL"
+	ordersUsage  = "usage: main orders expire [OLDER_THAN]"
+	reindexUsage = "usage: main reindex"
+	seedUsage    = "usage: main seed"
+)
+
+// migrationsDir is where "migrate create" writes new migrations, relative to
+// the repository root the command is run from.
+const migrationsDir = "migrations"
+
+// runMigrateCommand manages the schema: "up" applies pending migrations, all
+// of them or the next N, "down" reverts the latest one or N, "status" lists
+// them and "create" adds an empty migration to the source tree.
+func runMigrateCommand(args []string) {
+	if len(args) == 0 {
+		log.Fatal(migrateUsage)
+	}
+
+	if args[0] == "create" {
+		if len(args) != 2 {
+			log.Fatal(migrateUsage)
+		}
+
+		up, down, err := migrate.Create(migrationsDir, args[1])
+		if err != nil {
+			log.Fatalf("Failed to create migration: %v", err)
+		}
+		fmt.Printf("Created %s\nCreated %s\n", up, down)
+		return
+	}
+
+	migrator, err := config.NewMigrator(config.InitDatabase())
+	if err != nil {
+		log.Fatalf("Failed to load migrations: %v", err)
+	}
+	ctx := context.Background()
+
+	switch args[0] {
+	case "up", "down":
+		if len(args) > 2 {
+			log.Fatal(migrateUsage)
+		}
+
+		n := 0
+		if args[0] == "down" {
+			n = 1
+		}
+		if len(args) == 2 {
+			if n, err = strconv.Atoi(args[1]); err != nil || n < 1 {
+				log.Fatal(migrateUsage)
+			}
+		}
+
+		var done []*migrate.Migration
+		verb := "Applied"
+		if args[0] == "up" {
+			done, err = migrator.Up(ctx, n)
+		} else {
+			done, err = migrator.Down(ctx, n)
+			verb = "Reverted"
+		}
+
+		for _, migration := range done {
+			fmt.Printf("%s %d_%s\n", verb, migration.Version, migration.Name)
+		}
+		if err != nil {
+			log.Fatalf("Failed to migrate database: %v", err)
+		}
+		if len(done) == 0 {
+			fmt.Println("Nothing to migrate")
+		}
+
+	case "status":
+		if len(args) != 1 {
+			log.Fatal(migrateUsage)
+		}
+
+		statuses, err := migrator.Status(ctx)
+		if err != nil {
+			log.Fatalf("Failed to read migration status: %v", err)
+		}
+
+		for _, status := range statuses {
+			state := "pending"
+			switch {
+			case status.Dirty:
+				state = "dirty"
+			case status.AppliedAt != nil:
+				state = "applied " + status.AppliedAt.Format("2006-01-02 15:04:05")
+			}
+			if status.Missing {
+				state += ", file missing"
+			}
+			fmt.Printf("%04d  %-40s %s\n", status.Version, status.Name, state)
+		}
+
+	default:
+		log.Fatal(migrateUsage)
+	}
+}
+
+// runRollupCommand maintains the sales_daily rollup: "rebuild" backfills it
+// from the orders and "check" compares it with them, exiting with status 1
+// when they differ.
+func runRollupCommand(db *gorm.DB, args []string) {
+	if len(args) != 1 {
+		log.Fatal(rollupUsage)
+	}
+
+	ctx := context.Background()
+	rollupService := service.NewSalesRollupService(repository.NewSalesRollupRepository(db))
+
+	switch args[0] {
+	case "rebuild":
+		if err := rollupService.Rebuild(ctx); err != nil {
+			log.Fatalf("Failed to rebuild sales rollup: %v", err)
+		}
+		log.Println("Sales rollup rebuilt")
+
+	case "check":
+		check, err := rollupService.Check(ctx)
+		if err != nil {
+			log.Fatalf("Failed to check sales rollup: %v", err)
+		}
+
+		if check.RebuiltAt == nil {
+			fmt.Println("Sales rollup was never rebuilt; reports read from the orders")
+		} else {
+			fmt.Printf("Sales rollup last rebuilt at %s\n", check.RebuiltAt.Format("2006-01-02 15:04:05"))
+		}
+
+		for _, m := range check.Mismatches {
+			fmt.Printf("%s event %s category %s: rollup %d orders, %d tickets, %.2f revenue; orders %d orders, %d tickets, %.2f revenue\n",
+				m.Day.Format("2006-01-02"), m.EventID, m.CategoryID,
+				m.Rollup.OrderCount, m.Rollup.TicketsSold, m.Rollup.Revenue,
+				m.Raw.OrderCount, m.Raw.TicketsSold, m.Raw.Revenue)
+		}
+		fmt.Printf("%d category days checked, %d mismatched\n", check.Rows, len(check.Mismatches))
+
+		if len(check.Mismatches) > 0 {
+			os.Exit(1)
+		}
+
+	default:
+		log.Fatal(rollupUsage)
+	}
+}
+
+// defaultStaleOrderAge is how long an order may stay pending before
+// "orders expire" expires it when no age is given.
+const defaultStaleOrderAge = 24 * time.Hour
+
+// runAdminCommand manages admin accounts: "create" adds an admin with a
+// generated password, which is printed once, and "promote" gives an existing
+// user the admin role.
+func runAdminCommand(db *gorm.DB, args []string) {
+	if len(args) == 0 {
+		log.Fatal(adminUsage)
+	}
+
+	config.InitRedis(config.GetConfig())
+
+	ctx := context.Background()
+	userService := newUserService(db)
+
+	switch args[0] {
+	case "create":
+		if len(args) != 4 {
+			log.Fatal(adminUsage)
+		}
+
+		password := generatePassword()
+		user, validationErrors, err := userService.CreateAdmin(ctx, &request.CreateUserRequest{
+			Email:     args[1],
+			Password:  password,
+			FirstName: args[2],
+			LastName:  args[3],
+		})
+		exitOnServiceError("create admin", validationErrors, err)
+		fmt.Printf("Created admin %s (%s)\nPassword: %s\n", user.Email, user.ID, password)
+
+	case "promote":
+		if len(args) != 2 {
+			log.Fatal(adminUsage)
+		}
+
+		user, err := userService.PromoteToAdmin(ctx, args[1])
+		exitOnServiceError("promote user", nil, err)
+		fmt.Printf("%s is an admin; their sessions were revoked\n", user.Email)
+
+	default:
+		log.Fatal(adminUsage)
+	}
+}
+
+// runUserCommand acts on a user's credentials: "reset-password" replaces the
+// password with a generated one, which is printed once, and
+// "revoke-sessions" signs the user out everywhere.
+func runUserCommand(db *gorm.DB, args []string) {
+	if len(args) != 2 {
+		log.Fatal(userUsage)
+	}
+
+	config.InitRedis(config.GetConfig())
+
+	ctx := context.Background()
+	userService := newUserService(db)
+
+	switch args[0] {
+	case "reset-password":
+		password := generatePassword()
+		user, validationErrors, err := userService.ResetPassword(ctx, &request.ResetPasswordRequest{
+			Email:    args[1],
+			Password: password,
+		})
+		exitOnServiceError("reset password", validationErrors, err)
+		fmt.Printf("Reset the password of %s; their sessions were revoked\nPassword: %s\n", user.Email, password)
+
+	case "revoke-sessions":
+		err := userService.RevokeSessions(ctx, args[1])
+		exitOnServiceError("revoke sessions", nil, err)
+		fmt.Printf("Revoked all sessions of %s\n", args[1])
+
+	default:
+		log.Fatal(userUsage)
+	}
+}
+
+// runOrdersCommand maintains orders: "expire" expires the orders left pending
+// for longer than the given duration, 24h by default, returning their stock
+// and seats.
+func runOrdersCommand(db *gorm.DB, args []string) {
+	if len(args) == 0 || len(args) > 2 || args[0] != "expire" {
+		log.Fatal(ordersUsage)
+	}
+
+	age := defaultStaleOrderAge
+	if len(args) == 2 {
+		var err error
+		if age, err = time.ParseDuration(args[1]); err != nil || age <= 0 {
+			log.Fatal(ordersUsage)
+		}
+	}
+
+	config.InitRedis(config.GetConfig())
+
+	inventoryService := newInventoryService(db)
+	orderService := service.NewOrderService(
+		repository.NewOrderRepository(db),
+		repository.NewUserRepository(db),
+		repository.NewCategoryRepository(db),
+		inventoryService,
+		newWaitlistService(db, inventoryService),
+	)
+
+	before := time.Now().Add(-age)
+	expired, err := orderService.ExpireStaleOrders(context.Background(), before)
+	fmt.Printf("Expired %d orders placed before %s\n", expired, before.UTC().Format("2006-01-02 15:04:05"))
+	if err != nil {
+		log.Fatalf("Failed to expire orders: %v", err)
+	}
+}
+
+// runReindexCommand rebuilds the data derived from the database: the Redis
+// stock of every category is reconciled, the catalogue cache dropped and the
+// sales rollup rebuilt.
+func runReindexCommand(db *gorm.DB, args []string) {
+	if len(args) != 0 {
+		log.Fatal(reindexUsage)
+	}
+
+	config.InitRedis(config.GetConfig())
+
+	ctx := context.Background()
+	eventRepo := repository.NewEventRepository(db)
+	categoryRepo := repository.NewCategoryRepository(db)
+
+	if err := newInventoryService(db).Reconcile(ctx); err != nil {
+		log.Fatalf("Failed to reconcile inventory: %v", err)
+	}
+	log.Println("Inventory reconciled")
+
+	service.NewCatalogueService(eventRepo, categoryRepo, repository.NewCacheRepository()).Invalidate(ctx)
+	log.Println("Catalogue cache invalidated")
+
+	if err := service.NewSalesRollupService(repository.NewSalesRollupRepository(db)).Rebuild(ctx); err != nil {
+		log.Fatalf("Failed to rebuild sales rollup: %v", err)
+	}
+	log.Println("Sales rollup rebuilt")
+}
+
+// demoEvent is an event created by "seed", starting the given number of days
+// from now at the venue.
+type demoEvent struct {
+	Title       string
+	Description string
+	Days        int
+	Starts      string
+	Ends        string
+	Categories  []demoCategory
+}
+
+type demoCategory struct {
+	Name              string
+	Price             float64
+	Quantity          int
+	InventoryStrategy string
+}
+
+const (
+	demoOrganizer  = "Ticert Demo"
+	demoVenue      = "Ticert Demo Arena"
+	demoAdminEmail = "admin@ticert.test"
+	demoUserEmail  = "user@ticert.test"
+)
+
+var demoEvents = []demoEvent{
+	{
+		Title:       "Ticert Demo Concert",
+		Description: "An evening concert to try out ticket sales.",
+		Days:        30,
+		Starts:      "19:00",
+		Ends:        "23:00",
+		Categories: []demoCategory{
+			{Name: "VIP", Price: 1500000, Quantity: 100, InventoryStrategy: "redis"},
+			{Name: "Regular", Price: 500000, Quantity: 1000, InventoryStrategy: "database"},
+		},
+	},
+	{
+		Title:       "Ticert Demo Conference",
+		Description: "A one day conference to try out ticket sales.",
+		Days:        45,
+		Starts:      "09:00",
+		Ends:        "17:00",
+		Categories: []demoCategory{
+			{Name: "Early Bird", Price: 250000, Quantity: 200, InventoryStrategy: "database"},
+			{Name: "General", Price: 400000, Quantity: 500, InventoryStrategy: "database"},
+		},
+	},
+	{
+		Title:       "Ticert Demo Festival",
+		Description: "An all day festival to try out ticket sales.",
+		Days:        60,
+		Starts:      "14:00",
+		Ends:        "23:00",
+		Categories: []demoCategory{
+			{Name: "Day Pass", Price: 750000, Quantity: 2000, InventoryStrategy: "redis"},
+		},
+	},
+}
+
+// runSeedCommand fills the database with demo data through the services: an
+// admin and a buyer sharing a generated password, a venue and upcoming events
+// with ticket categories. Data that already exists is left as it is, so the
+// command can be run again.
+func runSeedCommand(db *gorm.DB, args []string) {
+	if len(args) != 0 {
+		log.Fatal(seedUsage)
+	}
+
+	config.InitRedis(config.GetConfig())
+
+	ctx := context.Background()
+	userRepo := repository.NewUserRepository(db)
+	eventRepo := repository.NewEventRepository(db)
+	categoryRepo := repository.NewCategoryRepository(db)
+	venueRepo := repository.NewVenueRepository(db)
+
+	inventoryService := newInventoryService(db)
+	waitlistService := newWaitlistService(db, inventoryService)
+	catalogueService := service.NewCatalogueService(eventRepo, categoryRepo, repository.NewCacheRepository())
+	userService := service.NewUserService(userRepo, repository.NewAuthRepository())
+	venueService := service.NewVenueService(venueRepo, categoryRepo)
+	eventService := service.NewEventService(eventRepo, repository.NewSeatRepository(db), venueRepo, categoryRepo, inventoryService, catalogueService)
+	categoryService := service.NewCategoryService(categoryRepo, eventRepo, inventoryService, waitlistService, catalogueService)
+
+	password := generatePassword()
+	passwordUsed := false
+
+	_, validationErrors, err := userService.CreateAdmin(ctx, &request.CreateUserRequest{
+		Email:     demoAdminEmail,
+		Password:  password,
+		FirstName: "Demo",
+		LastName:  "Admin",
+	})
+	if errors.Is(err, errs.ErrEmailAlreadyExists) {
+		fmt.Printf("Skipped admin %s, it already exists\n", demoAdminEmail)
+	} else {
+		exitOnServiceError("seed admin", validationErrors, err)
+		fmt.Printf("Created admin %s\n", demoAdminEmail)
+		passwordUsed = true
+	}
+
+	_, validationErrors, err = userService.Register(ctx, &request.RegisterRequest{
+		Email:     demoUserEmail,
+		Password:  password,
+		FirstName: "Demo",
+		LastName:  "Buyer",
+	})
+	if errors.Is(err, errs.ErrEmailAlreadyExists) {
+		fmt.Printf("Skipped user %s, it already exists\n", demoUserEmail)
+	} else {
+		exitOnServiceError("seed user", validationErrors, err)
+		fmt.Printf("Created user %s\n", demoUserEmail)
+		passwordUsed = true
+	}
+
+	if passwordUsed {
+		fmt.Printf("Password: %s\n", password)
+	}
+
+	venues, validationErrors, err := venueService.GetVenues(ctx, &request.GetVenuesRequest{Page: 1, Limit: 10, Search: demoVenue})
+	exitOnServiceError("look up demo venue", validationErrors, err)
+
+	var venueID uuid.UUID
+	for _, venue := range venues.Venues {
+		if venue.Name == demoVenue {
+			venueID = venue.ID
+			break
+		}
+	}
+	if venueID == uuid.Nil {
+		venue, validationErrors, err := venueService.CreateVenue(ctx, &request.CreateVenueRequest{
+			Name:     demoVenue,
+			Address:  "Jl. Jend. Sudirman No. 1, Jakarta",
+			Timezone: "Asia/Jakarta",
+			Capacity: 5000,
+		})
+		exitOnServiceError("seed venue", validationErrors, err)
+		venueID = venue.ID
+		fmt.Printf("Created venue %s\n", demoVenue)
+	}
+
+	loc, err := time.LoadLocation("Asia/Jakarta")
+	if err != nil {
+		log.Fatalf("Failed to load demo time zone: %v", err)
+	}
+
+	for _, demo := range demoEvents {
+		day := time.Now().In(loc).AddDate(0, 0, demo.Days).Format("2006-01-02")
+
+		event, validationErrors, err := eventService.CreateEvent(ctx, &request.CreateEventRequest{
+			Organizer:   demoOrganizer,
+			Title:       demo.Title,
+			Description: demo.Description,
+			StartsAt:    day + "T" + demo.Starts,
+			EndsAt:      day + "T" + demo.Ends,
+			VenueID:     &venueID,
+		})
+		if errors.Is(err, errs.ErrEventTitleAlreadyExists) {
+			fmt.Printf("Skipped event %s, it already exists\n", demo.Title)
+			continue
+		}
+		exitOnServiceError("seed event", validationErrors, err)
+
+		for _, category := range demo.Categories {
+			_, validationErrors, err := categoryService.CreateCategory(ctx, &request.CreateCategoryRequest{
+				EventID:           event.ID,
+				Name:              category.Name,
+				Price:             category.Price,
+				Quantity:          category.Quantity,
+				EventDate:         day,
+				InventoryStrategy: category.InventoryStrategy,
+			})
+			exitOnServiceError("seed category", validationErrors, err)
+		}
+		fmt.Printf("Created event %s with %d categories\n", demo.Title, len(demo.Categories))
+	}
+}
+
+func newUserService(db *gorm.DB) service.UserService {
+	return service.NewUserService(repository.NewUserRepository(db), repository.NewAuthRepository())
+}
+
+func newInventoryService(db *gorm.DB) service.InventoryService {
+	return service.NewInventoryService(repository.NewInventoryRepository(), repository.NewCategoryRepository(db), repository.NewOrderRepository(db))
+}
+
+func newWaitlistService(db *gorm.DB, inventoryService service.InventoryService) service.WaitlistService {
+	return service.NewWaitlistService(repository.NewWaitlistRepository(db), repository.NewCategoryRepository(db), inventoryService, service.NewLogNotifier())
+}
+
+// generatePassword returns a random password for accounts created from the
+// command line, which their owners are expected to change.
+func generatePassword() string {
+	b := make([]byte, 12)
+	if _, err := rand.Read(b); err != nil {
+		log.Fatalf("Failed to generate password: %v", err)
+	}
+	return base64.RawURLEncoding.EncodeToString(b)
+}
+
+// exitOnServiceError ends the command when a service call failed, printing
+// its validation errors field by field.
+func exitOnServiceError(action string, validationErrors map[string]string, err error) {
+	if err != nil {
+		log.Fatalf("Failed to %s: %v", action, err)
+	}
+
+	if validationErrors != nil {
+		fields := make([]string, 0, len(validationErrors))
+		for field, message := range validationErrors {
+			fields = append(fields, field+": "+message)
+		}
+		sort.Strings(fields)
+		log.Fatalf("Failed to %s: %s", action, strings.Join(fields, "; "))
+	}
+}
@@ -5,6 +5,7 @@ import (
 	"log"
 	"os"
 	"strconv"
+	"time"
 )
 
 type Config struct {
@@ -18,6 +19,13 @@ type Config struct {
 	DBPassword string
 	DBName     string
 
+	// DBLegacyTimezone is the zone the database connection used before
+	// timestamps were stored in UTC. Only read by the schedule migration.
+	DBLegacyTimezone string
+
+	// DBMigrateOnStart applies pending migrations when the server starts.
+	DBMigrateOnStart string
+
 	// Redis Config
 	RedisHost     string
 	RedisPort     string
@@ -30,6 +38,24 @@ type Config struct {
 	JWTAccessExpiry  string // in hours
 	JWTRefreshExpiry string // in hours
 
+	// Storage Config
+	StorageDriver    string // local or s3
+	StorageLocalDir  string
+	StoragePublicURL string
+	S3Endpoint       string
+	S3Region         string
+	S3Bucket         string
+	S3AccessKey      string
+	S3SecretKey      string
+	S3PathStyle      string
+
+	// Mail Config
+	MailDriver   string // log or smtp
+	MailFrom     string
+	SMTPHost     string
+	SMTPPort     string
+	SMTPUsername string
+	SMTPPassword string
 }
 
 func GetConfig() *Config {
@@ -44,6 +70,9 @@ func GetConfig() *Config {
 		DBPassword: getEnv("DB_PASSWORD"),
 		DBName:     getEnv("DB_NAME"),
 
+		DBLegacyTimezone: getEnv("DB_LEGACY_TIMEZONE"),
+		DBMigrateOnStart: getEnvDefault("DB_MIGRATE_ON_START", "true"),
+
 		// Redis
 		RedisHost:     getEnv("REDIS_HOST"),
 		RedisPort:     getEnv("REDIS_PORT"),
@@ -55,6 +84,25 @@ func GetConfig() *Config {
 		JWTRefreshSecret: getEnv("JWT_REFRESH_SECRET"),
 		JWTAccessExpiry:  getEnv("JWT_ACCESS_EXPIRY"),
 		JWTRefreshExpiry: getEnv("JWT_REFRESH_EXPIRY"),
+
+		// Storage
+		StorageDriver:    getEnvDefault("STORAGE_DRIVER", "local"),
+		StorageLocalDir:  getEnvDefault("STORAGE_LOCAL_DIR", "uploads"),
+		StoragePublicURL: getEnv("STORAGE_PUBLIC_URL"),
+		S3Endpoint:       getEnv("S3_ENDPOINT"),
+		S3Region:         getEnvDefault("S3_REGION", "us-east-1"),
+		S3Bucket:         getEnv("S3_BUCKET"),
+		S3AccessKey:      getEnv("S3_ACCESS_KEY"),
+		S3SecretKey:      getEnv("S3_SECRET_KEY"),
+		S3PathStyle:      getEnvDefault("S3_PATH_STYLE", "true"),
+
+		// Mail
+		MailDriver:   getEnvDefault("MAIL_DRIVER", "log"),
+		MailFrom:     getEnvDefault("MAIL_FROM", "Ticert <noreply@localhost>"),
+		SMTPHost:     getEnv("SMTP_HOST"),
+		SMTPPort:     getEnvDefault("SMTP_PORT", "587"),
+		SMTPUsername: getEnv("SMTP_USERNAME"),
+		SMTPPassword: getEnv("SMTP_PASSWORD"),
 	}
 
 	// Validate all required environment variables
@@ -65,7 +113,7 @@ func GetConfig() *Config {
 
 // GetDatabaseDSN returns MySQL DSN string with UTC timezone
 func (c *Config) GetDatabaseDSN() string {
-	return fmt.Sprintf("%s:%s@tcp(%s:%s)/%s?charset=utf8mb4&parseTime=True&loc=Local",
+	return fmt.Sprintf("%s:%s@tcp(%s:%s)/%s?charset=utf8mb4&parseTime=True&loc=UTC",
 		c.DBUser,
 		c.DBPassword,
 		c.DBHost,
@@ -74,10 +122,32 @@ func (c *Config) GetDatabaseDSN() string {
 	)
 }
 
+// GetLegacyLocation returns the zone datetimes were written in before the
+// connection switched to UTC. It defaults to the server's local zone, which is
+// what the old loc=Local connection used.
+func (c *Config) GetLegacyLocation() *time.Location {
+	if c.DBLegacyTimezone == "" {
+		return time.Local
+	}
+
+	loc, err := time.LoadLocation(c.DBLegacyTimezone)
+	if err != nil {
+		return time.Local
+	}
+	return loc
+}
+
 func getEnv(key string) string {
 	return os.Getenv(key)
 }
 
+func getEnvDefault(key, fallback string) string {
+	if value := os.Getenv(key); value != "" {
+		return value
+	}
+	return fallback
+}
+
 func ValidateConfig(cfg *Config) {
 	// Required environment variables (DB_PASSWORD is optional - can be blank)
 	requiredEnvVars := map[string]string{
@@ -127,4 +197,58 @@ func ValidateConfig(cfg *Config) {
 		log.Printf("Invalid JWT_REFRESH_EXPIRY value '%d': must be greater than 0", refreshExpiry)
 		log.Fatal("JWT_REFRESH_EXPIRY must be a positive integer")
 	}
+
+	if cfg.DBLegacyTimezone != "" {
+		if _, err := time.LoadLocation(cfg.DBLegacyTimezone); err != nil {
+			log.Printf("Invalid DB_LEGACY_TIMEZONE value '%s': %v", cfg.DBLegacyTimezone, err)
+			log.Fatal("DB_LEGACY_TIMEZONE must be an IANA time zone name")
+		}
+	}
+
+	if _, err := strconv.ParseBool(cfg.DBMigrateOnStart); err != nil {
+		log.Printf("Invalid DB_MIGRATE_ON_START value '%s': %v", cfg.DBMigrateOnStart, err)
+		log.Fatal("DB_MIGRATE_ON_START must be true or false")
+	}
+
+	switch cfg.StorageDriver {
+	case "local":
+	case "s3":
+		s3EnvVars := map[string]string{
+			"S3_ENDPOINT":   cfg.S3Endpoint,
+			"S3_BUCKET":     cfg.S3Bucket,
+			"S3_ACCESS_KEY": cfg.S3AccessKey,
+			"S3_SECRET_KEY": cfg.S3SecretKey,
+		}
+		for envVar, value := range s3EnvVars {
+			if value == "" {
+				missingEnvVars = append(missingEnvVars, envVar)
+			}
+		}
+		if len(missingEnvVars) > 0 {
+			log.Printf("Missing required environment variables: %v", missingEnvVars)
+			log.Fatal("STORAGE_DRIVER=s3 requires S3_ENDPOINT, S3_BUCKET, S3_ACCESS_KEY and S3_SECRET_KEY")
+		}
+		if _, err := strconv.ParseBool(cfg.S3PathStyle); err != nil {
+			log.Printf("Invalid S3_PATH_STYLE value '%s': %v", cfg.S3PathStyle, err)
+			log.Fatal("S3_PATH_STYLE must be true or false")
+		}
+	default:
+		log.Printf("Invalid STORAGE_DRIVER value '%s'", cfg.StorageDriver)
+		log.Fatal("STORAGE_DRIVER must be local or s3")
+	}
+
+	switch cfg.MailDriver {
+	case "log":
+	case "smtp":
+		if cfg.SMTPHost == "" {
+			log.Fatal("MAIL_DRIVER=smtp requires SMTP_HOST")
+		}
+		if _, err := strconv.Atoi(cfg.SMTPPort); err != nil {
+			log.Printf("Invalid SMTP_PORT value '%s': %v", cfg.SMTPPort, err)
+			log.Fatal("SMTP_PORT must be a port number")
+		}
+	default:
+		log.Printf("Invalid MAIL_DRIVER value '%s'", cfg.MailDriver)
+		log.Fatal("MAIL_DRIVER must be log or smtp")
+	}
 }
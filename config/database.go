@@ -1,8 +1,14 @@
 package config
 
 import (
+	"context"
+	"database/sql"
+	"fmt"
 	"log"
 	"ticert/entity"
+	"ticert/migrations"
+	"ticert/utils/migrate"
+	"time"
 
 	"gorm.io/driver/mysql"
 	"gorm.io/gorm"
@@ -32,16 +38,345 @@ func InitDatabase() *gorm.DB {
 	return db
 }
 
-func AutoMigrate(db *gorm.DB) {
+// NewMigrator returns a migrator for the embedded migrations. A database that
+// was auto-migrated by earlier releases is adopted before its first migration.
+func NewMigrator(db *gorm.DB) (*migrate.Migrator, error) {
+	sqlDB, err := db.DB()
+	if err != nil {
+		return nil, err
+	}
+
+	migrator, err := migrate.New(sqlDB, migrations.FS)
+	if err != nil {
+		return nil, err
+	}
+
+	migrator.Adopt = func(ctx context.Context) error {
+		return adoptAutoMigratedSchema(db.WithContext(ctx))
+	}
+
+	return migrator, nil
+}
+
+// Migrate applies the pending migrations. When several instances start at
+// once, one of them migrates while the others wait for it.
+func Migrate(db *gorm.DB) {
+	migrator, err := NewMigrator(db)
+	if err != nil {
+		log.Fatalf("Failed to load migrations: %v", err)
+	}
+
+	applied, err := migrator.Up(context.Background(), 0)
+	for _, migration := range applied {
+		log.Printf("Applied migration %d_%s", migration.Version, migration.Name)
+	}
+	if err != nil {
+		log.Fatalf("Failed to migrate database: %v", err)
+	}
+
+	log.Println("Database migration completed")
+}
+
+// adoptAutoMigratedSchema brings a database created by the GORM
+// auto-migration, which earlier releases ran at every start, to the schema of
+// the first migration: legacy layouts are converted, the tables auto-migrated
+// one last time and new columns backfilled. Empty databases are left to the
+// migrations. The auto-migration follows the entities, so it only matches
+// version 1 until a migration changes one of their tables; such a migration
+// has to replace it with the statements it stands for.
+func adoptAutoMigratedSchema(db *gorm.DB) error {
+	if !db.Migrator().HasTable(&entity.User{}) {
+		return nil
+	}
+
+	if err := migrateEventSchedule(db, GetConfig().GetLegacyLocation()); err != nil {
+		return fmt.Errorf("failed to migrate event schedules: %w", err)
+	}
+
+	backfillRedeemedAt := !db.Migrator().HasColumn(&entity.OrderDetail{}, "redeemed_at")
+	backfillPaidAt := !db.Migrator().HasColumn(&entity.Order{}, "paid_at")
+
 	err := db.AutoMigrate(
 		&entity.User{},
+		&entity.Venue{},
+		&entity.SeatMap{},
+		&entity.SeatSection{},
+		&entity.Seat{},
+		&entity.EventSeries{},
+		&entity.Tag{},
 		&entity.Event{},
+		&entity.EventImage{},
 		&entity.Category{},
+		&entity.PriceTier{},
+		&entity.WaitlistEntry{},
+		&entity.CategorySeat{},
 		&entity.Order{},
+		&entity.OrderItem{},
 		&entity.OrderDetail{},
+		&entity.SalesDaily{},
+		&entity.RollupState{},
+		&entity.ReportSubscription{},
+		&entity.ReportRun{},
 	)
 	if err != nil {
-		log.Fatalf("Failed to auto migrate: %v", err)
+		return fmt.Errorf("failed to auto migrate: %w", err)
 	}
-	log.Println("Database migration completed")
+
+	if err := migrateOrderItems(db); err != nil {
+		return fmt.Errorf("failed to migrate orders to order items: %w", err)
+	}
+
+	if backfillRedeemedAt {
+		if err := migrateRedeemedAt(db); err != nil {
+			return fmt.Errorf("failed to backfill ticket redemption times: %w", err)
+		}
+	}
+
+	if backfillPaidAt {
+		if err := migratePaidAt(db); err != nil {
+			return fmt.Errorf("failed to backfill order payment times: %w", err)
+		}
+	}
+
+	log.Println("Adopted auto-migrated database schema")
+	return nil
+}
+
+// migrateOrderItems converts single-category orders into order items. Orders
+// used to reference one category through orders.category_id; every such order
+// becomes one line and its tickets are attached to that line before the
+// legacy column is dropped. MySQL commits schema changes implicitly, so the
+// column is dropped after the backfill has been committed; the backfill only
+// touches rows not converted yet and is safe to repeat should the drop fail.
+func migrateOrderItems(db *gorm.DB) error {
+	if !db.Migrator().HasColumn(&entity.Order{}, "category_id") {
+		return nil
+	}
+
+	err := db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Exec(`UPDATE orders
+			JOIN categories ON categories.id = orders.category_id
+			SET orders.event_id = categories.event_id
+			WHERE orders.event_id = '' OR orders.event_id IS NULL`).Error; err != nil {
+			return err
+		}
+
+		if err := tx.Exec(`INSERT INTO order_items (id, order_id, category_id, quantity, unit_price, subtotal, created_at, updated_at)
+			SELECT UUID(), orders.id, orders.category_id, orders.quantity,
+				CASE WHEN orders.quantity > 0 THEN orders.total_price / orders.quantity ELSE 0 END,
+				orders.total_price, orders.created_at, orders.updated_at
+			FROM orders
+			WHERE NOT EXISTS (SELECT 1 FROM order_items WHERE order_items.order_id = orders.id)`).Error; err != nil {
+			return err
+		}
+
+		return tx.Exec(`UPDATE order_details
+			JOIN order_items ON order_items.order_id = order_details.order_id
+			SET order_details.order_item_id = order_items.id
+			WHERE order_details.order_item_id = '' OR order_details.order_item_id IS NULL`).Error
+	})
+	if err != nil {
+		return err
+	}
+
+	return db.Migrator().DropColumn(&entity.Order{}, "category_id")
+}
+
+// migrateRedeemedAt dates the tickets redeemed before redemption times were
+// recorded. Redeeming was the only update made to a ticket, so its last
+// update time is when it was checked in.
+func migrateRedeemedAt(db *gorm.DB) error {
+	return db.Exec(`UPDATE order_details
+		SET redeemed_at = updated_at
+		WHERE redeemed = TRUE AND redeemed_at IS NULL`).Error
+}
+
+// migratePaidAt dates the orders paid before payment times were recorded.
+// Verifying the payment was the last update made to a paid order. Refunded
+// orders were updated again when refunded, so their payment time stays
+// unknown.
+func migratePaidAt(db *gorm.DB) error {
+	return db.Exec(`UPDATE orders
+		SET paid_at = updated_at
+		WHERE status = 'paid' AND paid_at IS NULL`).Error
+}
+
+// legacyEventSchedule is an events row before schedules became UTC instants.
+type legacyEventSchedule struct {
+	ID        string
+	StartDate time.Time
+	EndDate   time.Time
+	StartTime time.Time
+	EndTime   time.Time
+}
+
+// eventScheduleMarker records that the datetime columns were shifted from the
+// legacy zone to UTC. It is written in the transaction doing the shift, so a
+// migration interrupted before the legacy columns are dropped does not shift
+// them again when it is repeated.
+const eventScheduleMarker = "event_schedule_conversion"
+
+// migrateEventSchedule converts events from separate date and time columns to
+// UTC start and end instants with an IANA time zone. The connection used to
+// run with loc=Local, so every datetime column holds wall clock values of the
+// legacy zone; those are shifted to UTC with MySQL's time zone tables, or by
+// the zone's fixed offset when the tables are not loaded. Event dates were
+// parsed as UTC midnight before being written in the legacy zone, so the
+// calendar day is recovered by converting them back to UTC, while event times
+// are wall clock values on a placeholder day.
+//
+// MySQL commits schema changes implicitly, so columns are added before and
+// dropped after the transaction converting the data. The conversion is
+// recorded in a marker table and skipped once done.
+func migrateEventSchedule(db *gorm.DB, legacy *time.Location) error {
+	if !db.Migrator().HasTable(&entity.Event{}) || !db.Migrator().HasColumn(&entity.Event{}, "start_date") {
+		return nil
+	}
+
+	// Events keep an IANA name, which the server's local zone does not carry
+	// unless it happens to be UTC.
+	if legacy.String() == "Local" {
+		if offset, fixed := fixedOffset(legacy); !fixed || offset != 0 {
+			return fmt.Errorf("set DB_LEGACY_TIMEZONE to the IANA name of the server's time zone")
+		}
+		legacy = time.UTC
+	}
+
+	from, err := legacyZoneName(db, legacy)
+	if err != nil {
+		return err
+	}
+
+	for _, column := range []string{"starts_at", "ends_at", "timezone"} {
+		if db.Migrator().HasColumn(&entity.Event{}, column) {
+			continue
+		}
+
+		columnType := "DATETIME NULL"
+		if column == "timezone" {
+			columnType = "VARCHAR(64) NULL"
+		}
+		if err := db.Exec(fmt.Sprintf("ALTER TABLE events ADD COLUMN %s %s", column, columnType)).Error; err != nil {
+			return err
+		}
+	}
+
+	if err := db.Exec("CREATE TABLE IF NOT EXISTS " + eventScheduleMarker + " (converted_at DATETIME NOT NULL)").Error; err != nil {
+		return err
+	}
+
+	err = db.Transaction(func(tx *gorm.DB) error {
+		var converted int64
+		if err := tx.Table(eventScheduleMarker).Count(&converted).Error; err != nil {
+			return err
+		}
+		if converted > 0 {
+			return nil
+		}
+
+		if from != "+00:00" {
+			var columns []struct {
+				TableName  string
+				ColumnName string
+			}
+			if err := tx.Raw(`SELECT TABLE_NAME AS table_name, COLUMN_NAME AS column_name
+				FROM information_schema.COLUMNS
+				WHERE TABLE_SCHEMA = DATABASE() AND DATA_TYPE = 'datetime'
+					AND NOT (TABLE_NAME = 'events' AND COLUMN_NAME IN ('start_date', 'end_date', 'start_time', 'end_time', 'starts_at', 'ends_at'))
+					AND TABLE_NAME <> ?`, eventScheduleMarker).
+				Scan(&columns).Error; err != nil {
+				return err
+			}
+
+			for _, c := range columns {
+				if err := tx.Exec(fmt.Sprintf("UPDATE `%s` SET `%s` = CONVERT_TZ(`%s`, ?, '+00:00')", c.TableName, c.ColumnName, c.ColumnName), from).Error; err != nil {
+					return err
+				}
+			}
+		}
+
+		var events []legacyEventSchedule
+		if err := tx.Raw("SELECT id, start_date, end_date, start_time, end_time FROM events").Scan(&events).Error; err != nil {
+			return err
+		}
+
+		for _, e := range events {
+			if err := tx.Exec("UPDATE events SET starts_at = ?, ends_at = ?, timezone = ? WHERE id = ?",
+				legacyInstant(e.StartDate, e.StartTime, legacy),
+				legacyInstant(e.EndDate, e.EndTime, legacy),
+				legacy.String(), e.ID).Error; err != nil {
+				return err
+			}
+		}
+
+		return tx.Exec("INSERT INTO "+eventScheduleMarker+" (converted_at) VALUES (?)", time.Now().UTC()).Error
+	})
+	if err != nil {
+		return err
+	}
+
+	for _, column := range []string{"start_date", "end_date", "start_time", "end_time"} {
+		if !db.Migrator().HasColumn(&entity.Event{}, column) {
+			continue
+		}
+		if err := db.Migrator().DropColumn(&entity.Event{}, column); err != nil {
+			return err
+		}
+	}
+
+	return db.Migrator().DropTable(eventScheduleMarker)
+}
+
+// legacyZoneName returns the zone to pass to CONVERT_TZ for datetimes written
+// in the legacy zone: its IANA name when MySQL's time zone tables know it, so
+// daylight saving time is applied per value, and otherwise its offset. A zone
+// whose offset changes cannot be converted without the tables.
+func legacyZoneName(db *gorm.DB, legacy *time.Location) (string, error) {
+	offset, fixed := fixedOffset(legacy)
+	if fixed && offset == 0 {
+		return "+00:00", nil
+	}
+
+	// CONVERT_TZ returns NULL for zones missing from the time zone tables.
+	var converted sql.NullString
+	if err := db.Raw("SELECT CONVERT_TZ('2000-01-01 00:00:00', ?, '+00:00')", legacy.String()).Row().Scan(&converted); err != nil {
+		return "", err
+	}
+	if converted.Valid {
+		return legacy.String(), nil
+	}
+
+	if !fixed {
+		return "", fmt.Errorf("%s observes daylight saving time; load the MySQL time zone tables (mysql_tzinfo_to_sql) before migrating", legacy)
+	}
+
+	sign := "+"
+	if offset < 0 {
+		sign = "-"
+		offset = -offset
+	}
+	return fmt.Sprintf("%s%02d:%02d", sign, offset/3600, offset%3600/60), nil
+}
+
+// fixedOffset returns the UTC offset of a zone in seconds and whether the zone
+// kept it in winter and summer over the last twenty years, the period the
+// stored data can come from.
+func fixedOffset(loc *time.Location) (int, bool) {
+	now := time.Now()
+	_, offset := now.In(loc).Zone()
+	for year := now.Year() - 20; year <= now.Year(); year++ {
+		for _, month := range []time.Month{time.January, time.July} {
+			if _, o := time.Date(year, month, 1, 0, 0, 0, 0, loc).Zone(); o != offset {
+				return offset, false
+			}
+		}
+	}
+	return offset, true
+}
+
+// legacyInstant combines a legacy event date and time, both read back as wall
+// clock values of the legacy zone, into a UTC instant.
+func legacyInstant(date, clock time.Time, legacy *time.Location) time.Time {
+	day := time.Date(date.Year(), date.Month(), date.Day(), date.Hour(), date.Minute(), date.Second(), 0, legacy).UTC()
+	return time.Date(day.Year(), day.Month(), day.Day(), clock.Hour(), clock.Minute(), 0, 0, legacy).UTC()
 }
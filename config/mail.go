@@ -0,0 +1,23 @@
+package config
+
+import (
+	"log"
+	"ticert/utils/mail"
+)
+
+var Mailer mail.Mailer
+
+func InitMailer(cfg *Config) {
+	switch cfg.MailDriver {
+	case "smtp":
+		Mailer = mail.NewSMTP(cfg.SMTPHost, cfg.SMTPPort, cfg.SMTPUsername, cfg.SMTPPassword, cfg.MailFrom)
+		log.Printf("Sending mail through %s:%s", cfg.SMTPHost, cfg.SMTPPort)
+	default:
+		Mailer = mail.NewLog()
+		log.Println("Mail is written to the log; set MAIL_DRIVER=smtp to deliver it")
+	}
+}
+
+func GetMailer() mail.Mailer {
+	return Mailer
+}
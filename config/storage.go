@@ -0,0 +1,32 @@
+package config
+
+import (
+	"log"
+	"strconv"
+	"ticert/utils/storage"
+)
+
+// LocalMediaPath is the route local storage files are served under.
+const LocalMediaPath = "/media"
+
+var Storage storage.Storage
+
+func InitStorage(cfg *Config) {
+	switch cfg.StorageDriver {
+	case "s3":
+		pathStyle, _ := strconv.ParseBool(cfg.S3PathStyle)
+		Storage = storage.NewS3(cfg.S3Endpoint, cfg.S3Region, cfg.S3Bucket, cfg.S3AccessKey, cfg.S3SecretKey, pathStyle, cfg.StoragePublicURL)
+		log.Printf("Using S3 storage bucket %s at %s", cfg.S3Bucket, cfg.S3Endpoint)
+	default:
+		publicURL := cfg.StoragePublicURL
+		if publicURL == "" {
+			publicURL = LocalMediaPath
+		}
+		Storage = storage.NewLocal(cfg.StorageLocalDir, publicURL)
+		log.Printf("Using local storage in %s", cfg.StorageLocalDir)
+	}
+}
+
+func GetStorage() storage.Storage {
+	return Storage
+}
@@ -0,0 +1,141 @@
+package controller
+
+import (
+	"net/http"
+	"ticert/dto/request"
+	"ticert/service"
+	"ticert/utils/errs"
+	"ticert/utils/response"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+type AnalyticsController struct {
+	analyticsService service.AnalyticsService
+}
+
+func NewAnalyticsController(analyticsService service.AnalyticsService) *AnalyticsController {
+	return &AnalyticsController{analyticsService: analyticsService}
+}
+
+func (h *AnalyticsController) GetSalesSeries(ctx *gin.Context) {
+	var req request.SalesSeriesRequest
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		response.BuildErrorResponse(ctx, errs.ErrBadRequest)
+		return
+	}
+
+	series, validationErrors, err := h.analyticsService.GetSalesSeries(ctx, &req)
+	if validationErrors != nil {
+		response.BuildValidationErrorResponse(ctx, validationErrors)
+		return
+	}
+
+	if err != nil {
+		response.BuildErrorResponse(ctx, err)
+		return
+	}
+
+	response.BuildSuccessResponse(ctx, http.StatusOK, "Sales series generated successfully", series, nil)
+}
+
+func (h *AnalyticsController) GetFunnelReport(ctx *gin.Context) {
+	var req request.FunnelReportRequest
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		response.BuildErrorResponse(ctx, errs.ErrBadRequest)
+		return
+	}
+
+	funnel, validationErrors, err := h.analyticsService.GetFunnelReport(ctx, &req)
+	if validationErrors != nil {
+		response.BuildValidationErrorResponse(ctx, validationErrors)
+		return
+	}
+
+	if err != nil {
+		response.BuildErrorResponse(ctx, err)
+		return
+	}
+
+	response.BuildSuccessResponse(ctx, http.StatusOK, "Funnel report generated successfully", funnel, nil)
+}
+
+func (h *AnalyticsController) GetTopCustomers(ctx *gin.Context) {
+	var req request.TopCustomersRequest
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		response.BuildErrorResponse(ctx, errs.ErrBadRequest)
+		return
+	}
+
+	customers, validationErrors, err := h.analyticsService.GetTopCustomers(ctx, &req)
+	if validationErrors != nil {
+		response.BuildValidationErrorResponse(ctx, validationErrors)
+		return
+	}
+
+	if err != nil {
+		response.BuildErrorResponse(ctx, err)
+		return
+	}
+
+	response.BuildSuccessResponse(ctx, http.StatusOK, "Top customers retrieved successfully", customers, nil)
+}
+
+func (h *AnalyticsController) GetRepeatPurchases(ctx *gin.Context) {
+	var req request.RepeatPurchaseRequest
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		response.BuildErrorResponse(ctx, errs.ErrBadRequest)
+		return
+	}
+
+	repeat, validationErrors, err := h.analyticsService.GetRepeatPurchases(ctx, &req)
+	if validationErrors != nil {
+		response.BuildValidationErrorResponse(ctx, validationErrors)
+		return
+	}
+
+	if err != nil {
+		response.BuildErrorResponse(ctx, err)
+		return
+	}
+
+	response.BuildSuccessResponse(ctx, http.StatusOK, "Repeat purchase rate generated successfully", repeat, nil)
+}
+
+func (h *AnalyticsController) GetEventBuyers(ctx *gin.Context) {
+	var req request.EventBuyersRequest
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		response.BuildErrorResponse(ctx, errs.ErrBadRequest)
+		return
+	}
+
+	events, validationErrors, err := h.analyticsService.GetEventBuyers(ctx, &req)
+	if validationErrors != nil {
+		response.BuildValidationErrorResponse(ctx, validationErrors)
+		return
+	}
+
+	if err != nil {
+		response.BuildErrorResponse(ctx, err)
+		return
+	}
+
+	response.BuildSuccessResponse(ctx, http.StatusOK, "Event buyers retrieved successfully", events, nil)
+}
+
+func (h *AnalyticsController) GetCustomerSummary(ctx *gin.Context) {
+	userID, err := uuid.Parse(ctx.Param("id"))
+	if err != nil {
+		response.BuildErrorResponse(ctx, errs.ErrBadRequest)
+		return
+	}
+
+	summary, err := h.analyticsService.GetCustomerSummary(ctx, userID)
+	if err != nil {
+		response.BuildErrorResponse(ctx, err)
+		return
+	}
+
+	response.BuildSuccessResponse(ctx, http.StatusOK, "Customer summary retrieved successfully", summary, nil)
+}
@@ -0,0 +1,101 @@
+package controller
+
+import (
+	"crypto/sha1"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"ticert/dto/request"
+	"ticert/service"
+	"ticert/utils/errs"
+	"ticert/utils/response"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+type CatalogueController struct {
+	catalogueService service.CatalogueService
+}
+
+func NewCatalogueController(catalogueService service.CatalogueService) *CatalogueController {
+	return &CatalogueController{catalogueService: catalogueService}
+}
+
+func (h *CatalogueController) GetEvents(ctx *gin.Context) {
+	var req request.GetEventsRequest
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		response.BuildErrorResponse(ctx, errs.ErrBadRequest)
+		return
+	}
+
+	events, validationErrors, err := h.catalogueService.GetEvents(ctx, &req)
+	if validationErrors != nil {
+		response.BuildValidationErrorResponse(ctx, validationErrors)
+		return
+	}
+
+	if err != nil {
+		response.BuildErrorResponse(ctx, err)
+		return
+	}
+
+	buildCachedResponse(ctx, "Events fetched successfully", events)
+}
+
+func (h *CatalogueController) GetEvent(ctx *gin.Context) {
+	id, err := uuid.Parse(ctx.Param("id"))
+	if err != nil {
+		response.BuildErrorResponse(ctx, errs.ErrBadRequest)
+		return
+	}
+
+	event, err := h.catalogueService.GetEvent(ctx, id)
+	if err != nil {
+		response.BuildErrorResponse(ctx, err)
+		return
+	}
+
+	buildCachedResponse(ctx, "Event fetched successfully", event)
+}
+
+func (h *CatalogueController) GetCategory(ctx *gin.Context) {
+	id, err := uuid.Parse(ctx.Param("id"))
+	if err != nil {
+		response.BuildErrorResponse(ctx, errs.ErrBadRequest)
+		return
+	}
+
+	category, err := h.catalogueService.GetCategory(ctx, id)
+	if err != nil {
+		response.BuildErrorResponse(ctx, err)
+		return
+	}
+
+	buildCachedResponse(ctx, "Category fetched successfully", category)
+}
+
+// buildCachedResponse sends a success response that clients and shared
+// caches may keep for the catalogue TTL. The ETag is derived from the data,
+// so a matching If-None-Match gets a 304 without a body.
+func buildCachedResponse(ctx *gin.Context, message string, data interface{}) {
+	payload, err := json.Marshal(data)
+	if err != nil {
+		response.BuildErrorResponse(ctx, errs.ErrInternalServerError)
+		return
+	}
+
+	sum := sha1.Sum(payload)
+	etag := `"` + hex.EncodeToString(sum[:]) + `"`
+
+	ctx.Header("Cache-Control", fmt.Sprintf("public, max-age=%d", int(service.CatalogueCacheTTL.Seconds())))
+	ctx.Header("ETag", etag)
+
+	if ctx.GetHeader("If-None-Match") == etag {
+		ctx.Status(http.StatusNotModified)
+		return
+	}
+
+	response.BuildSuccessResponse(ctx, http.StatusOK, message, data, nil)
+}
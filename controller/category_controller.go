@@ -117,3 +117,48 @@ func (h *CategoryController) DeleteCategory(ctx *gin.Context) {
 
 	response.BuildSuccessResponse(ctx, http.StatusOK, "Category deleted successfully", nil, nil)
 }
+
+func (h *CategoryController) GetPriceTiers(ctx *gin.Context) {
+	idParam := ctx.Param("id")
+	id, err := uuid.Parse(idParam)
+	if err != nil {
+		response.BuildErrorResponse(ctx, err)
+		return
+	}
+
+	tiers, err := h.categoryService.GetPriceTiers(ctx, id)
+	if err != nil {
+		response.BuildErrorResponse(ctx, err)
+		return
+	}
+
+	response.BuildSuccessResponse(ctx, http.StatusOK, "Price tiers retrieved successfully", tiers, nil)
+}
+
+func (h *CategoryController) UpdatePriceTiers(ctx *gin.Context) {
+	idParam := ctx.Param("id")
+	id, err := uuid.Parse(idParam)
+	if err != nil {
+		response.BuildErrorResponse(ctx, err)
+		return
+	}
+
+	var req request.UpdatePriceTiersRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		response.BuildErrorResponse(ctx, errs.ErrBadRequest)
+		return
+	}
+
+	tiers, validationErrors, err := h.categoryService.UpdatePriceTiers(ctx, id, &req)
+	if validationErrors != nil {
+		response.BuildValidationErrorResponse(ctx, validationErrors)
+		return
+	}
+
+	if err != nil {
+		response.BuildErrorResponse(ctx, err)
+		return
+	}
+
+	response.BuildSuccessResponse(ctx, http.StatusOK, "Price tiers updated successfully", tiers, nil)
+}
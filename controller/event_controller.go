@@ -120,3 +120,61 @@ func (h *EventController) DeleteEvent(ctx *gin.Context) {
 
 	response.BuildSuccessResponse(ctx, http.StatusOK, "Event deleted successfully", nil, nil)
 }
+
+func (h *EventController) CancelEvent(ctx *gin.Context) {
+	idParam := ctx.Param("id")
+	id, err := uuid.Parse(idParam)
+	if err != nil {
+		response.BuildErrorResponse(ctx, errs.ErrBadRequest)
+		return
+	}
+
+	var req request.CancelEventRequest
+	if ctx.Request.ContentLength > 0 {
+		if err := ctx.ShouldBindJSON(&req); err != nil {
+			response.BuildErrorResponse(ctx, errs.ErrBadRequest)
+			return
+		}
+	}
+
+	eventResponse, validationErrors, err := h.eventService.CancelEvent(ctx, id, &req)
+	if validationErrors != nil {
+		response.BuildValidationErrorResponse(ctx, validationErrors)
+		return
+	}
+
+	if err != nil {
+		response.BuildErrorResponse(ctx, err)
+		return
+	}
+
+	response.BuildSuccessResponse(ctx, http.StatusOK, "Event cancelled successfully", eventResponse, nil)
+}
+
+func (h *EventController) CloneEvent(ctx *gin.Context) {
+	idParam := ctx.Param("id")
+	id, err := uuid.Parse(idParam)
+	if err != nil {
+		response.BuildErrorResponse(ctx, errs.ErrBadRequest)
+		return
+	}
+
+	var req request.CloneEventRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		response.BuildErrorResponse(ctx, errs.ErrBadRequest)
+		return
+	}
+
+	eventResponse, validationErrors, err := h.eventService.CloneEvent(ctx, id, &req)
+	if validationErrors != nil {
+		response.BuildValidationErrorResponse(ctx, validationErrors)
+		return
+	}
+
+	if err != nil {
+		response.BuildErrorResponse(ctx, err)
+		return
+	}
+
+	response.BuildSuccessResponse(ctx, http.StatusCreated, "Event cloned successfully", eventResponse, nil)
+}
@@ -0,0 +1,116 @@
+package controller
+
+import (
+	"errors"
+	"fmt"
+	"mime/multipart"
+	"net/http"
+	"ticert/service"
+	"ticert/utils/errs"
+	"ticert/utils/response"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// maxUploadRequestSize leaves room for the multipart framing around the file.
+const maxUploadRequestSize = service.MaxImageSize + 1<<20
+
+type EventMediaController struct {
+	eventMediaService service.EventMediaService
+}
+
+func NewEventMediaController(eventMediaService service.EventMediaService) *EventMediaController {
+	return &EventMediaController{eventMediaService: eventMediaService}
+}
+
+func (h *EventMediaController) UploadPoster(ctx *gin.Context) {
+	eventID, err := uuid.Parse(ctx.Param("id"))
+	if err != nil {
+		response.BuildErrorResponse(ctx, errs.ErrBadRequest)
+		return
+	}
+
+	file, validationErrors := imageFile(ctx)
+	if validationErrors != nil {
+		response.BuildValidationErrorResponse(ctx, validationErrors)
+		return
+	}
+
+	imageResponse, validationErrors, err := h.eventMediaService.UploadPoster(ctx, eventID, file)
+	if validationErrors != nil {
+		response.BuildValidationErrorResponse(ctx, validationErrors)
+		return
+	}
+
+	if err != nil {
+		response.BuildErrorResponse(ctx, err)
+		return
+	}
+
+	response.BuildSuccessResponse(ctx, http.StatusCreated, "Event poster uploaded successfully", imageResponse, nil)
+}
+
+func (h *EventMediaController) UploadGalleryImage(ctx *gin.Context) {
+	eventID, err := uuid.Parse(ctx.Param("id"))
+	if err != nil {
+		response.BuildErrorResponse(ctx, errs.ErrBadRequest)
+		return
+	}
+
+	file, validationErrors := imageFile(ctx)
+	if validationErrors != nil {
+		response.BuildValidationErrorResponse(ctx, validationErrors)
+		return
+	}
+
+	imageResponse, validationErrors, err := h.eventMediaService.UploadGalleryImage(ctx, eventID, file)
+	if validationErrors != nil {
+		response.BuildValidationErrorResponse(ctx, validationErrors)
+		return
+	}
+
+	if err != nil {
+		response.BuildErrorResponse(ctx, err)
+		return
+	}
+
+	response.BuildSuccessResponse(ctx, http.StatusCreated, "Event image uploaded successfully", imageResponse, nil)
+}
+
+func (h *EventMediaController) DeleteImage(ctx *gin.Context) {
+	eventID, err := uuid.Parse(ctx.Param("id"))
+	if err != nil {
+		response.BuildErrorResponse(ctx, errs.ErrBadRequest)
+		return
+	}
+
+	imageID, err := uuid.Parse(ctx.Param("imageId"))
+	if err != nil {
+		response.BuildErrorResponse(ctx, errs.ErrBadRequest)
+		return
+	}
+
+	if err := h.eventMediaService.DeleteImage(ctx, eventID, imageID); err != nil {
+		response.BuildErrorResponse(ctx, err)
+		return
+	}
+
+	response.BuildSuccessResponse(ctx, http.StatusOK, "Event image deleted successfully", nil, nil)
+}
+
+// imageFile reads the "file" field of a multipart upload, rejecting request
+// bodies larger than an image may be before they are buffered.
+func imageFile(ctx *gin.Context) (*multipart.FileHeader, map[string]string) {
+	ctx.Request.Body = http.MaxBytesReader(ctx.Writer, ctx.Request.Body, maxUploadRequestSize)
+
+	file, err := ctx.FormFile("file")
+	if err != nil {
+		var maxBytesErr *http.MaxBytesError
+		if errors.As(err, &maxBytesErr) {
+			return nil, map[string]string{"file": fmt.Sprintf("File must be at most %d MB", service.MaxImageSize>>20)}
+		}
+		return nil, map[string]string{"file": "File is required"}
+	}
+	return file, nil
+}
@@ -0,0 +1,58 @@
+package controller
+
+import (
+	"net/http"
+	"ticert/dto/request"
+	"ticert/service"
+	"ticert/utils/errs"
+	"ticert/utils/response"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+type EventSeriesController struct {
+	eventSeriesService service.EventSeriesService
+}
+
+func NewEventSeriesController(eventSeriesService service.EventSeriesService) *EventSeriesController {
+	return &EventSeriesController{eventSeriesService: eventSeriesService}
+}
+
+func (h *EventSeriesController) CreateSeries(ctx *gin.Context) {
+	var req request.CreateEventSeriesRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		response.BuildErrorResponse(ctx, errs.ErrBadRequest)
+		return
+	}
+
+	series, validationErrors, err := h.eventSeriesService.CreateSeries(ctx, &req)
+	if validationErrors != nil {
+		response.BuildValidationErrorResponse(ctx, validationErrors)
+		return
+	}
+
+	if err != nil {
+		response.BuildErrorResponse(ctx, err)
+		return
+	}
+
+	response.BuildSuccessResponse(ctx, http.StatusCreated, "Event series created successfully", series, nil)
+}
+
+func (h *EventSeriesController) GetSeries(ctx *gin.Context) {
+	idParam := ctx.Param("id")
+	id, err := uuid.Parse(idParam)
+	if err != nil {
+		response.BuildErrorResponse(ctx, errs.ErrBadRequest)
+		return
+	}
+
+	series, err := h.eventSeriesService.GetSeries(ctx, id)
+	if err != nil {
+		response.BuildErrorResponse(ctx, err)
+		return
+	}
+
+	response.BuildSuccessResponse(ctx, http.StatusOK, "Event series fetched successfully", series, nil)
+}
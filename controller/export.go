@@ -0,0 +1,69 @@
+package controller
+
+import (
+	"fmt"
+	"log"
+	"net/http"
+	"ticert/utils/errs"
+	"ticert/utils/export"
+	"ticert/utils/response"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// exportResponse streams an export file to the client. The download headers
+// are only sent with the first write, so a request that fails before any
+// row is written can still be answered with a JSON error.
+type exportResponse struct {
+	ctx      *gin.Context
+	format   string
+	filename string
+	started  bool
+}
+
+func newExportResponse(ctx *gin.Context, format, name string) *exportResponse {
+	return &exportResponse{
+		ctx:      ctx,
+		format:   format,
+		filename: fmt.Sprintf("%s-%s.%s", name, time.Now().Format("20060102-150405"), format),
+	}
+}
+
+func (e *exportResponse) Write(p []byte) (int, error) {
+	if !e.started {
+		e.started = true
+		header := e.ctx.Writer.Header()
+		header.Set("Content-Type", export.ContentType(e.format))
+		header.Set("Content-Disposition", fmt.Sprintf(`attachment; filename="%s"`, e.filename))
+		header.Set("Cache-Control", "no-store")
+		e.ctx.Status(http.StatusOK)
+	}
+	return e.ctx.Writer.Write(p)
+}
+
+// finish answers an export request once the service has returned. Errors
+// after the download started can only be logged, as the status is already
+// sent; the client sees a truncated file.
+func (e *exportResponse) finish(validationErrors map[string]string, err error) {
+	if validationErrors != nil {
+		response.BuildValidationErrorResponse(e.ctx, validationErrors)
+		return
+	}
+
+	if err != nil {
+		log.Printf("export %s failed: %v", e.filename, err)
+		if !e.started {
+			response.BuildErrorResponse(e.ctx, errs.ErrInternalServerError)
+			return
+		}
+		e.ctx.Abort()
+		return
+	}
+
+	e.ctx.Writer.Flush()
+}
+
+func isExportFormat(format string) bool {
+	return format == "csv" || format == "xlsx"
+}
@@ -81,6 +81,12 @@ func (h *OrderController) GetOrdersAdmin(ctx *gin.Context) {
 		return
 	}
 
+	if isExportFormat(req.Format) {
+		out := newExportResponse(ctx, req.Format, "orders")
+		out.finish(h.orderService.ExportOrdersAdmin(ctx, &req, out))
+		return
+	}
+
 	orders, validationErrors, err := h.orderService.GetOrdersAdmin(ctx, &req)
 	if validationErrors != nil {
 		response.BuildValidationErrorResponse(ctx, validationErrors)
@@ -162,3 +168,18 @@ func (h *OrderController) VerifyTicket(ctx *gin.Context) {
 
 	response.BuildSuccessResponse(ctx, http.StatusOK, "Ticket redeemed successfully", nil, nil)
 }
+
+func (h *OrderController) RefundOrder(ctx *gin.Context) {
+	orderID, err := uuid.Parse(ctx.Param("id"))
+	if err != nil {
+		response.BuildErrorResponse(ctx, errs.ErrBadRequest)
+		return
+	}
+
+	if err := h.orderService.RefundOrder(ctx, orderID); err != nil {
+		response.BuildErrorResponse(ctx, err)
+		return
+	}
+
+	response.BuildSuccessResponse(ctx, http.StatusOK, "Order refunded successfully", nil, nil)
+}
@@ -8,6 +8,7 @@ import (
 	"ticert/utils/response"
 
 	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
 )
 
 type ReportController struct {
@@ -46,6 +47,12 @@ func (h *ReportController) GetReportList(ctx *gin.Context) {
 		return
 	}
 
+	if isExportFormat(req.Format) {
+		out := newExportResponse(ctx, req.Format, "reports")
+		out.finish(h.reportService.ExportReportList(ctx, &req, out))
+		return
+	}
+
 	reports, validationErrors, err := h.reportService.GetReportList(ctx, &req)
 	if validationErrors != nil {
 		response.BuildValidationErrorResponse(ctx, validationErrors)
@@ -59,3 +66,78 @@ func (h *ReportController) GetReportList(ctx *gin.Context) {
 
 	response.BuildSuccessResponse(ctx, http.StatusOK, "Reports retrieved successfully", reports, nil)
 }
+
+func (h *ReportController) GetAttendanceReport(ctx *gin.Context) {
+	var req request.AttendanceReportRequest
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		response.BuildErrorResponse(ctx, errs.ErrBadRequest)
+		return
+	}
+
+	reports, validationErrors, err := h.reportService.GetAttendanceReport(ctx, &req)
+	if validationErrors != nil {
+		response.BuildValidationErrorResponse(ctx, validationErrors)
+		return
+	}
+
+	if err != nil {
+		response.BuildErrorResponse(ctx, err)
+		return
+	}
+
+	response.BuildSuccessResponse(ctx, http.StatusOK, "Attendance report retrieved successfully", reports, nil)
+}
+
+func (h *ReportController) GetCheckInTimeline(ctx *gin.Context) {
+	eventID, err := uuid.Parse(ctx.Param("id"))
+	if err != nil {
+		response.BuildErrorResponse(ctx, errs.ErrBadRequest)
+		return
+	}
+
+	var req request.CheckInTimelineRequest
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		response.BuildErrorResponse(ctx, errs.ErrBadRequest)
+		return
+	}
+
+	timeline, validationErrors, err := h.reportService.GetCheckInTimeline(ctx, eventID, &req)
+	if validationErrors != nil {
+		response.BuildValidationErrorResponse(ctx, validationErrors)
+		return
+	}
+
+	if err != nil {
+		response.BuildErrorResponse(ctx, err)
+		return
+	}
+
+	response.BuildSuccessResponse(ctx, http.StatusOK, "Check-in timeline retrieved successfully", timeline, nil)
+}
+
+func (h *ReportController) GetArrivalReport(ctx *gin.Context) {
+	eventID, err := uuid.Parse(ctx.Param("id"))
+	if err != nil {
+		response.BuildErrorResponse(ctx, errs.ErrBadRequest)
+		return
+	}
+
+	var req request.ArrivalReportRequest
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		response.BuildErrorResponse(ctx, errs.ErrBadRequest)
+		return
+	}
+
+	arrivals, validationErrors, err := h.reportService.GetArrivalReport(ctx, eventID, &req)
+	if validationErrors != nil {
+		response.BuildValidationErrorResponse(ctx, validationErrors)
+		return
+	}
+
+	if err != nil {
+		response.BuildErrorResponse(ctx, err)
+		return
+	}
+
+	response.BuildSuccessResponse(ctx, http.StatusOK, "Arrival report retrieved successfully", arrivals, nil)
+}
@@ -0,0 +1,154 @@
+package controller
+
+import (
+	"net/http"
+	"ticert/dto/request"
+	"ticert/service"
+	"ticert/utils/auth"
+	"ticert/utils/errs"
+	"ticert/utils/response"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+type ReportSubscriptionController struct {
+	reportSubscriptionService service.ReportSubscriptionService
+}
+
+func NewReportSubscriptionController(reportSubscriptionService service.ReportSubscriptionService) *ReportSubscriptionController {
+	return &ReportSubscriptionController{reportSubscriptionService: reportSubscriptionService}
+}
+
+func (h *ReportSubscriptionController) CreateSubscription(ctx *gin.Context) {
+	userCtx, err := auth.GetUserContextKey(ctx)
+	if err != nil {
+		response.BuildErrorResponse(ctx, err)
+		return
+	}
+
+	var req request.CreateReportSubscriptionRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		response.BuildErrorResponse(ctx, errs.ErrBadRequest)
+		return
+	}
+
+	subscription, validationErrors, err := h.reportSubscriptionService.CreateSubscription(ctx, &req, userCtx.UserID)
+	if validationErrors != nil {
+		response.BuildValidationErrorResponse(ctx, validationErrors)
+		return
+	}
+
+	if err != nil {
+		response.BuildErrorResponse(ctx, err)
+		return
+	}
+
+	response.BuildSuccessResponse(ctx, http.StatusCreated, "Report subscription created successfully", subscription, nil)
+}
+
+func (h *ReportSubscriptionController) GetSubscriptions(ctx *gin.Context) {
+	var req request.GetReportSubscriptionsRequest
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		response.BuildErrorResponse(ctx, errs.ErrBadRequest)
+		return
+	}
+
+	subscriptions, validationErrors, err := h.reportSubscriptionService.GetSubscriptions(ctx, &req)
+	if validationErrors != nil {
+		response.BuildValidationErrorResponse(ctx, validationErrors)
+		return
+	}
+
+	if err != nil {
+		response.BuildErrorResponse(ctx, err)
+		return
+	}
+
+	response.BuildSuccessResponse(ctx, http.StatusOK, "Report subscriptions retrieved successfully", subscriptions, nil)
+}
+
+func (h *ReportSubscriptionController) GetSubscriptionByID(ctx *gin.Context) {
+	id, err := uuid.Parse(ctx.Param("id"))
+	if err != nil {
+		response.BuildErrorResponse(ctx, errs.ErrBadRequest)
+		return
+	}
+
+	subscription, err := h.reportSubscriptionService.GetSubscriptionByID(ctx, id)
+	if err != nil {
+		response.BuildErrorResponse(ctx, err)
+		return
+	}
+
+	response.BuildSuccessResponse(ctx, http.StatusOK, "Report subscription retrieved successfully", subscription, nil)
+}
+
+func (h *ReportSubscriptionController) UpdateSubscription(ctx *gin.Context) {
+	id, err := uuid.Parse(ctx.Param("id"))
+	if err != nil {
+		response.BuildErrorResponse(ctx, errs.ErrBadRequest)
+		return
+	}
+
+	var req request.UpdateReportSubscriptionRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		response.BuildErrorResponse(ctx, errs.ErrBadRequest)
+		return
+	}
+
+	subscription, validationErrors, err := h.reportSubscriptionService.UpdateSubscription(ctx, id, &req)
+	if validationErrors != nil {
+		response.BuildValidationErrorResponse(ctx, validationErrors)
+		return
+	}
+
+	if err != nil {
+		response.BuildErrorResponse(ctx, err)
+		return
+	}
+
+	response.BuildSuccessResponse(ctx, http.StatusOK, "Report subscription updated successfully", subscription, nil)
+}
+
+func (h *ReportSubscriptionController) DeleteSubscription(ctx *gin.Context) {
+	id, err := uuid.Parse(ctx.Param("id"))
+	if err != nil {
+		response.BuildErrorResponse(ctx, errs.ErrBadRequest)
+		return
+	}
+
+	if err := h.reportSubscriptionService.DeleteSubscription(ctx, id); err != nil {
+		response.BuildErrorResponse(ctx, err)
+		return
+	}
+
+	response.BuildSuccessResponse(ctx, http.StatusOK, "Report subscription deleted successfully", nil, nil)
+}
+
+func (h *ReportSubscriptionController) GetRuns(ctx *gin.Context) {
+	id, err := uuid.Parse(ctx.Param("id"))
+	if err != nil {
+		response.BuildErrorResponse(ctx, errs.ErrBadRequest)
+		return
+	}
+
+	var req request.GetReportRunsRequest
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		response.BuildErrorResponse(ctx, errs.ErrBadRequest)
+		return
+	}
+
+	runs, validationErrors, err := h.reportSubscriptionService.GetRuns(ctx, id, &req)
+	if validationErrors != nil {
+		response.BuildValidationErrorResponse(ctx, validationErrors)
+		return
+	}
+
+	if err != nil {
+		response.BuildErrorResponse(ctx, err)
+		return
+	}
+
+	response.BuildSuccessResponse(ctx, http.StatusOK, "Report runs retrieved successfully", runs, nil)
+}
@@ -0,0 +1,125 @@
+package controller
+
+import (
+	"net/http"
+	"ticert/dto/request"
+	"ticert/service"
+	"ticert/utils/errs"
+	"ticert/utils/response"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+type SeatController struct {
+	seatService service.SeatService
+}
+
+func NewSeatController(seatService service.SeatService) *SeatController {
+	return &SeatController{seatService: seatService}
+}
+
+func (h *SeatController) CreateSeatMap(ctx *gin.Context) {
+	var req request.CreateSeatMapRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		response.BuildErrorResponse(ctx, errs.ErrBadRequest)
+		return
+	}
+
+	seatMap, validationErrors, err := h.seatService.CreateSeatMap(ctx, &req)
+	if validationErrors != nil {
+		response.BuildValidationErrorResponse(ctx, validationErrors)
+		return
+	}
+
+	if err != nil {
+		response.BuildErrorResponse(ctx, err)
+		return
+	}
+
+	response.BuildSuccessResponse(ctx, http.StatusCreated, "Seat map created successfully", seatMap, nil)
+}
+
+func (h *SeatController) GetSeatMaps(ctx *gin.Context) {
+	seatMaps, err := h.seatService.GetSeatMaps(ctx)
+	if err != nil {
+		response.BuildErrorResponse(ctx, err)
+		return
+	}
+
+	response.BuildSuccessResponse(ctx, http.StatusOK, "Seat maps retrieved successfully", seatMaps, nil)
+}
+
+func (h *SeatController) GetSeatMapByID(ctx *gin.Context) {
+	id, err := uuid.Parse(ctx.Param("id"))
+	if err != nil {
+		response.BuildErrorResponse(ctx, errs.ErrBadRequest)
+		return
+	}
+
+	seatMap, err := h.seatService.GetSeatMapByID(ctx, id)
+	if err != nil {
+		response.BuildErrorResponse(ctx, err)
+		return
+	}
+
+	response.BuildSuccessResponse(ctx, http.StatusOK, "Seat map retrieved successfully", seatMap, nil)
+}
+
+func (h *SeatController) DeleteSeatMap(ctx *gin.Context) {
+	id, err := uuid.Parse(ctx.Param("id"))
+	if err != nil {
+		response.BuildErrorResponse(ctx, errs.ErrBadRequest)
+		return
+	}
+
+	if err := h.seatService.DeleteSeatMap(ctx, id); err != nil {
+		response.BuildErrorResponse(ctx, err)
+		return
+	}
+
+	response.BuildSuccessResponse(ctx, http.StatusOK, "Seat map deleted successfully", nil, nil)
+}
+
+func (h *SeatController) AssignCategorySeats(ctx *gin.Context) {
+	categoryID, err := uuid.Parse(ctx.Param("id"))
+	if err != nil {
+		response.BuildErrorResponse(ctx, errs.ErrBadRequest)
+		return
+	}
+
+	var req request.AssignCategorySeatsRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		response.BuildErrorResponse(ctx, errs.ErrBadRequest)
+		return
+	}
+
+	category, validationErrors, err := h.seatService.AssignCategorySeats(ctx, categoryID, &req)
+	if validationErrors != nil {
+		response.BuildValidationErrorResponse(ctx, validationErrors)
+		return
+	}
+
+	if err != nil {
+		response.BuildErrorResponse(ctx, err)
+		return
+	}
+
+	response.BuildSuccessResponse(ctx, http.StatusOK, "Category seats updated successfully", category, nil)
+}
+
+func (h *SeatController) GetEventSeats(ctx *gin.Context) {
+	eventID, err := uuid.Parse(ctx.Param("id"))
+	if err != nil {
+		response.BuildErrorResponse(ctx, errs.ErrBadRequest)
+		return
+	}
+
+	seats, err := h.seatService.GetEventSeats(ctx, eventID)
+	if err != nil {
+		response.BuildErrorResponse(ctx, err)
+		return
+	}
+
+	response.BuildSuccessResponse(ctx, http.StatusOK, "Seats retrieved successfully", seats, nil)
+}
@@ -0,0 +1,97 @@
+package controller
+
+import (
+	"net/http"
+	"ticert/dto/request"
+	"ticert/service"
+	"ticert/utils/auth"
+	"ticert/utils/errs"
+	"ticert/utils/response"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+type WaitlistController struct {
+	waitlistService service.WaitlistService
+}
+
+func NewWaitlistController(waitlistService service.WaitlistService) *WaitlistController {
+	return &WaitlistController{waitlistService: waitlistService}
+}
+
+func (h *WaitlistController) JoinWaitlist(ctx *gin.Context) {
+	userCtx, err := auth.GetUserContextKey(ctx)
+	if err != nil {
+		response.BuildErrorResponse(ctx, err)
+		return
+	}
+
+	categoryID, err := uuid.Parse(ctx.Param("id"))
+	if err != nil {
+		response.BuildErrorResponse(ctx, errs.ErrBadRequest)
+		return
+	}
+
+	var req request.JoinWaitlistRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		response.BuildErrorResponse(ctx, errs.ErrBadRequest)
+		return
+	}
+
+	entry, validationErrors, err := h.waitlistService.JoinWaitlist(ctx, categoryID, userCtx.UserID, &req)
+	if validationErrors != nil {
+		response.BuildValidationErrorResponse(ctx, validationErrors)
+		return
+	}
+
+	if err != nil {
+		response.BuildErrorResponse(ctx, err)
+		return
+	}
+
+	response.BuildSuccessResponse(ctx, http.StatusCreated, "Joined waitlist successfully", entry, nil)
+}
+
+func (h *WaitlistController) GetWaitlistEntry(ctx *gin.Context) {
+	userCtx, err := auth.GetUserContextKey(ctx)
+	if err != nil {
+		response.BuildErrorResponse(ctx, err)
+		return
+	}
+
+	categoryID, err := uuid.Parse(ctx.Param("id"))
+	if err != nil {
+		response.BuildErrorResponse(ctx, errs.ErrBadRequest)
+		return
+	}
+
+	entry, err := h.waitlistService.GetWaitlistEntry(ctx, categoryID, userCtx.UserID)
+	if err != nil {
+		response.BuildErrorResponse(ctx, err)
+		return
+	}
+
+	response.BuildSuccessResponse(ctx, http.StatusOK, "Waitlist entry retrieved successfully", entry, nil)
+}
+
+func (h *WaitlistController) LeaveWaitlist(ctx *gin.Context) {
+	userCtx, err := auth.GetUserContextKey(ctx)
+	if err != nil {
+		response.BuildErrorResponse(ctx, err)
+		return
+	}
+
+	categoryID, err := uuid.Parse(ctx.Param("id"))
+	if err != nil {
+		response.BuildErrorResponse(ctx, errs.ErrBadRequest)
+		return
+	}
+
+	if err := h.waitlistService.LeaveWaitlist(ctx, categoryID, userCtx.UserID); err != nil {
+		response.BuildErrorResponse(ctx, err)
+		return
+	}
+
+	response.BuildSuccessResponse(ctx, http.StatusOK, "Left waitlist successfully", nil, nil)
+}
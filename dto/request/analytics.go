@@ -0,0 +1,44 @@
+package request
+
+type SalesSeriesRequest struct {
+	StartDate  string `form:"start_date" validate:"required"`
+	EndDate    string `form:"end_date" validate:"required"`
+	Interval   string `form:"interval" validate:"omitempty,oneof=hour day week month"`
+	Timezone   string `form:"timezone" validate:"omitempty,timezone"`
+	EventID    string `form:"event_id" validate:"omitempty,uuid"`
+	CategoryID string `form:"category_id" validate:"omitempty,uuid"`
+}
+
+type FunnelReportRequest struct {
+	StartDate string `form:"start_date" validate:"required"`
+	EndDate   string `form:"end_date" validate:"required"`
+	Timezone  string `form:"timezone" validate:"omitempty,timezone"`
+	EventID   string `form:"event_id" validate:"omitempty,uuid"`
+}
+
+// The customer reports cover all paid orders unless a date range is given.
+
+type TopCustomersRequest struct {
+	StartDate string `form:"start_date" validate:"required_with=EndDate"`
+	EndDate   string `form:"end_date" validate:"required_with=StartDate"`
+	Timezone  string `form:"timezone" validate:"omitempty,timezone"`
+	EventID   string `form:"event_id" validate:"omitempty,uuid"`
+	SortBy    string `form:"sort_by" validate:"omitempty,oneof=spend tickets"`
+	Page      int    `form:"page" validate:"omitempty,min=1"`
+	Limit     int    `form:"limit" validate:"omitempty,min=1,max=100"`
+}
+
+type RepeatPurchaseRequest struct {
+	StartDate string `form:"start_date" validate:"required_with=EndDate"`
+	EndDate   string `form:"end_date" validate:"required_with=StartDate"`
+	Timezone  string `form:"timezone" validate:"omitempty,timezone"`
+}
+
+type EventBuyersRequest struct {
+	StartDate string `form:"start_date" validate:"required_with=EndDate"`
+	EndDate   string `form:"end_date" validate:"required_with=StartDate"`
+	Timezone  string `form:"timezone" validate:"omitempty,timezone"`
+	EventID   string `form:"event_id" validate:"omitempty,uuid"`
+	Page      int    `form:"page" validate:"omitempty,min=1"`
+	Limit     int    `form:"limit" validate:"omitempty,min=1,max=100"`
+}
@@ -5,16 +5,40 @@ import (
 )
 
 type CreateCategoryRequest struct {
-	EventID   uuid.UUID `json:"event_id" validate:"required,uuid"`
-	Name      string    `json:"name" validate:"required,max=255"`
-	Price     float64   `json:"price" validate:"required,min=0"`
-	Quantity  int       `json:"quantity" validate:"required,min=1"`
-	EventDate string    `json:"event_date" validate:"required"`
+	EventID           uuid.UUID `json:"event_id" validate:"required,uuid"`
+	Name              string    `json:"name" validate:"required,max=255"`
+	Price             float64   `json:"price" validate:"required,min=0"`
+	Quantity          int       `json:"quantity" validate:"required,min=1"`
+	EventDate         string    `json:"event_date" validate:"required"`
+	InventoryStrategy string    `json:"inventory_strategy" validate:"omitempty,oneof=database redis"`
+	SalesStartAt      string    `json:"sales_start_at" validate:"omitempty"`
+	SalesEndAt        string    `json:"sales_end_at" validate:"omitempty"`
+	MinPerOrder       int       `json:"min_per_order" validate:"omitempty,min=1"`
+	MaxPerOrder       int       `json:"max_per_order" validate:"omitempty,min=0"`
+	MaxPerUser        int       `json:"max_per_user" validate:"omitempty,min=0"`
 }
 
 type UpdateCategoryRequest struct {
-	Name      string  `json:"name" validate:"omitempty,max=255"`
-	Price     float64 `json:"price" validate:"omitempty,min=0"`
-	Quantity  int     `json:"quantity" validate:"omitempty,min=1"`
-	EventDate string  `json:"event_date" validate:"omitempty"`
+	Name              string  `json:"name" validate:"omitempty,max=255"`
+	Price             float64 `json:"price" validate:"omitempty,min=0"`
+	Quantity          int     `json:"quantity" validate:"omitempty,min=1"`
+	EventDate         string  `json:"event_date" validate:"omitempty"`
+	InventoryStrategy string  `json:"inventory_strategy" validate:"omitempty,oneof=database redis"`
+	SalesStartAt      *string `json:"sales_start_at" validate:"omitempty"`
+	SalesEndAt        *string `json:"sales_end_at" validate:"omitempty"`
+	MinPerOrder       *int    `json:"min_per_order" validate:"omitempty,min=1"`
+	MaxPerOrder       *int    `json:"max_per_order" validate:"omitempty,min=0"`
+	MaxPerUser        *int    `json:"max_per_user" validate:"omitempty,min=0"`
+}
+
+type PriceTierRequest struct {
+	Name      string  `json:"name" validate:"required,max=255"`
+	Price     float64 `json:"price" validate:"min=0"`
+	StartsAt  string  `json:"starts_at" validate:"omitempty"`
+	EndsAt    string  `json:"ends_at" validate:"omitempty"`
+	UnitLimit int     `json:"unit_limit" validate:"omitempty,min=0"`
+}
+
+type UpdatePriceTiersRequest struct {
+	Tiers []PriceTierRequest `json:"tiers" validate:"omitempty,max=20,dive"`
 }
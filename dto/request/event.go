@@ -1,30 +1,58 @@
 package request
 
+import "github.com/google/uuid"
+
 type CreateEventRequest struct {
-	Organizer   string `json:"organizer" validate:"required,max=255"`
-	Title       string `json:"title" validate:"required,max=255"`
-	Description string `json:"description" validate:"required,max=255"`
-	StartDate   string `json:"start_date" validate:"required"`
-	EndDate     string `json:"end_date" validate:"required"`
-	StartTime   string `json:"start_time" validate:"required"`
-	EndTime     string `json:"end_time" validate:"required"`
-	Location    string `json:"location" validate:"required,max=255"`
+	Organizer   string     `json:"organizer" validate:"required,max=255"`
+	Title       string     `json:"title" validate:"required,max=255"`
+	Description string     `json:"description" validate:"required,max=255"`
+	StartsAt    string     `json:"starts_at" validate:"required"`
+	EndsAt      string     `json:"ends_at" validate:"required"`
+	Timezone    string     `json:"timezone" validate:"required_without=VenueID,timezone"`
+	Location    string     `json:"location" validate:"required_without=VenueID,max=255"`
+	VenueID     *uuid.UUID `json:"venue_id" validate:"omitempty"`
+	SeatMapID   *uuid.UUID `json:"seat_map_id" validate:"omitempty"`
 }
 
 type UpdateEventRequest struct {
-	Organizer   string `json:"organizer" validate:"omitempty,max=255"`
-	Title       string `json:"title" validate:"omitempty,max=255"`
-	Description string `json:"description" validate:"omitempty,max=255"`
-	StartDate   string `json:"start_date" validate:"omitempty"`
-	EndDate     string `json:"end_date" validate:"omitempty"`
-	StartTime   string `json:"start_time" validate:"omitempty"`
-	EndTime     string `json:"end_time" validate:"omitempty"`
-	Location    string `json:"location" validate:"omitempty,max=255"`
+	Organizer   string     `json:"organizer" validate:"omitempty,max=255"`
+	Title       string     `json:"title" validate:"omitempty,max=255"`
+	Description string     `json:"description" validate:"omitempty,max=255"`
+	StartsAt    string     `json:"starts_at" validate:"omitempty"`
+	EndsAt      string     `json:"ends_at" validate:"omitempty"`
+	Timezone    string     `json:"timezone" validate:"omitempty,timezone"`
+	Location    string     `json:"location" validate:"omitempty,max=255"`
+	VenueID     *uuid.UUID `json:"venue_id" validate:"omitempty"`
+	SeatMapID   *uuid.UUID `json:"seat_map_id" validate:"omitempty"`
+	Status      string     `json:"status" validate:"omitempty,oneof=draft published"`
+	Scope       string     `json:"scope" validate:"omitempty,oneof=this future"`
+}
+
+type CloneEventRequest struct {
+	Title      string `json:"title" validate:"required,max=255"`
+	OffsetDays int    `json:"offset_days" validate:"omitempty"`
+}
+
+type CancelEventRequest struct {
+	Scope string `json:"scope" validate:"omitempty,oneof=this future"`
 }
 
 type GetEventsRequest struct {
-	Page    int    `form:"page" validate:"omitempty"`
-	Limit   int    `form:"limit" validate:"omitempty"`
-	Search  string `form:"search" validate:"omitempty,max=255"`
-	OrderBy string `form:"order_by" validate:"omitempty,oneof=asc desc date_asc date_desc time_asc time_desc"`
+	Page      int      `form:"page" validate:"omitempty"`
+	Limit     int      `form:"limit" validate:"omitempty"`
+	Search    string   `form:"search" validate:"omitempty,max=255"`
+	OrderBy   string   `form:"order_by" validate:"omitempty,oneof=asc desc date_asc date_desc time_asc time_desc relevance soonest cheapest popular"`
+	Organizer string   `form:"organizer" validate:"omitempty,max=255"`
+	Location  string   `form:"location" validate:"omitempty,max=255"`
+	VenueID   string   `form:"venue_id" validate:"omitempty,uuid"`
+	StartFrom string   `form:"start_from" validate:"omitempty"`
+	StartTo   string   `form:"start_to" validate:"omitempty"`
+	MinPrice  *float64 `form:"min_price" validate:"omitempty,min=0"`
+	MaxPrice  *float64 `form:"max_price" validate:"omitempty,min=0"`
+	Available bool     `form:"available" validate:"omitempty"`
+	// Tags are tag slugs, repeated or comma separated.
+	Tags []string `form:"tags" validate:"omitempty,max=20,dive,max=120"`
+
+	Pagination string `form:"pagination" validate:"omitempty,oneof=offset cursor"`
+	Cursor     string `form:"cursor" validate:"omitempty"`
 }
@@ -0,0 +1,8 @@
+package request
+
+import "github.com/google/uuid"
+
+type CreateEventSeriesRequest struct {
+	TemplateEventID uuid.UUID `json:"template_event_id" validate:"required"`
+	RRule           string    `json:"rrule" validate:"required,max=255"`
+}
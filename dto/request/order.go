@@ -3,10 +3,15 @@ package request
 import "github.com/google/uuid"
 
 type OrderRequest struct {
-	CategoryID    uuid.UUID            `json:"category_id" validate:"required,uuid"`
-	Quantity      int                  `json:"quantity" validate:"required,min=1,max=10"`
-	SameAsOrderer bool                 `json:"same_as_orderer" validate:"omitempty"`
-	OrderDetails  []OrderDetailRequest `json:"order_details" validate:"required,min=1,max=10"`
+	Items         []OrderItemRequest `json:"items" validate:"required,min=1,max=10,unique=CategoryID,dive"`
+	SameAsOrderer bool               `json:"same_as_orderer" validate:"omitempty"`
+}
+
+type OrderItemRequest struct {
+	CategoryID   uuid.UUID            `json:"category_id" validate:"required,uuid"`
+	Quantity     int                  `json:"quantity" validate:"required,min=1,max=10"`
+	OrderDetails []OrderDetailRequest `json:"order_details" validate:"required,min=1,max=10"`
+	SeatIDs      []uuid.UUID          `json:"seat_ids" validate:"omitempty,max=10,unique"`
 }
 
 type OrderDetailRequest struct {
@@ -15,14 +20,20 @@ type OrderDetailRequest struct {
 }
 
 type GetOrdersRequest struct {
-	Page  int `form:"page" validate:"omitempty,min=1"`
-	Limit int `form:"limit" validate:"omitempty,min=1"`
+	Page       int    `form:"page" validate:"omitempty,min=1"`
+	Limit      int    `form:"limit" validate:"omitempty,min=1"`
+	Pagination string `form:"pagination" validate:"omitempty,oneof=offset cursor"`
+	Cursor     string `form:"cursor" validate:"omitempty"`
 }
 
 type GetOrdersRequestAdmin struct {
 	Page    int    `form:"page" validate:"omitempty"`
 	Limit   int    `form:"limit" validate:"omitempty"`
-	Status  string `form:"status" validate:"omitempty,oneof=pending paid cancelled"`
+	Status  string `form:"status" validate:"omitempty,oneof=pending paid cancelled expired refunded"`
 	Search  string `form:"search" validate:"omitempty,max=255"`
 	OrderBy string `form:"order_by" validate:"omitempty,oneof=asc desc quantity_asc quantity_desc total_price_asc total_price_desc"`
+
+	Pagination string `form:"pagination" validate:"omitempty,oneof=offset cursor"`
+	Cursor     string `form:"cursor" validate:"omitempty"`
+	Format     string `form:"format" validate:"omitempty,oneof=json csv xlsx"`
 }
@@ -14,4 +14,25 @@ type ReportFilterRequest struct {
 	EndDate    string `form:"end_date" validate:"omitempty"`
 	Page       int    `form:"page" validate:"omitempty,min=1"`
 	Limit      int    `form:"limit" validate:"omitempty,min=1,max=100"`
+	Pagination string `form:"pagination" validate:"omitempty,oneof=offset cursor"`
+	Cursor     string `form:"cursor" validate:"omitempty"`
+	Format     string `form:"format" validate:"omitempty,oneof=json csv xlsx"`
+}
+
+type AttendanceReportRequest struct {
+	EventID    string `form:"event_id" validate:"omitempty"`
+	CategoryID string `form:"category_id" validate:"omitempty"`
+	StartDate  string `form:"start_date" validate:"omitempty"`
+	EndDate    string `form:"end_date" validate:"omitempty"`
+	Page       int    `form:"page" validate:"omitempty,min=1"`
+	Limit      int    `form:"limit" validate:"omitempty,min=1,max=100"`
+}
+
+type CheckInTimelineRequest struct {
+	CategoryID string `form:"category_id" validate:"omitempty"`
+	Interval   int    `form:"interval" validate:"omitempty,oneof=5 15 30 60 1440"`
+}
+
+type ArrivalReportRequest struct {
+	CategoryID string `form:"category_id" validate:"omitempty"`
 }
@@ -0,0 +1,32 @@
+package request
+
+type CreateReportSubscriptionRequest struct {
+	Recipient string `json:"recipient" validate:"required,email,max=255"`
+	EventID   string `json:"event_id" validate:"omitempty,uuid"`
+	Kind      string `json:"kind" validate:"omitempty,oneof=digest final"`
+	Schedule  string `json:"schedule" validate:"required,max=100"`
+	Timezone  string `json:"timezone" validate:"omitempty,timezone"`
+	Format    string `json:"format" validate:"omitempty,oneof=csv xlsx"`
+}
+
+type UpdateReportSubscriptionRequest struct {
+	Recipient string `json:"recipient" validate:"omitempty,email,max=255"`
+	// EventID changes the event the report covers. An empty string leaves it
+	// unchanged; "none" reports on all events.
+	EventID  string `json:"event_id" validate:"omitempty"`
+	Kind     string `json:"kind" validate:"omitempty,oneof=digest final"`
+	Schedule string `json:"schedule" validate:"omitempty,max=100"`
+	Timezone string `json:"timezone" validate:"omitempty,timezone"`
+	Format   string `json:"format" validate:"omitempty,oneof=csv xlsx"`
+	Active   *bool  `json:"active" validate:"omitempty"`
+}
+
+type GetReportSubscriptionsRequest struct {
+	Page  int `form:"page" validate:"omitempty,min=1"`
+	Limit int `form:"limit" validate:"omitempty,min=1,max=100"`
+}
+
+type GetReportRunsRequest struct {
+	Page  int `form:"page" validate:"omitempty,min=1"`
+	Limit int `form:"limit" validate:"omitempty,min=1,max=100"`
+}
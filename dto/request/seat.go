@@ -0,0 +1,30 @@
+package request
+
+import "github.com/google/uuid"
+
+type CreateSeatMapRequest struct {
+	VenueID     *uuid.UUID           `json:"venue_id" validate:"omitempty"`
+	Name        string               `json:"name" validate:"required,max=255"`
+	Description string               `json:"description" validate:"omitempty"`
+	Sections    []SeatSectionRequest `json:"sections" validate:"required,min=1,max=50,dive"`
+}
+
+type SeatSectionRequest struct {
+	Name string           `json:"name" validate:"required,max=255"`
+	Rows []SeatRowRequest `json:"rows" validate:"required,min=1,max=100,dive"`
+}
+
+// SeatRowRequest describes a row of consecutively numbered seats. Accessible
+// and Companion list seat numbers in the row carrying those flags.
+type SeatRowRequest struct {
+	Label       string `json:"label" validate:"required,max=10"`
+	Seats       int    `json:"seats" validate:"required,min=1,max=200"`
+	StartNumber int    `json:"start_number" validate:"omitempty,min=1"`
+	Accessible  []int  `json:"accessible" validate:"omitempty,unique"`
+	Companion   []int  `json:"companion" validate:"omitempty,unique"`
+}
+
+type AssignCategorySeatsRequest struct {
+	SectionIDs []uuid.UUID `json:"section_ids" validate:"omitempty,unique"`
+	SeatIDs    []uuid.UUID `json:"seat_ids" validate:"omitempty,unique"`
+}
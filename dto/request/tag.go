@@ -0,0 +1,23 @@
+package request
+
+type CreateTagRequest struct {
+	Name     string `json:"name" validate:"required,max=100"`
+	Slug     string `json:"slug" validate:"omitempty,max=120"`
+	ParentID string `json:"parent_id" validate:"omitempty,uuid"`
+}
+
+type UpdateTagRequest struct {
+	Name string `json:"name" validate:"omitempty,max=100"`
+	Slug string `json:"slug" validate:"omitempty,max=120"`
+	// ParentID moves the tag under another top-level tag. An empty string
+	// leaves the parent unchanged; "none" makes the tag top-level.
+	ParentID string `json:"parent_id" validate:"omitempty"`
+}
+
+type GetTagsRequest struct {
+	Search string `form:"search" validate:"omitempty,max=100"`
+}
+
+type SetEventTagsRequest struct {
+	TagIDs []string `json:"tag_ids" validate:"max=20,dive,uuid"`
+}
@@ -37,3 +37,8 @@ type UpdatePasswordRequest struct {
 type UpdateEmailRequest struct {
 	Email string `json:"email" validate:"required,email,max=100"`
 }
+
+type ResetPasswordRequest struct {
+	Email    string `json:"email" validate:"required,email,max=100"`
+	Password string `json:"password" validate:"required,min=8,max=50"`
+}
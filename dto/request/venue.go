@@ -0,0 +1,25 @@
+package request
+
+type CreateVenueRequest struct {
+	Name      string   `json:"name" validate:"required,max=255"`
+	Address   string   `json:"address" validate:"required"`
+	Latitude  *float64 `json:"latitude" validate:"omitempty,latitude"`
+	Longitude *float64 `json:"longitude" validate:"omitempty,longitude"`
+	Timezone  string   `json:"timezone" validate:"required,timezone"`
+	Capacity  int      `json:"capacity" validate:"required,min=1"`
+}
+
+type UpdateVenueRequest struct {
+	Name      string   `json:"name" validate:"omitempty,max=255"`
+	Address   string   `json:"address" validate:"omitempty"`
+	Latitude  *float64 `json:"latitude" validate:"omitempty,latitude"`
+	Longitude *float64 `json:"longitude" validate:"omitempty,longitude"`
+	Timezone  string   `json:"timezone" validate:"omitempty,timezone"`
+	Capacity  int      `json:"capacity" validate:"omitempty,min=1"`
+}
+
+type GetVenuesRequest struct {
+	Page   int    `form:"page" validate:"omitempty"`
+	Limit  int    `form:"limit" validate:"omitempty"`
+	Search string `form:"search" validate:"omitempty,max=255"`
+}
@@ -0,0 +1,5 @@
+package request
+
+type JoinWaitlistRequest struct {
+	Quantity int `json:"quantity" validate:"required,min=1"`
+}
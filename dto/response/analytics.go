@@ -0,0 +1,153 @@
+package response
+
+import (
+	"ticert/utils/response"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type SalesSeriesResponse struct {
+	Interval  string                 `json:"interval"`
+	Timezone  string                 `json:"timezone"`
+	StartDate string                 `json:"start_date"`
+	EndDate   string                 `json:"end_date"`
+	Totals    *SalesFiguresResponse  `json:"totals"`
+	Buckets   []*SalesBucketResponse `json:"buckets"`
+}
+
+type SalesFiguresResponse struct {
+	TicketsSold   int64   `json:"tickets_sold"`
+	Revenue       float64 `json:"revenue"`
+	OrdersCreated int64   `json:"orders_created"`
+	Cancellations int64   `json:"cancellations"`
+	Redemptions   int64   `json:"redemptions"`
+}
+
+type SalesBucketResponse struct {
+	Start time.Time `json:"start"`
+	SalesFiguresResponse
+}
+
+type FunnelReportResponse struct {
+	Timezone   string                    `json:"timezone"`
+	StartDate  string                    `json:"start_date"`
+	EndDate    string                    `json:"end_date"`
+	EventID    *uuid.UUID                `json:"event_id,omitempty"`
+	Totals     *FunnelFiguresResponse    `json:"totals"`
+	Categories []*CategoryFunnelResponse `json:"categories"`
+}
+
+// FunnelFiguresResponse describes what became of the orders created in the
+// period. Rates are percentages of the settled orders, those no longer
+// pending.
+type FunnelFiguresResponse struct {
+	OrdersCreated          int64    `json:"orders_created"`
+	Pending                int64    `json:"pending"`
+	Paid                   int64    `json:"paid"`
+	Refunded               int64    `json:"refunded"`
+	Cancelled              int64    `json:"cancelled"`
+	Expired                int64    `json:"expired"`
+	ConversionRate         float64  `json:"conversion_rate"`
+	CancellationRate       float64  `json:"cancellation_rate"`
+	ExpiryRate             float64  `json:"expiry_rate"`
+	AbandonmentRate        float64  `json:"abandonment_rate"`
+	MedianTimeToPaySeconds *float64 `json:"median_time_to_pay_seconds"`
+	Revenue                float64  `json:"revenue"`
+	LostRevenue            float64  `json:"lost_revenue"`
+}
+
+type CategoryFunnelResponse struct {
+	CategoryID   uuid.UUID `json:"category_id"`
+	CategoryName string    `json:"category_name"`
+	EventID      uuid.UUID `json:"event_id"`
+	EventTitle   string    `json:"event_title"`
+	FunnelFiguresResponse
+}
+
+type CustomerResponse struct {
+	UserID            uuid.UUID  `json:"user_id"`
+	Email             string     `json:"email"`
+	FirstName         string     `json:"first_name"`
+	LastName          string     `json:"last_name"`
+	Orders            int64      `json:"orders"`
+	Events            int64      `json:"events"`
+	TicketsBought     int64      `json:"tickets_bought"`
+	Spend             float64    `json:"spend"`
+	AverageOrderValue float64    `json:"average_order_value"`
+	FirstPurchaseAt   *time.Time `json:"first_purchase_at"`
+	LastPurchaseAt    *time.Time `json:"last_purchase_at"`
+}
+
+type TopCustomersResponse struct {
+	SortBy     string               `json:"sort_by"`
+	Timezone   string               `json:"timezone,omitempty"`
+	StartDate  string               `json:"start_date,omitempty"`
+	EndDate    string               `json:"end_date,omitempty"`
+	Customers  []*CustomerResponse  `json:"customers"`
+	Pagination *response.Pagination `json:"pagination"`
+}
+
+// RepeatPurchaseResponse describes how many customers bought tickets for
+// more than one event. RepeatPurchaseRate is a percentage of all customers.
+type RepeatPurchaseResponse struct {
+	Timezone                 string  `json:"timezone,omitempty"`
+	StartDate                string  `json:"start_date,omitempty"`
+	EndDate                  string  `json:"end_date,omitempty"`
+	Customers                int64   `json:"customers"`
+	RepeatCustomers          int64   `json:"repeat_customers"`
+	OneTimeCustomers         int64   `json:"one_time_customers"`
+	RepeatPurchaseRate       float64 `json:"repeat_purchase_rate"`
+	AverageEventsPerCustomer float64 `json:"average_events_per_customer"`
+}
+
+// EventBuyersResponse splits the buyers of an event. ReturningRate is the
+// percentage of buyers who had paid for another event before.
+type EventBuyersResponse struct {
+	EventID         uuid.UUID `json:"event_id"`
+	EventTitle      string    `json:"event_title"`
+	StartsAt        time.Time `json:"starts_at"`
+	Buyers          int64     `json:"buyers"`
+	FirstTimeBuyers int64     `json:"first_time_buyers"`
+	ReturningBuyers int64     `json:"returning_buyers"`
+	ReturningRate   float64   `json:"returning_rate"`
+	FirstTimeSpend  float64   `json:"first_time_spend"`
+	ReturningSpend  float64   `json:"returning_spend"`
+}
+
+type EventBuyersListResponse struct {
+	Timezone   string                 `json:"timezone,omitempty"`
+	StartDate  string                 `json:"start_date,omitempty"`
+	EndDate    string                 `json:"end_date,omitempty"`
+	Events     []*EventBuyersResponse `json:"events"`
+	Pagination *response.Pagination   `json:"pagination"`
+}
+
+type CustomerOrderStatusResponse struct {
+	Total     int64 `json:"total"`
+	Pending   int64 `json:"pending"`
+	Paid      int64 `json:"paid"`
+	Refunded  int64 `json:"refunded"`
+	Cancelled int64 `json:"cancelled"`
+	Expired   int64 `json:"expired"`
+}
+
+type CustomerEventResponse struct {
+	EventID         uuid.UUID `json:"event_id"`
+	EventTitle      string    `json:"event_title"`
+	StartsAt        time.Time `json:"starts_at"`
+	Orders          int64     `json:"orders"`
+	TicketsBought   int64     `json:"tickets_bought"`
+	CheckedIn       int64     `json:"checked_in"`
+	Spend           float64   `json:"spend"`
+	FirstPurchaseAt time.Time `json:"first_purchase_at"`
+	LastPurchaseAt  time.Time `json:"last_purchase_at"`
+}
+
+// CustomerSummaryResponse is a customer's purchase history: totals over the
+// paid orders, all orders by status, and the paid orders per event.
+type CustomerSummaryResponse struct {
+	Customer      *CustomerResponse            `json:"customer"`
+	OrderStatuses *CustomerOrderStatusResponse `json:"order_statuses"`
+	Events        []*CustomerEventResponse     `json:"events"`
+}
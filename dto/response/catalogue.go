@@ -0,0 +1,123 @@
+package response
+
+import (
+	"ticert/entity"
+	"ticert/utils/response"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// The public catalogue responses only carry what a visitor needs to browse
+// events; stock figures, inventory settings and bookkeeping fields stay out.
+
+type PublicEventResponse struct {
+	ID          uuid.UUID                 `json:"id"`
+	Organizer   string                    `json:"organizer"`
+	Title       string                    `json:"title"`
+	Description string                    `json:"description"`
+	StartsAt    time.Time                 `json:"starts_at"`
+	EndsAt      time.Time                 `json:"ends_at"`
+	Timezone    string                    `json:"timezone"`
+	EventDate   string                    `json:"event_date"`
+	RangeTime   string                    `json:"range_time"`
+	Location    string                    `json:"location"`
+	Venue       *PublicVenueResponse      `json:"venue,omitempty"`
+	Poster      *EventImageResponse       `json:"poster,omitempty"`
+	Gallery     []*EventImageResponse     `json:"gallery,omitempty"`
+	Tags        []*EventTagResponse       `json:"tags"`
+	Categories  []*PublicCategoryResponse `json:"categories"`
+}
+
+type PublicVenueResponse struct {
+	Name      string   `json:"name"`
+	Address   string   `json:"address"`
+	Latitude  *float64 `json:"latitude,omitempty"`
+	Longitude *float64 `json:"longitude,omitempty"`
+}
+
+type PublicCategoryResponse struct {
+	ID           uuid.UUID          `json:"id"`
+	EventID      uuid.UUID          `json:"event_id"`
+	Name         string             `json:"name"`
+	EventDate    string             `json:"event_date"`
+	Price        float64            `json:"price"`
+	SeatingMode  string             `json:"seating_mode"`
+	SaleStatus   string             `json:"sale_status"`
+	SalesStartAt *time.Time         `json:"sales_start_at,omitempty"`
+	SalesEndAt   *time.Time         `json:"sales_end_at,omitempty"`
+	MinPerOrder  int                `json:"min_per_order"`
+	MaxPerOrder  int                `json:"max_per_order,omitempty"`
+	CurrentTier  *PriceTierResponse `json:"current_tier,omitempty"`
+	NextTier     *PriceTierResponse `json:"next_tier,omitempty"`
+}
+
+type PublicEventListResponse struct {
+	Events     []*PublicEventResponse `json:"events"`
+	Pagination *response.Pagination   `json:"pagination"`
+	Facets     *EventFacetsResponse   `json:"facets,omitempty"`
+}
+
+func NewPublicEventResponse(event *entity.Event) *PublicEventResponse {
+	zone := event.Zone()
+	startsAt := event.StartsAt.In(zone)
+	endsAt := event.EndsAt.In(zone)
+
+	categories := make([]*PublicCategoryResponse, 0, len(event.Categories))
+	for i := range event.Categories {
+		categories = append(categories, NewPublicCategoryResponse(&event.Categories[i]))
+	}
+	poster, gallery := newEventImagesResponse(event.Images)
+
+	return &PublicEventResponse{
+		ID:          event.ID,
+		Organizer:   event.Organizer,
+		Title:       event.Title,
+		Description: event.Description,
+		StartsAt:    startsAt,
+		EndsAt:      endsAt,
+		Timezone:    zone.String(),
+		EventDate:   getEventDate(startsAt, endsAt),
+		RangeTime:   startsAt.Format("15:04") + " - " + endsAt.Format("15:04 MST"),
+		Location:    event.Location,
+		Venue:       NewPublicVenueResponse(event.Venue),
+		Poster:      poster,
+		Gallery:     gallery,
+		Tags:        NewEventTagListResponse(event.Tags),
+		Categories:  categories,
+	}
+}
+
+func NewPublicVenueResponse(venue *entity.Venue) *PublicVenueResponse {
+	if venue == nil {
+		return nil
+	}
+
+	return &PublicVenueResponse{
+		Name:      venue.Name,
+		Address:   venue.Address,
+		Latitude:  venue.Latitude,
+		Longitude: venue.Longitude,
+	}
+}
+
+func NewPublicCategoryResponse(category *entity.Category) *PublicCategoryResponse {
+	now := time.Now()
+	quote := category.QuotePrice(now)
+
+	return &PublicCategoryResponse{
+		ID:           category.ID,
+		EventID:      category.EventID,
+		Name:         category.Name,
+		EventDate:    category.EventDate.Format("02 Jan 2006"),
+		Price:        quote.Price,
+		SeatingMode:  category.SeatingMode,
+		SaleStatus:   category.SaleStatus(now),
+		SalesStartAt: category.SalesStartAt,
+		SalesEndAt:   category.SalesEndAt,
+		MinPerOrder:  category.MinPerOrder,
+		MaxPerOrder:  category.MaxPerOrder,
+		CurrentTier:  NewPriceTierResponse(quote.Current),
+		NextTier:     NewPriceTierResponse(quote.Next),
+	}
+}
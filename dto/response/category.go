@@ -2,27 +2,97 @@ package response
 
 import (
 	"ticert/entity"
+	"time"
 
 	"github.com/google/uuid"
 )
 
 type CategoryResponse struct {
-	ID        uuid.UUID `json:"id"`
-	Name      string    `json:"name"`
-	EventDate string    `json:"event_date"`
-	Price     float64   `json:"price,omitempty"`
-	Quantity  int       `json:"quantity,omitempty"`
-	Status    string    `json:"status,omitempty"`
+	ID                uuid.UUID          `json:"id"`
+	Name              string             `json:"name"`
+	EventDate         string             `json:"event_date"`
+	Price             float64            `json:"price,omitempty"`
+	Quantity          int                `json:"quantity,omitempty"`
+	Status            string             `json:"status,omitempty"`
+	InventoryStrategy string             `json:"inventory_strategy,omitempty"`
+	SeatingMode       string             `json:"seating_mode,omitempty"`
+	SalesStartAt      *time.Time         `json:"sales_start_at,omitempty"`
+	SalesEndAt        *time.Time         `json:"sales_end_at,omitempty"`
+	MinPerOrder       int                `json:"min_per_order,omitempty"`
+	MaxPerOrder       int                `json:"max_per_order,omitempty"`
+	MaxPerUser        int                `json:"max_per_user,omitempty"`
+	SaleStatus        string             `json:"sale_status,omitempty"`
+	SaleOpensIn       int64              `json:"sale_opens_in,omitempty"`
+	CurrentTier       *PriceTierResponse `json:"current_tier,omitempty"`
+	NextTier          *PriceTierResponse `json:"next_tier,omitempty"`
+}
+
+type PriceTierResponse struct {
+	ID             uuid.UUID  `json:"id"`
+	Name           string     `json:"name"`
+	Price          float64    `json:"price"`
+	StartsAt       *time.Time `json:"starts_at,omitempty"`
+	EndsAt         *time.Time `json:"ends_at,omitempty"`
+	UnitLimit      int        `json:"unit_limit,omitempty"`
+	UnitsRemaining int        `json:"units_remaining,omitempty"`
+}
+
+func NewPriceTierResponse(tier *entity.PriceTier) *PriceTierResponse {
+	if tier == nil {
+		return nil
+	}
+
+	return &PriceTierResponse{
+		ID:        tier.ID,
+		Name:      tier.Name,
+		Price:     tier.Price,
+		StartsAt:  tier.StartsAt,
+		EndsAt:    tier.EndsAt,
+		UnitLimit: tier.UnitLimit,
+	}
+}
+
+func NewPriceTierListResponse(tiers []entity.PriceTier) []*PriceTierResponse {
+	responses := make([]*PriceTierResponse, 0, len(tiers))
+	for i := range tiers {
+		responses = append(responses, NewPriceTierResponse(&tiers[i]))
+	}
+	return responses
 }
 
 func NewCategoryResponse(ticket *entity.Category) *CategoryResponse {
+	now := time.Now()
+
+	// SaleOpensIn is the number of seconds until an upcoming sale opens.
+	var saleOpensIn int64
+	if ticket.SalesStartAt != nil && now.Before(*ticket.SalesStartAt) {
+		saleOpensIn = int64(ticket.SalesStartAt.Sub(now).Seconds())
+	}
+
+	quote := ticket.QuotePrice(now)
+	currentTier := NewPriceTierResponse(quote.Current)
+	if currentTier != nil {
+		currentTier.UnitsRemaining = quote.UnitsRemaining
+	}
+
 	return &CategoryResponse{
-		ID:        ticket.ID,
-		Name:      ticket.Name,
-		EventDate: ticket.EventDate.Format("02 Jan 2006"),
-		Price:     ticket.Price,
-		Quantity:  ticket.Quantity,
-		Status:    ticket.Status,
+		ID:                ticket.ID,
+		Name:              ticket.Name,
+		EventDate:         ticket.EventDate.Format("02 Jan 2006"),
+		Price:             quote.Price,
+		Quantity:          ticket.Quantity,
+		Status:            ticket.Status,
+		InventoryStrategy: ticket.InventoryStrategy,
+		SeatingMode:       ticket.SeatingMode,
+		SalesStartAt:      ticket.SalesStartAt,
+		SalesEndAt:        ticket.SalesEndAt,
+		MinPerOrder:       ticket.MinPerOrder,
+		MaxPerOrder:       ticket.MaxPerOrder,
+		MaxPerUser:        ticket.MaxPerUser,
+		SaleStatus:        ticket.SaleStatus(now),
+		SaleOpensIn:       saleOpensIn,
+		CurrentTier:       currentTier,
+		NextTier:          NewPriceTierResponse(quote.Next),
 	}
 }
 
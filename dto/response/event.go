@@ -9,38 +9,115 @@ import (
 )
 
 type EventResponse struct {
-	ID          uuid.UUID           `json:"id"`
-	Organizer   string              `json:"organizer"`
-	Title       string              `json:"title"`
-	Description string              `json:"description"`
-	EventDate   string              `json:"event_date"`
-	RangeTime   string              `json:"range_time"`
-	Location    string              `json:"location"`
-	CreatedAt   time.Time           `json:"created_at"`
-	UpdatedAt   time.Time           `json:"updated_at"`
-	Categories  []*CategoryResponse `json:"categories,omitempty"`
+	ID          uuid.UUID             `json:"id"`
+	Organizer   string                `json:"organizer"`
+	Title       string                `json:"title"`
+	Description string                `json:"description"`
+	StartsAt    time.Time             `json:"starts_at"`
+	EndsAt      time.Time             `json:"ends_at"`
+	Timezone    string                `json:"timezone"`
+	EventDate   string                `json:"event_date"`
+	RangeTime   string                `json:"range_time"`
+	Location    string                `json:"location"`
+	Status      string                `json:"status"`
+	SeriesID    *uuid.UUID            `json:"series_id,omitempty"`
+	Venue       *VenueResponse        `json:"venue,omitempty"`
+	SeatMapID   *uuid.UUID            `json:"seat_map_id,omitempty"`
+	Poster      *EventImageResponse   `json:"poster,omitempty"`
+	Gallery     []*EventImageResponse `json:"gallery,omitempty"`
+	Tags        []*EventTagResponse   `json:"tags"`
+	CreatedAt   time.Time             `json:"created_at"`
+	UpdatedAt   time.Time             `json:"updated_at"`
+	Categories  []*CategoryResponse   `json:"categories,omitempty"`
+}
+
+type EventImageResponse struct {
+	ID           uuid.UUID `json:"id"`
+	Kind         string    `json:"kind"`
+	URL          string    `json:"url"`
+	ThumbnailURL string    `json:"thumbnail_url"`
+	Width        int       `json:"width"`
+	Height       int       `json:"height"`
 }
 
 type EventListResponse struct {
 	Events     []*EventResponse     `json:"events"`
 	Pagination *response.Pagination `json:"pagination"`
+	Facets     *EventFacetsResponse `json:"facets,omitempty"`
+}
+
+type EventFacetsResponse struct {
+	Organizers   []*FacetCountResponse      `json:"organizers"`
+	Locations    []*FacetCountResponse      `json:"locations"`
+	Availability *AvailabilityFacetResponse `json:"availability"`
+}
+
+type FacetCountResponse struct {
+	Value string `json:"value"`
+	Count int64  `json:"count"`
+}
+
+type AvailabilityFacetResponse struct {
+	Available int64 `json:"available"`
+	SoldOut   int64 `json:"sold_out"`
 }
 
 func NewEventResponse(event *entity.Event) *EventResponse {
+	zone := event.Zone()
+	startsAt := event.StartsAt.In(zone)
+	endsAt := event.EndsAt.In(zone)
+	poster, gallery := newEventImagesResponse(event.Images)
+
 	return &EventResponse{
 		ID:          event.ID,
 		Organizer:   event.Organizer,
 		Title:       event.Title,
 		Description: event.Description,
-		EventDate:   getEventDate(event.StartDate, event.EndDate),
-		RangeTime:   event.StartTime.Format("15:04") + " - " + event.EndTime.Format("15:04"),
+		StartsAt:    startsAt,
+		EndsAt:      endsAt,
+		Timezone:    zone.String(),
+		EventDate:   getEventDate(startsAt, endsAt),
+		RangeTime:   startsAt.Format("15:04") + " - " + endsAt.Format("15:04 MST"),
 		Location:    event.Location,
+		Status:      event.Status,
+		SeriesID:    event.SeriesID,
+		Venue:       NewVenueResponse(event.Venue),
+		SeatMapID:   event.SeatMapID,
+		Poster:      poster,
+		Gallery:     gallery,
+		Tags:        NewEventTagListResponse(event.Tags),
 		CreatedAt:   event.CreatedAt,
 		UpdatedAt:   event.UpdatedAt,
 		Categories:  NewCategoryListResponse(event.Categories),
 	}
 }
 
+func NewEventImageResponse(image *entity.EventImage) *EventImageResponse {
+	return &EventImageResponse{
+		ID:           image.ID,
+		Kind:         image.Kind,
+		URL:          image.URL,
+		ThumbnailURL: image.ThumbnailURL,
+		Width:        image.Width,
+		Height:       image.Height,
+	}
+}
+
+// newEventImagesResponse splits the event's images into its poster and its
+// gallery, keeping the stored order.
+func newEventImagesResponse(images []entity.EventImage) (*EventImageResponse, []*EventImageResponse) {
+	var poster *EventImageResponse
+	var gallery []*EventImageResponse
+	for i := range images {
+		if images[i].Kind == "poster" {
+			poster = NewEventImageResponse(&images[i])
+			continue
+		}
+		gallery = append(gallery, NewEventImageResponse(&images[i]))
+	}
+	return poster, gallery
+}
+
 func getEventDate(startDate, endDate time.Time) string {
 	if startDate.Month() == endDate.Month() && startDate.Year() == endDate.Year() {
 		return startDate.Format("02") + " - " + endDate.Format("02 Jan 2006")
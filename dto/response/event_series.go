@@ -0,0 +1,37 @@
+package response
+
+import (
+	"ticert/entity"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type EventSeriesResponse struct {
+	ID              uuid.UUID        `json:"id"`
+	Title           string           `json:"title"`
+	RRule           string           `json:"rrule"`
+	Timezone        string           `json:"timezone"`
+	TemplateEventID uuid.UUID        `json:"template_event_id"`
+	CreatedAt       time.Time        `json:"created_at"`
+	UpdatedAt       time.Time        `json:"updated_at"`
+	Occurrences     []*EventResponse `json:"occurrences"`
+}
+
+func NewEventSeriesResponse(series *entity.EventSeries) *EventSeriesResponse {
+	occurrences := make([]*EventResponse, 0, len(series.Events))
+	for i := range series.Events {
+		occurrences = append(occurrences, NewEventResponse(&series.Events[i]))
+	}
+
+	return &EventSeriesResponse{
+		ID:              series.ID,
+		Title:           series.Title,
+		RRule:           series.RRule,
+		Timezone:        series.Timezone,
+		TemplateEventID: series.TemplateEventID,
+		CreatedAt:       series.CreatedAt,
+		UpdatedAt:       series.UpdatedAt,
+		Occurrences:     occurrences,
+	}
+}
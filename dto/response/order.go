@@ -9,25 +9,36 @@ import (
 )
 
 type OrderResponse struct {
-	ID         uuid.UUID              `json:"id"`
-	InvoiceID  string                 `json:"invoice_id"`
-	Status     string                 `json:"status"`
-	Quantity   int                    `json:"quantity"`
-	TotalPrice float64                `json:"total_price"`
-	Tickets    []*OrderDetailResponse `json:"tickets,omitempty"`
-	Event      *EventResponse         `json:"event"`
-	Category   *CategoryResponse      `json:"category"`
-	User       *UserResponse          `json:"user"`
-	CreatedAt  time.Time              `json:"created_at"`
-	UpdatedAt  time.Time              `json:"updated_at"`
+	ID         uuid.UUID            `json:"id"`
+	InvoiceID  string               `json:"invoice_id"`
+	Status     string               `json:"status"`
+	Quantity   int                  `json:"quantity"`
+	TotalPrice float64              `json:"total_price"`
+	Items      []*OrderItemResponse `json:"items"`
+	Event      *EventResponse       `json:"event"`
+	User       *UserResponse        `json:"user"`
+	PaidAt     *time.Time           `json:"paid_at"`
+	CreatedAt  time.Time            `json:"created_at"`
+	UpdatedAt  time.Time            `json:"updated_at"`
+}
+
+type OrderItemResponse struct {
+	ID        uuid.UUID              `json:"id"`
+	Category  *CategoryResponse      `json:"category"`
+	Quantity  int                    `json:"quantity"`
+	UnitPrice float64                `json:"unit_price"`
+	Subtotal  float64                `json:"subtotal"`
+	Tickets   []*OrderDetailResponse `json:"tickets,omitempty"`
 }
 
 type OrderDetailResponse struct {
-	ID             uuid.UUID `json:"id"`
-	TicketCode     string    `json:"ticket_code"`
-	FullName       string    `json:"full_name"`
-	IdentityNumber string    `json:"identity_number"`
-	Redeemed       bool      `json:"redeemed"`
+	ID             uuid.UUID     `json:"id"`
+	TicketCode     string        `json:"ticket_code"`
+	FullName       string        `json:"full_name"`
+	IdentityNumber string        `json:"identity_number"`
+	Redeemed       bool          `json:"redeemed"`
+	RedeemedAt     *time.Time    `json:"redeemed_at"`
+	Seat           *SeatResponse `json:"seat,omitempty"`
 }
 
 type OrderListResponse struct {
@@ -36,27 +47,56 @@ type OrderListResponse struct {
 }
 
 func NewOrderResponse(order *entity.Order) *OrderResponse {
-	var tickets []*OrderDetailResponse
-	if order.Status != "pending" && order.Status != "cancelled" {
-		tickets = NewOrderDetailListResponse(order.OrderDetails)
+	showTickets := order.Status != "pending" && order.Status != "cancelled" && order.Status != "expired"
+
+	items := make([]*OrderItemResponse, len(order.OrderItems))
+	for i, item := range order.OrderItems {
+		items[i] = NewOrderItemResponse(item, showTickets)
 	}
+
+	var event *EventResponse
+	if order.Event != nil {
+		event = NewEventResponse(order.Event)
+	}
+
 	return &OrderResponse{
 		ID:         order.ID,
 		InvoiceID:  order.InvoiceID,
 		Status:     order.Status,
 		Quantity:   order.Quantity,
 		TotalPrice: order.TotalPrice,
-		Tickets:    tickets,
-		Event:      NewEventResponse(order.Category.Event),
-		Category: &CategoryResponse{
-			ID:        order.Category.ID,
-			Name:      order.Category.Name,
-			Price:     order.Category.Price,
-			EventDate: order.Category.EventDate.Format("02 Jan 2006"),
-		},
-		User:      NewUserResponse(order.User),
-		CreatedAt: order.CreatedAt,
-		UpdatedAt: order.UpdatedAt,
+		Items:      items,
+		Event:      event,
+		User:       NewUserResponse(order.User),
+		PaidAt:     order.PaidAt,
+		CreatedAt:  order.CreatedAt,
+		UpdatedAt:  order.UpdatedAt,
+	}
+}
+
+func NewOrderItemResponse(item *entity.OrderItem, showTickets bool) *OrderItemResponse {
+	var category *CategoryResponse
+	if item.Category != nil {
+		category = &CategoryResponse{
+			ID:        item.Category.ID,
+			Name:      item.Category.Name,
+			Price:     item.UnitPrice,
+			EventDate: item.Category.EventDate.Format("02 Jan 2006"),
+		}
+	}
+
+	var tickets []*OrderDetailResponse
+	if showTickets {
+		tickets = NewOrderDetailListResponse(item.OrderDetails)
+	}
+
+	return &OrderItemResponse{
+		ID:        item.ID,
+		Category:  category,
+		Quantity:  item.Quantity,
+		UnitPrice: item.UnitPrice,
+		Subtotal:  item.Subtotal,
+		Tickets:   tickets,
 	}
 }
 
@@ -75,5 +115,7 @@ func NewOrderDetailResponse(orderDetail *entity.OrderDetail) *OrderDetailRespons
 		FullName:       orderDetail.FullName,
 		IdentityNumber: orderDetail.IdentityNumber,
 		Redeemed:       orderDetail.Redeemed,
+		RedeemedAt:     orderDetail.RedeemedAt,
+		Seat:           NewSeatResponse(orderDetail.Seat),
 	}
 }
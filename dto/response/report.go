@@ -42,3 +42,75 @@ type ReportListResponse struct {
 	Reports    []*EventReportResponse `json:"reports"`
 	Pagination *response.Pagination   `json:"pagination"`
 }
+
+// AttendanceFiguresResponse compares paid tickets with check-ins. NoShow
+// stays zero until the event has ended; CheckInRate is a percentage of
+// TicketsSold.
+type AttendanceFiguresResponse struct {
+	TicketsSold  int64   `json:"tickets_sold"`
+	CheckedIn    int64   `json:"checked_in"`
+	NotCheckedIn int64   `json:"not_checked_in"`
+	NoShow       int64   `json:"no_show"`
+	CheckInRate  float64 `json:"check_in_rate"`
+}
+
+type EventAttendanceResponse struct {
+	EventID     uuid.UUID `json:"event_id"`
+	EventTitle  string    `json:"event_title"`
+	EventDate   string    `json:"event_date"`
+	EventStatus string    `json:"event_status"`
+	AttendanceFiguresResponse
+	Categories []CategoryAttendanceResponse `json:"categories"`
+}
+
+type CategoryAttendanceResponse struct {
+	CategoryID   uuid.UUID `json:"category_id"`
+	CategoryName string    `json:"category_name"`
+	AttendanceFiguresResponse
+}
+
+type AttendanceListResponse struct {
+	Reports    []*EventAttendanceResponse `json:"reports"`
+	Pagination *response.Pagination       `json:"pagination"`
+}
+
+type CheckInTimelineResponse struct {
+	EventID         uuid.UUID                  `json:"event_id"`
+	EventTitle      string                     `json:"event_title"`
+	EventStatus     string                     `json:"event_status"`
+	Timezone        string                     `json:"timezone"`
+	Interval        int                        `json:"interval"`
+	StartsAt        time.Time                  `json:"starts_at"`
+	EndsAt          time.Time                  `json:"ends_at"`
+	Totals          *AttendanceFiguresResponse `json:"totals"`
+	UndatedCheckIns int64                      `json:"undated_check_ins"`
+	Buckets         []*CheckInBucketResponse   `json:"buckets"`
+}
+
+type CheckInBucketResponse struct {
+	Start              time.Time `json:"start"`
+	CheckIns           int64     `json:"check_ins"`
+	CumulativeCheckIns int64     `json:"cumulative_check_ins"`
+	CheckInRate        float64   `json:"check_in_rate"`
+}
+
+type ArrivalReportResponse struct {
+	EventID             uuid.UUID                `json:"event_id"`
+	EventTitle          string                   `json:"event_title"`
+	Timezone            string                   `json:"timezone"`
+	StartsAt            time.Time                `json:"starts_at"`
+	CheckedIn           int64                    `json:"checked_in"`
+	UndatedCheckIns     int64                    `json:"undated_check_ins"`
+	MedianArrivalMinute *int                     `json:"median_arrival_minute"`
+	Buckets             []*ArrivalBucketResponse `json:"buckets"`
+}
+
+// ArrivalBucketResponse counts the check-ins made from FromMinute up to
+// ToMinute after the event started. A nil bound leaves that side open.
+type ArrivalBucketResponse struct {
+	Label      string  `json:"label"`
+	FromMinute *int    `json:"from_minute"`
+	ToMinute   *int    `json:"to_minute"`
+	CheckIns   int64   `json:"check_ins"`
+	Percentage float64 `json:"percentage"`
+}
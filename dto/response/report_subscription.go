@@ -0,0 +1,104 @@
+package response
+
+import (
+	"ticert/entity"
+	"ticert/utils/response"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type ReportSubscriptionResponse struct {
+	ID         uuid.UUID  `json:"id"`
+	Recipient  string     `json:"recipient"`
+	EventID    *uuid.UUID `json:"event_id"`
+	EventTitle string     `json:"event_title,omitempty"`
+	Kind       string     `json:"kind"`
+	Schedule   string     `json:"schedule"`
+	Timezone   string     `json:"timezone"`
+	Format     string     `json:"format"`
+	Active     bool       `json:"active"`
+	NextRunAt  *time.Time `json:"next_run_at"`
+	LastRunAt  *time.Time `json:"last_run_at"`
+	CreatedAt  time.Time  `json:"created_at"`
+	UpdatedAt  time.Time  `json:"updated_at"`
+}
+
+type ReportSubscriptionListResponse struct {
+	Subscriptions []*ReportSubscriptionResponse `json:"subscriptions"`
+	Pagination    *response.Pagination          `json:"pagination"`
+}
+
+type ReportRunResponse struct {
+	ID          uuid.UUID  `json:"id"`
+	ScheduledAt time.Time  `json:"scheduled_at"`
+	PeriodStart string     `json:"period_start,omitempty"`
+	PeriodEnd   string     `json:"period_end,omitempty"`
+	Status      string     `json:"status"`
+	Error       string     `json:"error,omitempty"`
+	StartedAt   time.Time  `json:"started_at"`
+	FinishedAt  *time.Time `json:"finished_at"`
+}
+
+type ReportRunListResponse struct {
+	Runs       []*ReportRunResponse `json:"runs"`
+	Pagination *response.Pagination `json:"pagination"`
+}
+
+// NewReportSubscriptionResponse shows the schedule times in the
+// subscription's time zone.
+func NewReportSubscriptionResponse(subscription *entity.ReportSubscription) *ReportSubscriptionResponse {
+	if subscription == nil {
+		return nil
+	}
+
+	zone := subscription.Zone()
+	res := &ReportSubscriptionResponse{
+		ID:        subscription.ID,
+		Recipient: subscription.Recipient,
+		EventID:   subscription.EventID,
+		Kind:      subscription.Kind,
+		Schedule:  subscription.Schedule,
+		Timezone:  subscription.Timezone,
+		Format:    subscription.Format,
+		Active:    subscription.Active,
+		NextRunAt: inZone(subscription.NextRunAt, zone),
+		LastRunAt: inZone(subscription.LastRunAt, zone),
+		CreatedAt: subscription.CreatedAt,
+		UpdatedAt: subscription.UpdatedAt,
+	}
+
+	if subscription.Event != nil {
+		res.EventTitle = subscription.Event.Title
+	}
+
+	return res
+}
+
+func NewReportRunResponse(run *entity.ReportRun) *ReportRunResponse {
+	res := &ReportRunResponse{
+		ID:          run.ID,
+		ScheduledAt: run.ScheduledAt,
+		Status:      run.Status,
+		Error:       run.Error,
+		StartedAt:   run.StartedAt,
+		FinishedAt:  run.FinishedAt,
+	}
+
+	if run.PeriodStart != nil {
+		res.PeriodStart = run.PeriodStart.Format("2006-01-02")
+	}
+	if run.PeriodEnd != nil {
+		res.PeriodEnd = run.PeriodEnd.Format("2006-01-02")
+	}
+
+	return res
+}
+
+func inZone(t *time.Time, zone *time.Location) *time.Time {
+	if t == nil {
+		return nil
+	}
+	local := t.In(zone)
+	return &local
+}
@@ -0,0 +1,139 @@
+package response
+
+import (
+	"ticert/entity"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type SeatMapResponse struct {
+	ID          uuid.UUID              `json:"id"`
+	VenueID     *uuid.UUID             `json:"venue_id,omitempty"`
+	Name        string                 `json:"name"`
+	Description string                 `json:"description,omitempty"`
+	TotalSeats  int                    `json:"total_seats,omitempty"`
+	Sections    []*SeatSectionResponse `json:"sections,omitempty"`
+	CreatedAt   time.Time              `json:"created_at"`
+}
+
+type SeatSectionResponse struct {
+	ID    uuid.UUID       `json:"id"`
+	Name  string          `json:"name"`
+	Seats []*SeatResponse `json:"seats"`
+}
+
+type SeatResponse struct {
+	ID         uuid.UUID  `json:"id"`
+	Section    string     `json:"section,omitempty"`
+	Row        string     `json:"row"`
+	Number     int        `json:"number"`
+	Accessible bool       `json:"accessible,omitempty"`
+	Companion  bool       `json:"companion,omitempty"`
+	CategoryID *uuid.UUID `json:"category_id,omitempty"`
+	Price      float64    `json:"price,omitempty"`
+	Status     string     `json:"status,omitempty"`
+}
+
+type EventSeatsResponse struct {
+	EventID   uuid.UUID              `json:"event_id"`
+	SeatMapID uuid.UUID              `json:"seat_map_id"`
+	Sections  []*SeatSectionResponse `json:"sections"`
+}
+
+func NewSeatMapResponse(seatMap *entity.SeatMap) *SeatMapResponse {
+	var sections []*SeatSectionResponse
+	totalSeats := 0
+	for i := range seatMap.Sections {
+		section := &seatMap.Sections[i]
+		seats := make([]*SeatResponse, len(section.Seats))
+		for j := range section.Seats {
+			seats[j] = NewSeatResponse(&section.Seats[j])
+		}
+		totalSeats += len(seats)
+		sections = append(sections, &SeatSectionResponse{
+			ID:    section.ID,
+			Name:  section.Name,
+			Seats: seats,
+		})
+	}
+
+	return &SeatMapResponse{
+		ID:          seatMap.ID,
+		VenueID:     seatMap.VenueID,
+		Name:        seatMap.Name,
+		Description: seatMap.Description,
+		TotalSeats:  totalSeats,
+		Sections:    sections,
+		CreatedAt:   seatMap.CreatedAt,
+	}
+}
+
+func NewSeatResponse(seat *entity.Seat) *SeatResponse {
+	if seat == nil {
+		return nil
+	}
+
+	var section string
+	if seat.Section != nil {
+		section = seat.Section.Name
+	}
+
+	return &SeatResponse{
+		ID:         seat.ID,
+		Section:    section,
+		Row:        seat.Row,
+		Number:     seat.Number,
+		Accessible: seat.Accessible,
+		Companion:  seat.Companion,
+	}
+}
+
+// NewEventSeatsResponse lays the event's category seats over its seat map.
+// Seats not assigned to any category are reported as "unassigned".
+func NewEventSeatsResponse(event *entity.Event, seatMap *entity.SeatMap, categorySeats []*entity.CategorySeat) *EventSeatsResponse {
+	bySeat := make(map[uuid.UUID]*entity.CategorySeat, len(categorySeats))
+	for _, categorySeat := range categorySeats {
+		bySeat[categorySeat.SeatID] = categorySeat
+	}
+
+	now := time.Now()
+	prices := make(map[uuid.UUID]float64)
+
+	sections := make([]*SeatSectionResponse, len(seatMap.Sections))
+	for i := range seatMap.Sections {
+		section := &seatMap.Sections[i]
+		seats := make([]*SeatResponse, len(section.Seats))
+		for j := range section.Seats {
+			seat := NewSeatResponse(&section.Seats[j])
+			seat.Status = "unassigned"
+
+			if categorySeat, exists := bySeat[seat.ID]; exists {
+				categoryID := categorySeat.CategoryID
+				seat.CategoryID = &categoryID
+				seat.Status = categorySeat.Status
+
+				price, exists := prices[categoryID]
+				if !exists && categorySeat.Category != nil {
+					price = categorySeat.Category.QuotePrice(now).Price
+					prices[categoryID] = price
+				}
+				seat.Price = price
+			}
+
+			seats[j] = seat
+		}
+
+		sections[i] = &SeatSectionResponse{
+			ID:    section.ID,
+			Name:  section.Name,
+			Seats: seats,
+		}
+	}
+
+	return &EventSeatsResponse{
+		EventID:   event.ID,
+		SeatMapID: seatMap.ID,
+		Sections:  sections,
+	}
+}
@@ -0,0 +1,54 @@
+package response
+
+import (
+	"ticert/entity"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type TagResponse struct {
+	ID         uuid.UUID      `json:"id"`
+	Name       string         `json:"name"`
+	Slug       string         `json:"slug"`
+	ParentID   *uuid.UUID     `json:"parent_id,omitempty"`
+	EventCount int64          `json:"event_count"`
+	Children   []*TagResponse `json:"children,omitempty"`
+	CreatedAt  time.Time      `json:"created_at"`
+	UpdatedAt  time.Time      `json:"updated_at"`
+}
+
+type TagListResponse struct {
+	Tags []*TagResponse `json:"tags"`
+}
+
+// EventTagResponse is a tag as shown on an event.
+type EventTagResponse struct {
+	ID   uuid.UUID `json:"id"`
+	Name string    `json:"name"`
+	Slug string    `json:"slug"`
+}
+
+func NewTagResponse(tag *entity.Tag, eventCount int64) *TagResponse {
+	return &TagResponse{
+		ID:         tag.ID,
+		Name:       tag.Name,
+		Slug:       tag.Slug,
+		ParentID:   tag.ParentID,
+		EventCount: eventCount,
+		CreatedAt:  tag.CreatedAt,
+		UpdatedAt:  tag.UpdatedAt,
+	}
+}
+
+func NewEventTagListResponse(tags []entity.Tag) []*EventTagResponse {
+	tagResponses := make([]*EventTagResponse, 0, len(tags))
+	for _, tag := range tags {
+		tagResponses = append(tagResponses, &EventTagResponse{
+			ID:   tag.ID,
+			Name: tag.Name,
+			Slug: tag.Slug,
+		})
+	}
+	return tagResponses
+}
@@ -0,0 +1,44 @@
+package response
+
+import (
+	"ticert/entity"
+	"ticert/utils/response"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type VenueResponse struct {
+	ID        uuid.UUID `json:"id"`
+	Name      string    `json:"name"`
+	Address   string    `json:"address"`
+	Latitude  *float64  `json:"latitude,omitempty"`
+	Longitude *float64  `json:"longitude,omitempty"`
+	Timezone  string    `json:"timezone"`
+	Capacity  int       `json:"capacity"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+type VenueListResponse struct {
+	Venues     []*VenueResponse     `json:"venues"`
+	Pagination *response.Pagination `json:"pagination"`
+}
+
+func NewVenueResponse(venue *entity.Venue) *VenueResponse {
+	if venue == nil {
+		return nil
+	}
+
+	return &VenueResponse{
+		ID:        venue.ID,
+		Name:      venue.Name,
+		Address:   venue.Address,
+		Latitude:  venue.Latitude,
+		Longitude: venue.Longitude,
+		Timezone:  venue.Timezone,
+		Capacity:  venue.Capacity,
+		CreatedAt: venue.CreatedAt,
+		UpdatedAt: venue.UpdatedAt,
+	}
+}
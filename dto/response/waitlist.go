@@ -0,0 +1,32 @@
+package response
+
+import (
+	"ticert/entity"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type WaitlistResponse struct {
+	ID             uuid.UUID  `json:"id"`
+	CategoryID     uuid.UUID  `json:"category_id"`
+	Quantity       int        `json:"quantity"`
+	Status         string     `json:"status"`
+	Position       int64      `json:"position,omitempty"`
+	NotifiedAt     *time.Time `json:"notified_at,omitempty"`
+	ClaimExpiresAt *time.Time `json:"claim_expires_at,omitempty"`
+	CreatedAt      time.Time  `json:"created_at"`
+}
+
+func NewWaitlistResponse(entry *entity.WaitlistEntry, position int64) *WaitlistResponse {
+	return &WaitlistResponse{
+		ID:             entry.ID,
+		CategoryID:     entry.CategoryID,
+		Quantity:       entry.Quantity,
+		Status:         entry.Status,
+		Position:       position,
+		NotifiedAt:     entry.NotifiedAt,
+		ClaimExpiresAt: entry.ClaimExpiresAt,
+		CreatedAt:      entry.CreatedAt,
+	}
+}
@@ -8,18 +8,31 @@ import (
 )
 
 type Category struct {
-	ID        uuid.UUID      `json:"id" gorm:"type:char(36);primaryKey"`
-	EventID   uuid.UUID      `json:"event_id" gorm:"type:char(36);not null"`
-	Name      string         `json:"name" gorm:"type:varchar(255);not null"`
-	Price     float64        `json:"price" gorm:"type:decimal(10,2);not null"`
-	EventDate time.Time      `json:"event_date" gorm:"type:datetime;not null"`
-	Quantity  int            `json:"quantity" gorm:"type:int;not null"`
-	Status    string         `json:"status" gorm:"type:enum('available','sold');not null;default:'available'"`
-	CreatedAt time.Time      `json:"created_at" gorm:"autoCreateTime"`
-	UpdatedAt time.Time      `json:"updated_at" gorm:"autoUpdateTime"`
-	DeletedAt gorm.DeletedAt `json:"deleted_at,omitempty" gorm:"index"`
-
-	Event *Event `json:"event" gorm:"foreignKey:EventID"`
+	ID                uuid.UUID      `json:"id" gorm:"type:char(36);primaryKey"`
+	EventID           uuid.UUID      `json:"event_id" gorm:"type:char(36);not null"`
+	Name              string         `json:"name" gorm:"type:varchar(255);not null"`
+	Price             float64        `json:"price" gorm:"type:decimal(10,2);not null"`
+	EventDate         time.Time      `json:"event_date" gorm:"type:datetime;not null"`
+	Quantity          int            `json:"quantity" gorm:"type:int;not null"`
+	Status            string         `json:"status" gorm:"type:enum('available','sold');not null;default:'available'"`
+	InventoryStrategy string         `json:"inventory_strategy" gorm:"type:enum('database','redis');not null;default:'database'"`
+	SeatingMode       string         `json:"seating_mode" gorm:"type:enum('general','reserved');not null;default:'general'"`
+	SalesStartAt      *time.Time     `json:"sales_start_at" gorm:"type:datetime"`
+	SalesEndAt        *time.Time     `json:"sales_end_at" gorm:"type:datetime"`
+	MinPerOrder       int            `json:"min_per_order" gorm:"type:int;not null;default:1"`
+	MaxPerOrder       int            `json:"max_per_order" gorm:"type:int;not null;default:0"`
+	MaxPerUser        int            `json:"max_per_user" gorm:"type:int;not null;default:0"`
+	WaitlistHeld      int            `json:"waitlist_held" gorm:"type:int;not null;default:0"`
+	CreatedAt         time.Time      `json:"created_at" gorm:"autoCreateTime"`
+	UpdatedAt         time.Time      `json:"updated_at" gorm:"autoUpdateTime"`
+	DeletedAt         gorm.DeletedAt `json:"deleted_at,omitempty" gorm:"index"`
+
+	Event      *Event      `json:"event" gorm:"foreignKey:EventID"`
+	PriceTiers []PriceTier `json:"price_tiers" gorm:"foreignKey:CategoryID"`
+
+	// SoldQuantity counts tickets in pending and paid orders. It is filled by
+	// the repository and drives unit-limited price tiers.
+	SoldQuantity int `json:"-" gorm:"-"`
 }
 
 func (c *Category) BeforeCreate(tx *gorm.DB) error {
@@ -28,3 +41,90 @@ func (c *Category) BeforeCreate(tx *gorm.DB) error {
 	}
 	return nil
 }
+
+// SaleStatus reports whether the category can be bought at the given time:
+// "upcoming" before the sale window opens, "ended" after it closes,
+// "sold_out" when no stock is left and "on_sale" otherwise.
+func (c *Category) SaleStatus(now time.Time) string {
+	if c.SalesStartAt != nil && now.Before(*c.SalesStartAt) {
+		return "upcoming"
+	}
+	if c.SalesEndAt != nil && now.After(*c.SalesEndAt) {
+		return "ended"
+	}
+	if c.Status == "sold" || c.Quantity <= 0 {
+		return "sold_out"
+	}
+	return "on_sale"
+}
+
+// QuotePrice evaluates the price schedule at the given time. Tiers are walked
+// in position order; a tier applies once it has started, until it ends or its
+// unit limit is used up. Unit limits are cumulative, so "first 100 at X, next
+// 200 at Y" is two tiers with limits 100 and 200. Without an applicable tier
+// the category's base price is used.
+func (c *Category) QuotePrice(now time.Time) PriceQuote {
+	return c.quotePrice(now, c.SoldQuantity)
+}
+
+// PriceUnits prices quantity units bought at the given time. Units beyond
+// what is left of a unit-limited tier are priced by the tiers after it, so
+// an order crossing a tier boundary is split into one part per tier.
+func (c *Category) PriceUnits(now time.Time, quantity int) []PricedUnits {
+	var parts []PricedUnits
+
+	sold := c.SoldQuantity
+	for quantity > 0 {
+		quote := c.quotePrice(now, sold)
+
+		units := quantity
+		if quote.Current != nil && quote.Current.UnitLimit > 0 {
+			units = min(units, quote.UnitsRemaining)
+		}
+
+		parts = append(parts, PricedUnits{Tier: quote.Current, Price: quote.Price, Quantity: units})
+		sold += units
+		quantity -= units
+	}
+
+	return parts
+}
+
+func (c *Category) quotePrice(now time.Time, sold int) PriceQuote {
+	quote := PriceQuote{Price: c.Price}
+
+	unitsBefore := 0
+	for i := range c.PriceTiers {
+		tier := &c.PriceTiers[i]
+		unitsRemaining := 0
+		if tier.UnitLimit > 0 {
+			unitsRemaining = unitsBefore + tier.UnitLimit - sold
+			unitsBefore += tier.UnitLimit
+			if unitsRemaining <= 0 {
+				continue
+			}
+		}
+
+		if tier.EndsAt != nil && now.After(*tier.EndsAt) {
+			continue
+		}
+
+		started := tier.StartsAt == nil || !now.Before(*tier.StartsAt)
+		if quote.Current == nil && started {
+			quote.Current = tier
+			quote.Price = tier.Price
+			quote.UnitsRemaining = unitsRemaining
+			continue
+		}
+
+		if quote.Next == nil {
+			quote.Next = tier
+		}
+
+		if quote.Current != nil {
+			break
+		}
+	}
+
+	return quote
+}
@@ -9,19 +9,27 @@ import (
 
 type Event struct {
 	ID          uuid.UUID      `json:"id" gorm:"type:char(36);primaryKey"`
-	Organizer   string         `json:"organizer" gorm:"type:varchar(255);not null"`
-	Title       string         `json:"title" gorm:"type:varchar(255);not null"`
-	Description string         `json:"description" gorm:"type:text;not null"`
-	StartDate   time.Time      `json:"start_date" gorm:"type:datetime;not null"`
-	EndDate     time.Time      `json:"end_date" gorm:"type:datetime;not null"`
-	StartTime   time.Time      `json:"start_time" gorm:"type:datetime;not null"`
-	EndTime     time.Time      `json:"end_time" gorm:"type:datetime;not null"`
+	Organizer   string         `json:"organizer" gorm:"type:varchar(255);not null;index:idx_events_search,class:FULLTEXT,priority:3"`
+	Title       string         `json:"title" gorm:"type:varchar(255);not null;index:idx_events_search,class:FULLTEXT,priority:1"`
+	Description string         `json:"description" gorm:"type:text;not null;index:idx_events_search,class:FULLTEXT,priority:2"`
+	StartsAt    time.Time      `json:"starts_at" gorm:"type:datetime;not null;index"`
+	EndsAt      time.Time      `json:"ends_at" gorm:"type:datetime;not null"`
+	Timezone    string         `json:"timezone" gorm:"type:varchar(64);not null"`
 	Location    string         `json:"location" gorm:"type:varchar(255);not null"`
+	Status      string         `json:"status" gorm:"type:enum('draft','published','cancelled');not null;default:'published'"`
+	SeriesID    *uuid.UUID     `json:"series_id" gorm:"type:char(36);index"`
+	VenueID     *uuid.UUID     `json:"venue_id" gorm:"type:char(36);index"`
+	SeatMapID   *uuid.UUID     `json:"seat_map_id" gorm:"type:char(36);index"`
 	CreatedAt   time.Time      `json:"created_at" gorm:"autoCreateTime"`
 	UpdatedAt   time.Time      `json:"updated_at" gorm:"autoUpdateTime"`
 	DeletedAt   gorm.DeletedAt `json:"deleted_at,omitempty" gorm:"index"`
 
-	Categories []Category `json:"categories" gorm:"foreignKey:EventID"`
+	Categories []Category   `json:"categories" gorm:"foreignKey:EventID"`
+	Venue      *Venue       `json:"venue" gorm:"foreignKey:VenueID"`
+	SeatMap    *SeatMap     `json:"seat_map" gorm:"foreignKey:SeatMapID"`
+	Series     *EventSeries `json:"series" gorm:"foreignKey:SeriesID"`
+	Images     []EventImage `json:"images" gorm:"foreignKey:EventID"`
+	Tags       []Tag        `json:"tags" gorm:"many2many:event_tags"`
 }
 
 func (e *Event) BeforeCreate(tx *gorm.DB) error {
@@ -30,3 +38,13 @@ func (e *Event) BeforeCreate(tx *gorm.DB) error {
 	}
 	return nil
 }
+
+// Zone returns the event's IANA time zone, falling back to UTC when the
+// stored name cannot be loaded.
+func (e *Event) Zone() *time.Location {
+	loc, err := time.LoadLocation(e.Timezone)
+	if err != nil {
+		return time.UTC
+	}
+	return loc
+}
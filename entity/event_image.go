@@ -0,0 +1,34 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// EventImage is an uploaded poster or gallery image of an event. The original
+// and its thumbnail are kept in storage; the URLs are resolved at upload time.
+type EventImage struct {
+	ID           uuid.UUID `json:"id" gorm:"type:char(36);primaryKey"`
+	EventID      uuid.UUID `json:"event_id" gorm:"type:char(36);not null;index"`
+	Kind         string    `json:"kind" gorm:"type:enum('poster','gallery');not null"`
+	Position     int       `json:"position" gorm:"not null;default:0"`
+	ContentType  string    `json:"content_type" gorm:"type:varchar(64);not null"`
+	Size         int64     `json:"size" gorm:"not null"`
+	Width        int       `json:"width" gorm:"not null"`
+	Height       int       `json:"height" gorm:"not null"`
+	Key          string    `json:"key" gorm:"column:storage_key;type:varchar(255);not null"`
+	URL          string    `json:"url" gorm:"type:varchar(512);not null"`
+	ThumbnailKey string    `json:"thumbnail_key" gorm:"type:varchar(255);not null"`
+	ThumbnailURL string    `json:"thumbnail_url" gorm:"type:varchar(512);not null"`
+	CreatedAt    time.Time `json:"created_at" gorm:"autoCreateTime"`
+	UpdatedAt    time.Time `json:"updated_at" gorm:"autoUpdateTime"`
+}
+
+func (i *EventImage) BeforeCreate(tx *gorm.DB) error {
+	if i.ID == uuid.Nil {
+		i.ID = uuid.New()
+	}
+	return nil
+}
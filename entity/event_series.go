@@ -0,0 +1,28 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type EventSeries struct {
+	ID              uuid.UUID      `json:"id" gorm:"type:char(36);primaryKey"`
+	Title           string         `json:"title" gorm:"type:varchar(255);not null"`
+	RRule           string         `json:"rrule" gorm:"column:rrule;type:varchar(255);not null"`
+	Timezone        string         `json:"timezone" gorm:"type:varchar(64);not null"`
+	TemplateEventID uuid.UUID      `json:"template_event_id" gorm:"type:char(36);not null"`
+	CreatedAt       time.Time      `json:"created_at" gorm:"autoCreateTime"`
+	UpdatedAt       time.Time      `json:"updated_at" gorm:"autoUpdateTime"`
+	DeletedAt       gorm.DeletedAt `json:"deleted_at,omitempty" gorm:"index"`
+
+	Events []Event `json:"events" gorm:"foreignKey:SeriesID"`
+}
+
+func (s *EventSeries) BeforeCreate(tx *gorm.DB) error {
+	if s.ID == uuid.Nil {
+		s.ID = uuid.New()
+	}
+	return nil
+}
@@ -9,33 +9,58 @@ import (
 
 type Order struct {
 	ID         uuid.UUID      `json:"id" gorm:"type:char(36);primaryKey"`
-	CategoryID uuid.UUID      `json:"category_id" gorm:"type:char(36);not null"`
+	EventID    uuid.UUID      `json:"event_id" gorm:"type:char(36);not null;index"`
 	UserID     uuid.UUID      `json:"user_id" gorm:"type:char(36);not null"`
 	InvoiceID  string         `json:"invoice_id" gorm:"type:varchar(255);not null;unique"`
-	Status     string         `json:"status" gorm:"type:enum('pending','paid','cancelled');not null;default:'pending'"`
+	Status     string         `json:"status" gorm:"type:enum('pending','paid','cancelled','expired','refunded');not null;default:'pending'"`
 	Quantity   int            `json:"quantity" gorm:"type:int;not null"`
 	TotalPrice float64        `json:"total_price" gorm:"type:decimal(10,2);not null"`
+	PaidAt     *time.Time     `json:"paid_at" gorm:"type:datetime"`
 	CreatedAt  time.Time      `json:"created_at" gorm:"autoCreateTime"`
 	UpdatedAt  time.Time      `json:"updated_at" gorm:"autoUpdateTime"`
 	DeletedAt  gorm.DeletedAt `json:"deleted_at,omitempty" gorm:"index"`
 
-	Category     *Category      `json:"category" gorm:"foreignKey:CategoryID"`
+	Event        *Event         `json:"event" gorm:"foreignKey:EventID"`
 	User         *User          `json:"user" gorm:"foreignKey:UserID"`
+	OrderItems   []*OrderItem   `json:"order_items" gorm:"foreignKey:OrderID;references:ID"`
 	OrderDetails []*OrderDetail `json:"order_details" gorm:"foreignKey:OrderID;references:ID"`
 }
 
+type OrderItem struct {
+	ID              uuid.UUID      `json:"id" gorm:"type:char(36);primaryKey"`
+	OrderID         uuid.UUID      `json:"order_id" gorm:"type:char(36);not null;index"`
+	CategoryID      uuid.UUID      `json:"category_id" gorm:"type:char(36);not null;index"`
+	PriceTierID     *uuid.UUID     `json:"price_tier_id" gorm:"type:char(36)"`
+	WaitlistEntryID *uuid.UUID     `json:"waitlist_entry_id" gorm:"type:char(36)"`
+	Quantity        int            `json:"quantity" gorm:"type:int;not null"`
+	UnitPrice       float64        `json:"unit_price" gorm:"type:decimal(10,2);not null"`
+	Subtotal        float64        `json:"subtotal" gorm:"type:decimal(10,2);not null"`
+	CreatedAt       time.Time      `json:"created_at" gorm:"autoCreateTime"`
+	UpdatedAt       time.Time      `json:"updated_at" gorm:"autoUpdateTime"`
+	DeletedAt       gorm.DeletedAt `json:"deleted_at,omitempty" gorm:"index"`
+
+	Order        *Order         `json:"order" gorm:"foreignKey:OrderID;references:ID"`
+	Category     *Category      `json:"category" gorm:"foreignKey:CategoryID"`
+	OrderDetails []*OrderDetail `json:"order_details" gorm:"foreignKey:OrderItemID;references:ID"`
+}
+
 type OrderDetail struct {
 	ID             uuid.UUID      `json:"id" gorm:"type:char(36);primaryKey"`
 	OrderID        uuid.UUID      `json:"order_id" gorm:"type:char(36);not null"`
+	OrderItemID    uuid.UUID      `json:"order_item_id" gorm:"type:char(36);not null;index"`
 	TicketCode     string         `json:"ticket_code" gorm:"type:varchar(255);not null;unique"`
 	FullName       string         `json:"full_name" gorm:"type:varchar(255);not null"`
 	IdentityNumber string         `json:"identity_number" gorm:"type:varchar(255);not null"`
 	Redeemed       bool           `json:"redeemed" gorm:"type:boolean;not null;default:false"`
+	RedeemedAt     *time.Time     `json:"redeemed_at" gorm:"type:datetime;index"`
+	SeatID         *uuid.UUID     `json:"seat_id" gorm:"type:char(36)"`
 	CreatedAt      time.Time      `json:"created_at" gorm:"autoCreateTime"`
 	UpdatedAt      time.Time      `json:"updated_at" gorm:"autoUpdateTime"`
 	DeletedAt      gorm.DeletedAt `json:"deleted_at,omitempty" gorm:"index"`
 
-	Order *Order `json:"order" gorm:"foreignKey:OrderID;references:ID"`
+	Order     *Order     `json:"order" gorm:"foreignKey:OrderID;references:ID"`
+	OrderItem *OrderItem `json:"order_item" gorm:"foreignKey:OrderItemID;references:ID"`
+	Seat      *Seat      `json:"seat" gorm:"foreignKey:SeatID"`
 }
 
 func (o *Order) BeforeCreate(tx *gorm.DB) error {
@@ -45,6 +70,13 @@ func (o *Order) BeforeCreate(tx *gorm.DB) error {
 	return nil
 }
 
+func (o *OrderItem) BeforeCreate(tx *gorm.DB) error {
+	if o.ID == uuid.Nil {
+		o.ID = uuid.New()
+	}
+	return nil
+}
+
 func (o *OrderDetail) BeforeCreate(tx *gorm.DB) error {
 	if o.ID == uuid.Nil {
 		o.ID = uuid.New()
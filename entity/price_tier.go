@@ -0,0 +1,46 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type PriceTier struct {
+	ID         uuid.UUID      `json:"id" gorm:"type:char(36);primaryKey"`
+	CategoryID uuid.UUID      `json:"category_id" gorm:"type:char(36);not null;index"`
+	Name       string         `json:"name" gorm:"type:varchar(255);not null"`
+	Price      float64        `json:"price" gorm:"type:decimal(10,2);not null"`
+	StartsAt   *time.Time     `json:"starts_at" gorm:"type:datetime"`
+	EndsAt     *time.Time     `json:"ends_at" gorm:"type:datetime"`
+	UnitLimit  int            `json:"unit_limit" gorm:"type:int;not null;default:0"`
+	Position   int            `json:"position" gorm:"type:int;not null;default:0"`
+	CreatedAt  time.Time      `json:"created_at" gorm:"autoCreateTime"`
+	UpdatedAt  time.Time      `json:"updated_at" gorm:"autoUpdateTime"`
+	DeletedAt  gorm.DeletedAt `json:"deleted_at,omitempty" gorm:"index"`
+}
+
+// PriceQuote is the outcome of evaluating a category's price schedule.
+// UnitsRemaining is only set when the current tier has a unit limit.
+type PriceQuote struct {
+	Price          float64
+	Current        *PriceTier
+	Next           *PriceTier
+	UnitsRemaining int
+}
+
+// PricedUnits is a number of units sold at one price, under Tier or at the
+// category's base price when Tier is nil.
+type PricedUnits struct {
+	Tier     *PriceTier
+	Price    float64
+	Quantity int
+}
+
+func (p *PriceTier) BeforeCreate(tx *gorm.DB) error {
+	if p.ID == uuid.Nil {
+		p.ID = uuid.New()
+	}
+	return nil
+}
@@ -0,0 +1,72 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// ReportSubscription emails a sales report on a cron schedule evaluated in
+// Timezone. A digest is sent at every scheduled time and covers the days
+// since the previous one; a final report is sent once, at the first
+// scheduled time after its event has ended.
+type ReportSubscription struct {
+	ID        uuid.UUID      `json:"id" gorm:"type:char(36);primaryKey"`
+	Recipient string         `json:"recipient" gorm:"type:varchar(255);not null"`
+	EventID   *uuid.UUID     `json:"event_id" gorm:"type:char(36);index"`
+	Kind      string         `json:"kind" gorm:"type:enum('digest','final');not null;default:'digest'"`
+	Schedule  string         `json:"schedule" gorm:"type:varchar(100);not null"`
+	Timezone  string         `json:"timezone" gorm:"type:varchar(64);not null"`
+	Format    string         `json:"format" gorm:"type:enum('csv','xlsx');not null;default:'csv'"`
+	Active    bool           `json:"active" gorm:"not null;default:true"`
+	NextRunAt *time.Time     `json:"next_run_at" gorm:"index"`
+	LastRunAt *time.Time     `json:"last_run_at"`
+	CreatedBy uuid.UUID      `json:"created_by" gorm:"type:char(36);not null"`
+	CreatedAt time.Time      `json:"created_at" gorm:"autoCreateTime"`
+	UpdatedAt time.Time      `json:"updated_at" gorm:"autoUpdateTime"`
+	DeletedAt gorm.DeletedAt `json:"deleted_at,omitempty" gorm:"index"`
+
+	Event *Event `json:"event,omitempty" gorm:"foreignKey:EventID"`
+}
+
+// ReportRun is one delivery of a report subscription. A digest covers the
+// UTC days from PeriodStart to PeriodEnd inclusive; a final report has no
+// period and covers all sales of its event.
+type ReportRun struct {
+	ID             uuid.UUID  `json:"id" gorm:"type:char(36);primaryKey"`
+	SubscriptionID uuid.UUID  `json:"subscription_id" gorm:"type:char(36);not null;index"`
+	ScheduledAt    time.Time  `json:"scheduled_at" gorm:"not null"`
+	PeriodStart    *time.Time `json:"period_start" gorm:"type:date"`
+	PeriodEnd      *time.Time `json:"period_end" gorm:"type:date"`
+	Status         string     `json:"status" gorm:"type:enum('running','sent','failed');not null;default:'running'"`
+	Error          string     `json:"error" gorm:"type:text"`
+	StartedAt      time.Time  `json:"started_at" gorm:"not null"`
+	FinishedAt     *time.Time `json:"finished_at"`
+
+	Subscription *ReportSubscription `json:"subscription,omitempty" gorm:"foreignKey:SubscriptionID"`
+}
+
+func (s *ReportSubscription) BeforeCreate(tx *gorm.DB) error {
+	if s.ID == uuid.Nil {
+		s.ID = uuid.New()
+	}
+	return nil
+}
+
+func (r *ReportRun) BeforeCreate(tx *gorm.DB) error {
+	if r.ID == uuid.Nil {
+		r.ID = uuid.New()
+	}
+	return nil
+}
+
+// Zone returns the location the schedule is evaluated in, or UTC if the
+// zone is unknown.
+func (s *ReportSubscription) Zone() *time.Location {
+	loc, err := time.LoadLocation(s.Timezone)
+	if err != nil {
+		return time.UTC
+	}
+	return loc
+}
@@ -0,0 +1,33 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// SalesDaily is the paid sales of one category on one UTC day, counted by
+// the day the orders were placed. Rows are adjusted as orders are paid,
+// cancelled or refunded, so reports can sum them instead of scanning every
+// order.
+type SalesDaily struct {
+	EventID     uuid.UUID `json:"event_id" gorm:"type:char(36);primaryKey;index"`
+	CategoryID  uuid.UUID `json:"category_id" gorm:"type:char(36);primaryKey"`
+	Day         time.Time `json:"day" gorm:"type:date;primaryKey;index"`
+	OrderCount  int64     `json:"order_count" gorm:"not null;default:0"`
+	TicketsSold int64     `json:"tickets_sold" gorm:"not null;default:0"`
+	Revenue     float64   `json:"revenue" gorm:"type:decimal(14,2);not null;default:0"`
+	UpdatedAt   time.Time `json:"updated_at" gorm:"autoUpdateTime"`
+}
+
+func (SalesDaily) TableName() string {
+	return "sales_daily"
+}
+
+// RollupState records when a rollup table was last rebuilt from the raw
+// tables. A rollup without a state has never been backfilled and only holds
+// the changes made since it was created.
+type RollupState struct {
+	Name      string    `json:"name" gorm:"type:varchar(64);primaryKey"`
+	RebuiltAt time.Time `json:"rebuilt_at" gorm:"not null"`
+}
@@ -0,0 +1,93 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type SeatMap struct {
+	ID          uuid.UUID      `json:"id" gorm:"type:char(36);primaryKey"`
+	VenueID     *uuid.UUID     `json:"venue_id" gorm:"type:char(36);index"`
+	Name        string         `json:"name" gorm:"type:varchar(255);not null"`
+	Description string         `json:"description" gorm:"type:text"`
+	CreatedAt   time.Time      `json:"created_at" gorm:"autoCreateTime"`
+	UpdatedAt   time.Time      `json:"updated_at" gorm:"autoUpdateTime"`
+	DeletedAt   gorm.DeletedAt `json:"deleted_at,omitempty" gorm:"index"`
+
+	Venue    *Venue        `json:"venue" gorm:"foreignKey:VenueID"`
+	Sections []SeatSection `json:"sections" gorm:"foreignKey:SeatMapID"`
+}
+
+type SeatSection struct {
+	ID        uuid.UUID      `json:"id" gorm:"type:char(36);primaryKey"`
+	SeatMapID uuid.UUID      `json:"seat_map_id" gorm:"type:char(36);not null;index"`
+	Name      string         `json:"name" gorm:"type:varchar(255);not null"`
+	Position  int            `json:"position" gorm:"type:int;not null;default:0"`
+	CreatedAt time.Time      `json:"created_at" gorm:"autoCreateTime"`
+	UpdatedAt time.Time      `json:"updated_at" gorm:"autoUpdateTime"`
+	DeletedAt gorm.DeletedAt `json:"deleted_at,omitempty" gorm:"index"`
+
+	Seats []Seat `json:"seats" gorm:"foreignKey:SectionID"`
+}
+
+type Seat struct {
+	ID         uuid.UUID      `json:"id" gorm:"type:char(36);primaryKey"`
+	SectionID  uuid.UUID      `json:"section_id" gorm:"type:char(36);not null;uniqueIndex:idx_section_row_number"`
+	Row        string         `json:"row" gorm:"type:varchar(10);not null;uniqueIndex:idx_section_row_number"`
+	Number     int            `json:"number" gorm:"type:int;not null;uniqueIndex:idx_section_row_number"`
+	Accessible bool           `json:"accessible" gorm:"type:boolean;not null;default:false"`
+	Companion  bool           `json:"companion" gorm:"type:boolean;not null;default:false"`
+	Position   int            `json:"position" gorm:"type:int;not null;default:0"`
+	CreatedAt  time.Time      `json:"created_at" gorm:"autoCreateTime"`
+	UpdatedAt  time.Time      `json:"updated_at" gorm:"autoUpdateTime"`
+	DeletedAt  gorm.DeletedAt `json:"deleted_at,omitempty" gorm:"index"`
+
+	Section *SeatSection `json:"section" gorm:"foreignKey:SectionID"`
+}
+
+// CategorySeat assigns a seat of the event's seat map to a category and
+// tracks its availability for that event. A seat is held by a pending order
+// and booked once the order is paid.
+type CategorySeat struct {
+	ID         uuid.UUID  `json:"id" gorm:"type:char(36);primaryKey"`
+	EventID    uuid.UUID  `json:"event_id" gorm:"type:char(36);not null;uniqueIndex:idx_event_seat"`
+	SeatID     uuid.UUID  `json:"seat_id" gorm:"type:char(36);not null;uniqueIndex:idx_event_seat"`
+	CategoryID uuid.UUID  `json:"category_id" gorm:"type:char(36);not null;index"`
+	Status     string     `json:"status" gorm:"type:enum('available','held','booked');not null;default:'available'"`
+	OrderID    *uuid.UUID `json:"order_id" gorm:"type:char(36);index"`
+	CreatedAt  time.Time  `json:"created_at" gorm:"autoCreateTime"`
+	UpdatedAt  time.Time  `json:"updated_at" gorm:"autoUpdateTime"`
+
+	Seat     *Seat     `json:"seat" gorm:"foreignKey:SeatID"`
+	Category *Category `json:"category" gorm:"foreignKey:CategoryID"`
+}
+
+func (s *SeatMap) BeforeCreate(tx *gorm.DB) error {
+	if s.ID == uuid.Nil {
+		s.ID = uuid.New()
+	}
+	return nil
+}
+
+func (s *SeatSection) BeforeCreate(tx *gorm.DB) error {
+	if s.ID == uuid.Nil {
+		s.ID = uuid.New()
+	}
+	return nil
+}
+
+func (s *Seat) BeforeCreate(tx *gorm.DB) error {
+	if s.ID == uuid.Nil {
+		s.ID = uuid.New()
+	}
+	return nil
+}
+
+func (c *CategorySeat) BeforeCreate(tx *gorm.DB) error {
+	if c.ID == uuid.Nil {
+		c.ID = uuid.New()
+	}
+	return nil
+}
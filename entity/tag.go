@@ -0,0 +1,29 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// Tag is a genre or topic events are browsed by. Tags form a two-level
+// hierarchy: a top-level tag such as "Music" may have child tags such as
+// "Jazz", and an event tagged with a child also shows up under its parent.
+type Tag struct {
+	ID        uuid.UUID  `json:"id" gorm:"type:char(36);primaryKey"`
+	Name      string     `json:"name" gorm:"type:varchar(100);not null"`
+	Slug      string     `json:"slug" gorm:"type:varchar(120);not null;uniqueIndex"`
+	ParentID  *uuid.UUID `json:"parent_id" gorm:"type:char(36);index"`
+	CreatedAt time.Time  `json:"created_at" gorm:"autoCreateTime"`
+	UpdatedAt time.Time  `json:"updated_at" gorm:"autoUpdateTime"`
+
+	Parent *Tag `json:"parent,omitempty" gorm:"foreignKey:ParentID"`
+}
+
+func (t *Tag) BeforeCreate(tx *gorm.DB) error {
+	if t.ID == uuid.Nil {
+		t.ID = uuid.New()
+	}
+	return nil
+}
@@ -0,0 +1,28 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type Venue struct {
+	ID        uuid.UUID      `json:"id" gorm:"type:char(36);primaryKey"`
+	Name      string         `json:"name" gorm:"type:varchar(255);not null"`
+	Address   string         `json:"address" gorm:"type:text;not null"`
+	Latitude  *float64       `json:"latitude" gorm:"type:decimal(10,7)"`
+	Longitude *float64       `json:"longitude" gorm:"type:decimal(10,7)"`
+	Timezone  string         `json:"timezone" gorm:"type:varchar(64);not null"`
+	Capacity  int            `json:"capacity" gorm:"type:int;not null"`
+	CreatedAt time.Time      `json:"created_at" gorm:"autoCreateTime"`
+	UpdatedAt time.Time      `json:"updated_at" gorm:"autoUpdateTime"`
+	DeletedAt gorm.DeletedAt `json:"deleted_at,omitempty" gorm:"index"`
+}
+
+func (v *Venue) BeforeCreate(tx *gorm.DB) error {
+	if v.ID == uuid.Nil {
+		v.ID = uuid.New()
+	}
+	return nil
+}
@@ -0,0 +1,31 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type WaitlistEntry struct {
+	ID             uuid.UUID      `json:"id" gorm:"type:char(36);primaryKey"`
+	CategoryID     uuid.UUID      `json:"category_id" gorm:"type:char(36);not null;index"`
+	UserID         uuid.UUID      `json:"user_id" gorm:"type:char(36);not null;index"`
+	Quantity       int            `json:"quantity" gorm:"type:int;not null"`
+	Status         string         `json:"status" gorm:"type:enum('waiting','notified','claimed','expired','cancelled');not null;default:'waiting'"`
+	NotifiedAt     *time.Time     `json:"notified_at" gorm:"type:datetime"`
+	ClaimExpiresAt *time.Time     `json:"claim_expires_at" gorm:"type:datetime"`
+	CreatedAt      time.Time      `json:"created_at" gorm:"autoCreateTime"`
+	UpdatedAt      time.Time      `json:"updated_at" gorm:"autoUpdateTime"`
+	DeletedAt      gorm.DeletedAt `json:"deleted_at,omitempty" gorm:"index"`
+
+	Category *Category `json:"category" gorm:"foreignKey:CategoryID"`
+	User     *User     `json:"user" gorm:"foreignKey:UserID"`
+}
+
+func (w *WaitlistEntry) BeforeCreate(tx *gorm.DB) error {
+	if w.ID == uuid.Nil {
+		w.ID = uuid.New()
+	}
+	return nil
+}
@@ -2,11 +2,15 @@ package main
 
 import (
 	"log"
+	"os"
+	"strconv"
 	"ticert/config"
 	"ticert/routes"
+	_ "time/tzdata"
 
 	"github.com/gin-gonic/gin"
 	"github.com/joho/godotenv"
+	"gorm.io/gorm"
 )
 
 func main() {
@@ -15,15 +19,60 @@ func main() {
 		log.Println("Warning: .env file not found:", err)
 	}
 
+	// Serve unless another command is given
+	command := "serve"
+	if len(os.Args) > 1 {
+		command = os.Args[1]
+	}
+
+	// Manage the database schema before connecting to it
+	if command == "migrate" {
+		runMigrateCommand(os.Args[2:])
+		return
+	}
+
 	// Initialize database
 	db := config.InitDatabase()
 
-	// Auto migrate database
-	config.AutoMigrate(db)
+	// Apply pending migrations
+	if migrateOnStart, _ := strconv.ParseBool(config.GetConfig().DBMigrateOnStart); migrateOnStart {
+		config.Migrate(db)
+	}
+
+	switch command {
+	case "serve":
+		if len(os.Args) > 2 {
+			log.Fatal(serveUsage)
+		}
+		serve(db)
+	case "rollup":
+		runRollupCommand(db, os.Args[2:])
+	case "admin":
+		runAdminCommand(db, os.Args[2:])
+	case "user":
+		runUserCommand(db, os.Args[2:])
+	case "orders":
+		runOrdersCommand(db, os.Args[2:])
+	case "reindex":
+		runReindexCommand(db, os.Args[2:])
+	case "seed":
+		runSeedCommand(db, os.Args[2:])
+	default:
+		log.Fatalf("Unknown command %q", command)
+	}
+}
 
+// serve runs the HTTP server and its background workers.
+func serve(db *gorm.DB) {
 	// Initialize Redis
 	config.InitRedis(config.GetConfig())
 
+	// Initialize file storage
+	config.InitStorage(config.GetConfig())
+
+	// Initialize mail delivery
+	config.InitMailer(config.GetConfig())
+
 	// Setup Gin router
 	r := gin.Default()
 
@@ -0,0 +1,21 @@
+DROP TABLE IF EXISTS `report_runs`;
+DROP TABLE IF EXISTS `report_subscriptions`;
+DROP TABLE IF EXISTS `rollup_states`;
+DROP TABLE IF EXISTS `sales_daily`;
+DROP TABLE IF EXISTS `order_details`;
+DROP TABLE IF EXISTS `order_items`;
+DROP TABLE IF EXISTS `orders`;
+DROP TABLE IF EXISTS `category_seats`;
+DROP TABLE IF EXISTS `waitlist_entries`;
+DROP TABLE IF EXISTS `price_tiers`;
+DROP TABLE IF EXISTS `categories`;
+DROP TABLE IF EXISTS `event_images`;
+DROP TABLE IF EXISTS `event_tags`;
+DROP TABLE IF EXISTS `events`;
+DROP TABLE IF EXISTS `tags`;
+DROP TABLE IF EXISTS `event_series`;
+DROP TABLE IF EXISTS `seats`;
+DROP TABLE IF EXISTS `seat_sections`;
+DROP TABLE IF EXISTS `seat_maps`;
+DROP TABLE IF EXISTS `venues`;
+DROP TABLE IF EXISTS `users`;
@@ -0,0 +1,355 @@
+-- Schema of the tables as the GORM auto-migration left them. Tables are only
+-- created when missing, so a database adopted from the auto-migration keeps
+-- its tables and data.
+
+CREATE TABLE IF NOT EXISTS `users` (
+  `id` char(36),
+  `email` varchar(255) NOT NULL,
+  `password` varchar(255) NOT NULL,
+  `first_name` varchar(255) NOT NULL,
+  `last_name` varchar(255) NOT NULL,
+  `role` enum('user','admin') NOT NULL DEFAULT 'user',
+  `created_at` datetime(3) NULL,
+  `updated_at` datetime(3) NULL,
+  `deleted_at` datetime(3) NULL,
+  PRIMARY KEY (`id`),
+  INDEX `idx_users_email` (`email`),
+  INDEX `idx_users_deleted_at` (`deleted_at`)
+);
+
+CREATE TABLE IF NOT EXISTS `venues` (
+  `id` char(36),
+  `name` varchar(255) NOT NULL,
+  `address` text NOT NULL,
+  `latitude` decimal(10,7),
+  `longitude` decimal(10,7),
+  `timezone` varchar(64) NOT NULL,
+  `capacity` bigint NOT NULL,
+  `created_at` datetime(3) NULL,
+  `updated_at` datetime(3) NULL,
+  `deleted_at` datetime(3) NULL,
+  PRIMARY KEY (`id`),
+  INDEX `idx_venues_deleted_at` (`deleted_at`)
+);
+
+CREATE TABLE IF NOT EXISTS `seat_maps` (
+  `id` char(36),
+  `venue_id` char(36),
+  `name` varchar(255) NOT NULL,
+  `description` text,
+  `created_at` datetime(3) NULL,
+  `updated_at` datetime(3) NULL,
+  `deleted_at` datetime(3) NULL,
+  PRIMARY KEY (`id`),
+  INDEX `idx_seat_maps_venue_id` (`venue_id`),
+  INDEX `idx_seat_maps_deleted_at` (`deleted_at`),
+  CONSTRAINT `fk_seat_maps_venue` FOREIGN KEY (`venue_id`) REFERENCES `venues`(`id`)
+);
+
+CREATE TABLE IF NOT EXISTS `seat_sections` (
+  `id` char(36),
+  `seat_map_id` char(36) NOT NULL,
+  `name` varchar(255) NOT NULL,
+  `position` bigint NOT NULL DEFAULT 0,
+  `created_at` datetime(3) NULL,
+  `updated_at` datetime(3) NULL,
+  `deleted_at` datetime(3) NULL,
+  PRIMARY KEY (`id`),
+  INDEX `idx_seat_sections_seat_map_id` (`seat_map_id`),
+  INDEX `idx_seat_sections_deleted_at` (`deleted_at`),
+  CONSTRAINT `fk_seat_maps_sections` FOREIGN KEY (`seat_map_id`) REFERENCES `seat_maps`(`id`)
+);
+
+CREATE TABLE IF NOT EXISTS `seats` (
+  `id` char(36),
+  `section_id` char(36) NOT NULL,
+  `row` varchar(10) NOT NULL,
+  `number` bigint NOT NULL,
+  `accessible` boolean NOT NULL DEFAULT false,
+  `companion` boolean NOT NULL DEFAULT false,
+  `position` bigint NOT NULL DEFAULT 0,
+  `created_at` datetime(3) NULL,
+  `updated_at` datetime(3) NULL,
+  `deleted_at` datetime(3) NULL,
+  PRIMARY KEY (`id`),
+  UNIQUE INDEX `idx_section_row_number` (`section_id`,`row`,`number`),
+  INDEX `idx_seats_deleted_at` (`deleted_at`),
+  CONSTRAINT `fk_seat_sections_seats` FOREIGN KEY (`section_id`) REFERENCES `seat_sections`(`id`)
+);
+
+CREATE TABLE IF NOT EXISTS `event_series` (
+  `id` char(36),
+  `title` varchar(255) NOT NULL,
+  `rrule` varchar(255) NOT NULL,
+  `timezone` varchar(64) NOT NULL,
+  `template_event_id` char(36) NOT NULL,
+  `created_at` datetime(3) NULL,
+  `updated_at` datetime(3) NULL,
+  `deleted_at` datetime(3) NULL,
+  PRIMARY KEY (`id`),
+  INDEX `idx_event_series_deleted_at` (`deleted_at`)
+);
+
+CREATE TABLE IF NOT EXISTS `tags` (
+  `id` char(36),
+  `name` varchar(100) NOT NULL,
+  `slug` varchar(120) NOT NULL,
+  `parent_id` char(36),
+  `created_at` datetime(3) NULL,
+  `updated_at` datetime(3) NULL,
+  PRIMARY KEY (`id`),
+  UNIQUE INDEX `idx_tags_slug` (`slug`),
+  INDEX `idx_tags_parent_id` (`parent_id`),
+  CONSTRAINT `fk_tags_parent` FOREIGN KEY (`parent_id`) REFERENCES `tags`(`id`)
+);
+
+CREATE TABLE IF NOT EXISTS `events` (
+  `id` char(36),
+  `organizer` varchar(255) NOT NULL,
+  `title` varchar(255) NOT NULL,
+  `description` text NOT NULL,
+  `starts_at` datetime NOT NULL,
+  `ends_at` datetime NOT NULL,
+  `timezone` varchar(64) NOT NULL,
+  `location` varchar(255) NOT NULL,
+  `status` enum('draft','published','cancelled') NOT NULL DEFAULT 'published',
+  `series_id` char(36),
+  `venue_id` char(36),
+  `seat_map_id` char(36),
+  `created_at` datetime(3) NULL,
+  `updated_at` datetime(3) NULL,
+  `deleted_at` datetime(3) NULL,
+  PRIMARY KEY (`id`),
+  FULLTEXT INDEX `idx_events_search` (`title`,`description`,`organizer`),
+  INDEX `idx_events_starts_at` (`starts_at`),
+  INDEX `idx_events_series_id` (`series_id`),
+  INDEX `idx_events_venue_id` (`venue_id`),
+  INDEX `idx_events_seat_map_id` (`seat_map_id`),
+  INDEX `idx_events_deleted_at` (`deleted_at`),
+  CONSTRAINT `fk_event_series_events` FOREIGN KEY (`series_id`) REFERENCES `event_series`(`id`),
+  CONSTRAINT `fk_events_venue` FOREIGN KEY (`venue_id`) REFERENCES `venues`(`id`),
+  CONSTRAINT `fk_events_seat_map` FOREIGN KEY (`seat_map_id`) REFERENCES `seat_maps`(`id`)
+);
+
+CREATE TABLE IF NOT EXISTS `event_tags` (
+  `event_id` char(36),
+  `tag_id` char(36),
+  PRIMARY KEY (`event_id`,`tag_id`)
+);
+
+CREATE TABLE IF NOT EXISTS `event_images` (
+  `id` char(36),
+  `event_id` char(36) NOT NULL,
+  `kind` enum('poster','gallery') NOT NULL,
+  `position` bigint NOT NULL DEFAULT 0,
+  `content_type` varchar(64) NOT NULL,
+  `size` bigint NOT NULL,
+  `width` bigint NOT NULL,
+  `height` bigint NOT NULL,
+  `storage_key` varchar(255) NOT NULL,
+  `url` varchar(512) NOT NULL,
+  `thumbnail_key` varchar(255) NOT NULL,
+  `thumbnail_url` varchar(512) NOT NULL,
+  `created_at` datetime(3) NULL,
+  `updated_at` datetime(3) NULL,
+  PRIMARY KEY (`id`),
+  INDEX `idx_event_images_event_id` (`event_id`),
+  CONSTRAINT `fk_events_images` FOREIGN KEY (`event_id`) REFERENCES `events`(`id`)
+);
+
+CREATE TABLE IF NOT EXISTS `categories` (
+  `id` char(36),
+  `event_id` char(36) NOT NULL,
+  `name` varchar(255) NOT NULL,
+  `price` decimal(10,2) NOT NULL,
+  `event_date` datetime NOT NULL,
+  `quantity` bigint NOT NULL,
+  `status` enum('available','sold') NOT NULL DEFAULT 'available',
+  `inventory_strategy` enum('database','redis') NOT NULL DEFAULT 'database',
+  `seating_mode` enum('general','reserved') NOT NULL DEFAULT 'general',
+  `sales_start_at` datetime,
+  `sales_end_at` datetime,
+  `min_per_order` bigint NOT NULL DEFAULT 1,
+  `max_per_order` bigint NOT NULL DEFAULT 0,
+  `max_per_user` bigint NOT NULL DEFAULT 0,
+  `waitlist_held` bigint NOT NULL DEFAULT 0,
+  `created_at` datetime(3) NULL,
+  `updated_at` datetime(3) NULL,
+  `deleted_at` datetime(3) NULL,
+  PRIMARY KEY (`id`),
+  INDEX `idx_categories_deleted_at` (`deleted_at`),
+  CONSTRAINT `fk_events_categories` FOREIGN KEY (`event_id`) REFERENCES `events`(`id`)
+);
+
+CREATE TABLE IF NOT EXISTS `price_tiers` (
+  `id` char(36),
+  `category_id` char(36) NOT NULL,
+  `name` varchar(255) NOT NULL,
+  `price` decimal(10,2) NOT NULL,
+  `starts_at` datetime,
+  `ends_at` datetime,
+  `unit_limit` bigint NOT NULL DEFAULT 0,
+  `position` bigint NOT NULL DEFAULT 0,
+  `created_at` datetime(3) NULL,
+  `updated_at` datetime(3) NULL,
+  `deleted_at` datetime(3) NULL,
+  PRIMARY KEY (`id`),
+  INDEX `idx_price_tiers_category_id` (`category_id`),
+  INDEX `idx_price_tiers_deleted_at` (`deleted_at`),
+  CONSTRAINT `fk_categories_price_tiers` FOREIGN KEY (`category_id`) REFERENCES `categories`(`id`)
+);
+
+CREATE TABLE IF NOT EXISTS `waitlist_entries` (
+  `id` char(36),
+  `category_id` char(36) NOT NULL,
+  `user_id` char(36) NOT NULL,
+  `quantity` bigint NOT NULL,
+  `status` enum('waiting','notified','claimed','expired','cancelled') NOT NULL DEFAULT 'waiting',
+  `notified_at` datetime,
+  `claim_expires_at` datetime,
+  `created_at` datetime(3) NULL,
+  `updated_at` datetime(3) NULL,
+  `deleted_at` datetime(3) NULL,
+  PRIMARY KEY (`id`),
+  INDEX `idx_waitlist_entries_category_id` (`category_id`),
+  INDEX `idx_waitlist_entries_user_id` (`user_id`),
+  INDEX `idx_waitlist_entries_deleted_at` (`deleted_at`),
+  CONSTRAINT `fk_waitlist_entries_category` FOREIGN KEY (`category_id`) REFERENCES `categories`(`id`),
+  CONSTRAINT `fk_waitlist_entries_user` FOREIGN KEY (`user_id`) REFERENCES `users`(`id`)
+);
+
+CREATE TABLE IF NOT EXISTS `category_seats` (
+  `id` char(36),
+  `event_id` char(36) NOT NULL,
+  `seat_id` char(36) NOT NULL,
+  `category_id` char(36) NOT NULL,
+  `status` enum('available','held','booked') NOT NULL DEFAULT 'available',
+  `order_id` char(36),
+  `created_at` datetime(3) NULL,
+  `updated_at` datetime(3) NULL,
+  PRIMARY KEY (`id`),
+  UNIQUE INDEX `idx_event_seat` (`event_id`,`seat_id`),
+  INDEX `idx_category_seats_category_id` (`category_id`),
+  INDEX `idx_category_seats_order_id` (`order_id`),
+  CONSTRAINT `fk_category_seats_seat` FOREIGN KEY (`seat_id`) REFERENCES `seats`(`id`),
+  CONSTRAINT `fk_category_seats_category` FOREIGN KEY (`category_id`) REFERENCES `categories`(`id`)
+);
+
+CREATE TABLE IF NOT EXISTS `orders` (
+  `id` char(36),
+  `event_id` char(36) NOT NULL,
+  `user_id` char(36) NOT NULL,
+  `invoice_id` varchar(255) NOT NULL,
+  `status` enum('pending','paid','cancelled','expired','refunded') NOT NULL DEFAULT 'pending',
+  `quantity` bigint NOT NULL,
+  `total_price` decimal(10,2) NOT NULL,
+  `paid_at` datetime,
+  `created_at` datetime(3) NULL,
+  `updated_at` datetime(3) NULL,
+  `deleted_at` datetime(3) NULL,
+  PRIMARY KEY (`id`),
+  INDEX `idx_orders_event_id` (`event_id`),
+  INDEX `idx_orders_deleted_at` (`deleted_at`),
+  CONSTRAINT `fk_orders_event` FOREIGN KEY (`event_id`) REFERENCES `events`(`id`),
+  CONSTRAINT `fk_orders_user` FOREIGN KEY (`user_id`) REFERENCES `users`(`id`),
+  CONSTRAINT `uni_orders_invoice_id` UNIQUE (`invoice_id`)
+);
+
+CREATE TABLE IF NOT EXISTS `order_items` (
+  `id` char(36),
+  `order_id` char(36) NOT NULL,
+  `category_id` char(36) NOT NULL,
+  `price_tier_id` char(36),
+  `waitlist_entry_id` char(36),
+  `quantity` bigint NOT NULL,
+  `unit_price` decimal(10,2) NOT NULL,
+  `subtotal` decimal(10,2) NOT NULL,
+  `created_at` datetime(3) NULL,
+  `updated_at` datetime(3) NULL,
+  `deleted_at` datetime(3) NULL,
+  PRIMARY KEY (`id`),
+  INDEX `idx_order_items_order_id` (`order_id`),
+  INDEX `idx_order_items_category_id` (`category_id`),
+  INDEX `idx_order_items_deleted_at` (`deleted_at`),
+  CONSTRAINT `fk_orders_order_items` FOREIGN KEY (`order_id`) REFERENCES `orders`(`id`),
+  CONSTRAINT `fk_order_items_category` FOREIGN KEY (`category_id`) REFERENCES `categories`(`id`)
+);
+
+CREATE TABLE IF NOT EXISTS `order_details` (
+  `id` char(36),
+  `order_id` char(36) NOT NULL,
+  `order_item_id` char(36) NOT NULL,
+  `ticket_code` varchar(255) NOT NULL,
+  `full_name` varchar(255) NOT NULL,
+  `identity_number` varchar(255) NOT NULL,
+  `redeemed` boolean NOT NULL DEFAULT false,
+  `redeemed_at` datetime,
+  `seat_id` char(36),
+  `created_at` datetime(3) NULL,
+  `updated_at` datetime(3) NULL,
+  `deleted_at` datetime(3) NULL,
+  PRIMARY KEY (`id`),
+  INDEX `idx_order_details_order_item_id` (`order_item_id`),
+  INDEX `idx_order_details_redeemed_at` (`redeemed_at`),
+  INDEX `idx_order_details_deleted_at` (`deleted_at`),
+  CONSTRAINT `fk_order_details_seat` FOREIGN KEY (`seat_id`) REFERENCES `seats`(`id`),
+  CONSTRAINT `fk_order_items_order_details` FOREIGN KEY (`order_item_id`) REFERENCES `order_items`(`id`),
+  CONSTRAINT `fk_orders_order_details` FOREIGN KEY (`order_id`) REFERENCES `orders`(`id`),
+  CONSTRAINT `uni_order_details_ticket_code` UNIQUE (`ticket_code`)
+);
+
+CREATE TABLE IF NOT EXISTS `sales_daily` (
+  `event_id` char(36),
+  `category_id` char(36),
+  `day` date,
+  `order_count` bigint NOT NULL DEFAULT 0,
+  `tickets_sold` bigint NOT NULL DEFAULT 0,
+  `revenue` decimal(14,2) NOT NULL DEFAULT 0,
+  `updated_at` datetime(3) NULL,
+  PRIMARY KEY (`event_id`,`category_id`,`day`),
+  INDEX `idx_sales_daily_event_id` (`event_id`),
+  INDEX `idx_sales_daily_day` (`day`)
+);
+
+CREATE TABLE IF NOT EXISTS `rollup_states` (
+  `name` varchar(64),
+  `rebuilt_at` datetime(3) NOT NULL,
+  PRIMARY KEY (`name`)
+);
+
+CREATE TABLE IF NOT EXISTS `report_subscriptions` (
+  `id` char(36),
+  `recipient` varchar(255) NOT NULL,
+  `event_id` char(36),
+  `kind` enum('digest','final') NOT NULL DEFAULT 'digest',
+  `schedule` varchar(100) NOT NULL,
+  `timezone` varchar(64) NOT NULL,
+  `format` enum('csv','xlsx') NOT NULL DEFAULT 'csv',
+  `active` boolean NOT NULL DEFAULT true,
+  `next_run_at` datetime(3) NULL,
+  `last_run_at` datetime(3) NULL,
+  `created_by` char(36) NOT NULL,
+  `created_at` datetime(3) NULL,
+  `updated_at` datetime(3) NULL,
+  `deleted_at` datetime(3) NULL,
+  PRIMARY KEY (`id`),
+  INDEX `idx_report_subscriptions_event_id` (`event_id`),
+  INDEX `idx_report_subscriptions_next_run_at` (`next_run_at`),
+  INDEX `idx_report_subscriptions_deleted_at` (`deleted_at`),
+  CONSTRAINT `fk_report_subscriptions_event` FOREIGN KEY (`event_id`) REFERENCES `events`(`id`)
+);
+
+CREATE TABLE IF NOT EXISTS `report_runs` (
+  `id` char(36),
+  `subscription_id` char(36) NOT NULL,
+  `scheduled_at` datetime(3) NOT NULL,
+  `period_start` date,
+  `period_end` date,
+  `status` enum('running','sent','failed') NOT NULL DEFAULT 'running',
+  `error` text,
+  `started_at` datetime(3) NOT NULL,
+  `finished_at` datetime(3) NULL,
+  PRIMARY KEY (`id`),
+  INDEX `idx_report_runs_subscription_id` (`subscription_id`),
+  CONSTRAINT `fk_report_runs_subscription` FOREIGN KEY (`subscription_id`) REFERENCES `report_subscriptions`(`id`)
+);
@@ -0,0 +1,8 @@
+// Package migrations embeds the versioned SQL migrations of the database
+// schema. New migrations are created with "go run . migrate create <name>".
+package migrations
+
+import "embed"
+
+//go:embed *.sql
+var FS embed.FS
@@ -0,0 +1,99 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// SalesBucketData holds the sales figures of one time bucket. Bucket is the
+// bucket's start as local wall clock time, formatted "2006-01-02 15:04:05".
+type SalesBucketData struct {
+	Bucket        string  `json:"bucket"`
+	TicketsSold   int64   `json:"tickets_sold"`
+	Revenue       float64 `json:"revenue"`
+	OrdersCreated int64   `json:"orders_created"`
+	Cancellations int64   `json:"cancellations"`
+	Redemptions   int64   `json:"redemptions"`
+}
+
+// FunnelData counts the orders created in a period by their current status.
+// Paid includes orders refunded since. Revenue is what paid orders brought
+// in; LostRevenue is the value of the orders cancelled or left to expire.
+type FunnelData struct {
+	OrdersCreated int64   `json:"orders_created"`
+	Pending       int64   `json:"pending"`
+	Paid          int64   `json:"paid"`
+	Refunded      int64   `json:"refunded"`
+	Cancelled     int64   `json:"cancelled"`
+	Expired       int64   `json:"expired"`
+	Revenue       float64 `json:"revenue"`
+	LostRevenue   float64 `json:"lost_revenue"`
+}
+
+// CategoryFunnelData is the funnel of the orders containing a category.
+// Revenue and LostRevenue only count the category's order items.
+type CategoryFunnelData struct {
+	CategoryID   uuid.UUID `json:"category_id"`
+	CategoryName string    `json:"category_name"`
+	EventID      uuid.UUID `json:"event_id"`
+	EventTitle   string    `json:"event_title"`
+	FunnelData
+}
+
+// TimeToPayData is the median number of seconds between creating and paying
+// an order. CategoryID is nil for the median over all orders.
+type TimeToPayData struct {
+	CategoryID    *uuid.UUID `json:"category_id"`
+	MedianSeconds float64    `json:"median_seconds"`
+}
+
+// CustomerData sums up the paid orders of a customer. The purchase times are
+// nil for customers without paid orders.
+type CustomerData struct {
+	UserID          uuid.UUID  `json:"user_id"`
+	Email           string     `json:"email"`
+	FirstName       string     `json:"first_name"`
+	LastName        string     `json:"last_name"`
+	Orders          int64      `json:"orders"`
+	Events          int64      `json:"events"`
+	TicketsBought   int64      `json:"tickets_bought"`
+	Spend           float64    `json:"spend"`
+	FirstPurchaseAt *time.Time `json:"first_purchase_at"`
+	LastPurchaseAt  *time.Time `json:"last_purchase_at"`
+}
+
+// RepeatPurchaseData counts the customers with paid orders and those among
+// them who bought tickets for more than one event. Events sums the number of
+// events each customer bought tickets for.
+type RepeatPurchaseData struct {
+	Customers       int64 `json:"customers"`
+	RepeatCustomers int64 `json:"repeat_customers"`
+	Events          int64 `json:"events"`
+}
+
+// EventBuyersData splits the buyers of an event into first-time buyers and
+// returning buyers, who had paid for another event before.
+type EventBuyersData struct {
+	EventID         uuid.UUID `json:"event_id"`
+	EventTitle      string    `json:"event_title"`
+	StartsAt        time.Time `json:"starts_at"`
+	Buyers          int64     `json:"buyers"`
+	FirstTimeBuyers int64     `json:"first_time_buyers"`
+	ReturningBuyers int64     `json:"returning_buyers"`
+	FirstTimeSpend  float64   `json:"first_time_spend"`
+	ReturningSpend  float64   `json:"returning_spend"`
+}
+
+// CustomerEventData sums up a customer's paid orders for one event.
+type CustomerEventData struct {
+	EventID         uuid.UUID `json:"event_id"`
+	EventTitle      string    `json:"event_title"`
+	StartsAt        time.Time `json:"starts_at"`
+	Orders          int64     `json:"orders"`
+	TicketsBought   int64     `json:"tickets_bought"`
+	CheckedIn       int64     `json:"checked_in"`
+	Spend           float64   `json:"spend"`
+	FirstPurchaseAt time.Time `json:"first_purchase_at"`
+	LastPurchaseAt  time.Time `json:"last_purchase_at"`
+}
@@ -1,13 +1,20 @@
 package models
 
 import (
+	"time"
+
 	"github.com/google/uuid"
 )
 
 type EventReportData struct {
-	EventID      uuid.UUID `json:"event_id"`
-	TotalSold    int64     `json:"total_sold"`
-	TotalRevenue float64   `json:"total_revenue"`
+	EventID        uuid.UUID `json:"event_id"`
+	EventCreatedAt time.Time `json:"-"`
+	EventTitle     string    `json:"event_title"`
+	EventStartsAt  time.Time `json:"event_starts_at"`
+	EventEndsAt    time.Time `json:"event_ends_at"`
+	EventTimezone  string    `json:"event_timezone"`
+	TotalSold      int64     `json:"total_sold"`
+	TotalRevenue   float64   `json:"total_revenue"`
 }
 
 type EventTicketStats struct {
@@ -16,10 +23,45 @@ type EventTicketStats struct {
 	TotalRevenue float64 `json:"total_revenue"`
 }
 
-type CategoryTicketStats struct {
-	TotalOrders  int64   `json:"total_orders"`
-	TotalTickets int64   `json:"total_tickets"`
-	TotalRevenue float64 `json:"total_revenue"`
+// CategoryReportData is a category with its sales figures.
+type CategoryReportData struct {
+	CategoryID     uuid.UUID `json:"category_id"`
+	EventID        uuid.UUID `json:"event_id"`
+	CategoryName   string    `json:"category_name"`
+	RemainingStock int       `json:"remaining_stock"`
+	Status         string    `json:"status"`
+	TotalOrders    int64     `json:"total_orders"`
+	TotalTickets   int64     `json:"total_tickets"`
+	TotalRevenue   float64   `json:"total_revenue"`
+}
+
+// EventAttendanceData is an event with its paid tickets and how many of them
+// were checked in.
+type EventAttendanceData struct {
+	EventID       uuid.UUID `json:"event_id"`
+	EventTitle    string    `json:"event_title"`
+	EventStartsAt time.Time `json:"event_starts_at"`
+	EventEndsAt   time.Time `json:"event_ends_at"`
+	EventTimezone string    `json:"event_timezone"`
+	TicketsSold   int64     `json:"tickets_sold"`
+	CheckedIn     int64     `json:"checked_in"`
+}
+
+// CategoryAttendanceData is a category with its paid tickets and how many of
+// them were checked in.
+type CategoryAttendanceData struct {
+	CategoryID   uuid.UUID `json:"category_id"`
+	EventID      uuid.UUID `json:"event_id"`
+	CategoryName string    `json:"category_name"`
+	TicketsSold  int64     `json:"tickets_sold"`
+	CheckedIn    int64     `json:"checked_in"`
+}
+
+// CheckInMinuteData counts the check-ins made in one minute, counted from
+// the start of the event. Minutes before the start are negative.
+type CheckInMinuteData struct {
+	ArrivalMinute int   `json:"arrival_minute"`
+	CheckIns      int64 `json:"check_ins"`
 }
 
 type SummaryReportData struct {
@@ -28,3 +70,50 @@ type SummaryReportData struct {
 	TotalEvents      int64   `json:"total_events"`
 	TotalCategories  int64   `json:"total_categories"`
 }
+
+// OrderExportRow is an order flattened into one export row. Items lists the
+// order's categories and quantities, such as "VIP x2; Regular x1".
+type OrderExportRow struct {
+	ID            uuid.UUID `json:"id"`
+	InvoiceID     string    `json:"invoice_id"`
+	Status        string    `json:"status"`
+	Quantity      int       `json:"quantity"`
+	TotalPrice    float64   `json:"total_price"`
+	EventID       uuid.UUID `json:"event_id"`
+	EventTitle    string    `json:"event_title"`
+	UserID        uuid.UUID `json:"user_id"`
+	UserFirstName string    `json:"user_first_name"`
+	UserLastName  string    `json:"user_last_name"`
+	UserEmail     string    `json:"user_email"`
+	Items         string    `json:"items"`
+	CreatedAt     time.Time `json:"created_at"`
+	UpdatedAt     time.Time `json:"updated_at"`
+}
+
+// SalesRollupRow is the paid sales of a category on one UTC day, either as
+// stored in the sales_daily rollup or as aggregated from the orders.
+type SalesRollupRow struct {
+	EventID     uuid.UUID `json:"event_id"`
+	CategoryID  uuid.UUID `json:"category_id"`
+	Day         time.Time `json:"day"`
+	OrderCount  int64     `json:"order_count"`
+	TicketsSold int64     `json:"tickets_sold"`
+	Revenue     float64   `json:"revenue"`
+}
+
+// SalesRollupMismatch is a category day whose rollup figures differ from the
+// figures aggregated from the orders. A missing side has zero figures.
+type SalesRollupMismatch struct {
+	EventID    uuid.UUID      `json:"event_id"`
+	CategoryID uuid.UUID      `json:"category_id"`
+	Day        time.Time      `json:"day"`
+	Rollup     SalesRollupRow `json:"rollup"`
+	Raw        SalesRollupRow `json:"raw"`
+}
+
+// SalesRollupCheck is the outcome of comparing the rollup with the orders.
+type SalesRollupCheck struct {
+	RebuiltAt  *time.Time            `json:"rebuilt_at"`
+	Rows       int                   `json:"rows"`
+	Mismatches []SalesRollupMismatch `json:"mismatches"`
+}
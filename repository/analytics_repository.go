@@ -0,0 +1,390 @@
+package repository
+
+import (
+	"sync"
+	"ticert/models"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// SalesSeriesFilter selects the orders created in [From, To) and the bucket
+// size they are grouped by. Buckets follow the wall clock of Location.
+type SalesSeriesFilter struct {
+	Interval   string
+	From       time.Time
+	To         time.Time
+	Location   *time.Location
+	EventID    *uuid.UUID
+	CategoryID *uuid.UUID
+}
+
+// FunnelFilter selects the orders created in [From, To).
+type FunnelFilter struct {
+	From    time.Time
+	To      time.Time
+	EventID *uuid.UUID
+}
+
+// CustomerFilter selects the paid orders created in [From, To), or at any
+// time when From is zero, of customers whose account still exists.
+type CustomerFilter struct {
+	From    time.Time
+	To      time.Time
+	EventID *uuid.UUID
+}
+
+type AnalyticsRepository interface {
+	GetSalesSeries(filter SalesSeriesFilter) ([]*models.SalesBucketData, error)
+	GetFunnel(filter FunnelFilter) (*models.FunnelData, error)
+	GetCategoryFunnels(filter FunnelFilter) ([]*models.CategoryFunnelData, error)
+	GetTimeToPay(filter FunnelFilter) ([]*models.TimeToPayData, error)
+	GetTopCustomers(filter CustomerFilter, sortBy string, page, limit int) ([]*models.CustomerData, int64, error)
+	GetRepeatPurchases(filter CustomerFilter) (*models.RepeatPurchaseData, error)
+	GetEventBuyers(filter CustomerFilter, page, limit int) ([]*models.EventBuyersData, int64, error)
+	GetCustomer(userID uuid.UUID) (*models.CustomerData, error)
+	GetCustomerFunnel(userID uuid.UUID) (*models.FunnelData, error)
+	GetCustomerEvents(userID uuid.UUID) ([]*models.CustomerEventData, error)
+}
+
+type analyticsRepository struct {
+	db *gorm.DB
+
+	namedZonesOnce sync.Once
+	namedZones     bool
+}
+
+func NewAnalyticsRepository(db *gorm.DB) AnalyticsRepository {
+	return &analyticsRepository{db: db}
+}
+
+// bucketFormats truncate a local datetime to the start of its bucket. Weeks
+// start on Monday.
+var bucketFormats = map[string]string{
+	"hour":  "DATE_FORMAT(t.local_at, '%Y-%m-%d %H:00:00')",
+	"day":   "DATE_FORMAT(t.local_at, '%Y-%m-%d 00:00:00')",
+	"week":  "DATE_FORMAT(DATE_SUB(t.local_at, INTERVAL WEEKDAY(t.local_at) DAY), '%Y-%m-%d 00:00:00')",
+	"month": "DATE_FORMAT(t.local_at, '%Y-%m-01 00:00:00')",
+}
+
+// GetSalesSeries groups order items by the creation time of their orders.
+// Tickets sold and revenue only count paid orders; orders created and
+// cancellations count orders containing a matching item. Empty buckets are
+// left out.
+func (r *analyticsRepository) GetSalesSeries(filter SalesSeriesFilter) ([]*models.SalesBucketData, error) {
+	var results []*models.SalesBucketData
+
+	items := r.db.Table("orders o").
+		Select(`o.id AS order_id, o.status, oi.quantity, oi.subtotal,
+			CONVERT_TZ(o.created_at, '+00:00', ?) AS local_at,
+			(SELECT COUNT(*) FROM order_details od
+				WHERE od.order_item_id = oi.id AND od.redeemed = TRUE AND od.deleted_at IS NULL) AS redeemed`,
+			r.mysqlZone(filter.Location, filter.From)).
+		Joins("JOIN order_items oi ON oi.order_id = o.id AND oi.deleted_at IS NULL").
+		Where("o.deleted_at IS NULL AND o.created_at >= ? AND o.created_at < ?", filter.From, filter.To)
+
+	if filter.EventID != nil {
+		items = items.Where("o.event_id = ?", *filter.EventID)
+	}
+	if filter.CategoryID != nil {
+		items = items.Where("oi.category_id = ?", *filter.CategoryID)
+	}
+
+	bucket := bucketFormats[filter.Interval]
+	err := r.db.Table("(?) AS t", items).
+		Select(bucket + ` AS bucket,
+			COALESCE(SUM(CASE WHEN t.status = 'paid' THEN t.quantity END), 0) AS tickets_sold,
+			COALESCE(SUM(CASE WHEN t.status = 'paid' THEN t.subtotal END), 0) AS revenue,
+			COUNT(DISTINCT t.order_id) AS orders_created,
+			COUNT(DISTINCT CASE WHEN t.status = 'cancelled' THEN t.order_id END) AS cancellations,
+			COALESCE(SUM(t.redeemed), 0) AS redemptions`).
+		Group("bucket").
+		Order("bucket ASC").
+		Scan(&results).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return results, nil
+}
+
+// funnelColumns count orders by status. subtotal is the value an order adds
+// to the revenue columns.
+func funnelColumns(subtotal string) string {
+	return `COUNT(DISTINCT o.id) AS orders_created,
+		COUNT(DISTINCT CASE WHEN o.status = 'pending' THEN o.id END) AS pending,
+		COUNT(DISTINCT CASE WHEN o.status IN ('paid', 'refunded') THEN o.id END) AS paid,
+		COUNT(DISTINCT CASE WHEN o.status = 'refunded' THEN o.id END) AS refunded,
+		COUNT(DISTINCT CASE WHEN o.status = 'cancelled' THEN o.id END) AS cancelled,
+		COUNT(DISTINCT CASE WHEN o.status = 'expired' THEN o.id END) AS expired,
+		COALESCE(SUM(CASE WHEN o.status = 'paid' THEN ` + subtotal + ` END), 0) AS revenue,
+		COALESCE(SUM(CASE WHEN o.status IN ('cancelled', 'expired') THEN ` + subtotal + ` END), 0) AS lost_revenue`
+}
+
+func (r *analyticsRepository) funnelOrders(filter FunnelFilter) *gorm.DB {
+	query := r.db.Table("orders o").
+		Where("o.deleted_at IS NULL AND o.created_at >= ? AND o.created_at < ?", filter.From, filter.To)
+
+	if filter.EventID != nil {
+		query = query.Where("o.event_id = ?", *filter.EventID)
+	}
+	return query
+}
+
+func (r *analyticsRepository) GetFunnel(filter FunnelFilter) (*models.FunnelData, error) {
+	var result models.FunnelData
+
+	if err := r.funnelOrders(filter).
+		Select(funnelColumns("o.total_price")).
+		Scan(&result).Error; err != nil {
+		return nil, err
+	}
+
+	return &result, nil
+}
+
+// GetCategoryFunnels returns the funnel of every category with orders in the
+// period, sorted by event and category name.
+func (r *analyticsRepository) GetCategoryFunnels(filter FunnelFilter) ([]*models.CategoryFunnelData, error) {
+	var results []*models.CategoryFunnelData
+
+	if err := r.funnelOrders(filter).
+		Select(`oi.category_id, c.name AS category_name, c.event_id, e.title AS event_title, ` + funnelColumns("oi.subtotal")).
+		Joins("JOIN order_items oi ON oi.order_id = o.id AND oi.deleted_at IS NULL").
+		Joins("JOIN categories c ON c.id = oi.category_id").
+		Joins("JOIN events e ON e.id = c.event_id").
+		Group("oi.category_id, c.name, c.event_id, e.title").
+		Order("e.title ASC, c.name ASC").
+		Scan(&results).Error; err != nil {
+		return nil, err
+	}
+
+	return results, nil
+}
+
+// GetTimeToPay returns the median time between creating and paying an order,
+// over all orders and per category. Orders without a payment time, those
+// refunded before payment times were recorded, are left out.
+func (r *analyticsRepository) GetTimeToPay(filter FunnelFilter) ([]*models.TimeToPayData, error) {
+	paid := r.funnelOrders(filter).
+		Where("o.paid_at IS NOT NULL")
+
+	overall := paid.Session(&gorm.Session{}).
+		Select("NULL AS category_id, TIMESTAMPDIFF(SECOND, o.created_at, o.paid_at) AS seconds")
+
+	perCategory := paid.Session(&gorm.Session{}).
+		Distinct("oi.category_id, o.id, TIMESTAMPDIFF(SECOND, o.created_at, o.paid_at) AS seconds").
+		Joins("JOIN order_items oi ON oi.order_id = o.id AND oi.deleted_at IS NULL")
+
+	var results []*models.TimeToPayData
+	for _, durations := range []*gorm.DB{overall, perCategory} {
+		ranked := r.db.Table("(?) AS d", durations).
+			Select(`d.category_id, d.seconds,
+				ROW_NUMBER() OVER (PARTITION BY d.category_id ORDER BY d.seconds) AS position,
+				COUNT(*) OVER (PARTITION BY d.category_id) AS total`)
+
+		// The median is the middle duration, or the mean of the two middle
+		// ones when the count is even.
+		var medians []*models.TimeToPayData
+		if err := r.db.Table("(?) AS t", ranked).
+			Select("t.category_id, AVG(t.seconds) AS median_seconds").
+			Where("t.position IN (FLOOR((t.total + 1) / 2), CEIL((t.total + 1) / 2))").
+			Group("t.category_id").
+			Scan(&medians).Error; err != nil {
+			return nil, err
+		}
+		results = append(results, medians...)
+	}
+
+	return results, nil
+}
+
+// customerColumns sum up paid orders per customer.
+const customerColumns = `COUNT(DISTINCT o.id) AS orders,
+	COUNT(DISTINCT o.event_id) AS events,
+	COALESCE(SUM(o.quantity), 0) AS tickets_bought,
+	COALESCE(SUM(o.total_price), 0) AS spend,
+	MIN(o.created_at) AS first_purchase_at,
+	MAX(o.created_at) AS last_purchase_at`
+
+// customerOrderings rank customers by spend or by tickets bought, breaking
+// ties with the other figure and then the user ID for a stable order.
+var customerOrderings = map[string]string{
+	"spend":   "spend DESC, tickets_bought DESC, u.id ASC",
+	"tickets": "tickets_bought DESC, spend DESC, u.id ASC",
+}
+
+func (r *analyticsRepository) customerOrders(filter CustomerFilter) *gorm.DB {
+	query := r.db.Table("orders o").
+		Joins("JOIN users u ON u.id = o.user_id AND u.deleted_at IS NULL").
+		Where("o.deleted_at IS NULL AND o.status = ?", "paid")
+
+	if !filter.From.IsZero() {
+		query = query.Where("o.created_at >= ? AND o.created_at < ?", filter.From, filter.To)
+	}
+	if filter.EventID != nil {
+		query = query.Where("o.event_id = ?", *filter.EventID)
+	}
+	return query
+}
+
+func (r *analyticsRepository) GetTopCustomers(filter CustomerFilter, sortBy string, page, limit int) ([]*models.CustomerData, int64, error) {
+	var results []*models.CustomerData
+	var total int64
+
+	if err := r.customerOrders(filter).
+		Distinct("o.user_id").
+		Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	if err := r.customerOrders(filter).
+		Select("u.id AS user_id, u.email, u.first_name, u.last_name, " + customerColumns).
+		Group("u.id, u.email, u.first_name, u.last_name").
+		Order(customerOrderings[sortBy]).
+		Offset((page - 1) * limit).
+		Limit(limit).
+		Scan(&results).Error; err != nil {
+		return nil, 0, err
+	}
+
+	return results, total, nil
+}
+
+// GetRepeatPurchases counts customers by the number of events they bought
+// tickets for.
+func (r *analyticsRepository) GetRepeatPurchases(filter CustomerFilter) (*models.RepeatPurchaseData, error) {
+	var result models.RepeatPurchaseData
+
+	customers := r.customerOrders(filter).
+		Select("o.user_id, COUNT(DISTINCT o.event_id) AS events").
+		Group("o.user_id")
+
+	if err := r.db.Table("(?) AS c", customers).
+		Select(`COUNT(*) AS customers,
+			COALESCE(SUM(c.events > 1), 0) AS repeat_customers,
+			COALESCE(SUM(c.events), 0) AS events`).
+		Scan(&result).Error; err != nil {
+		return nil, err
+	}
+
+	return &result, nil
+}
+
+// GetEventBuyers splits the buyers of every event with paid orders in the
+// period into first-time and returning buyers, newest event first. A buyer
+// is returning when they paid for an order for another event, at any time,
+// before their first order for this one.
+func (r *analyticsRepository) GetEventBuyers(filter CustomerFilter, page, limit int) ([]*models.EventBuyersData, int64, error) {
+	var results []*models.EventBuyersData
+	var total int64
+
+	if err := r.customerOrders(filter).
+		Distinct("o.event_id").
+		Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	buyers := r.customerOrders(filter).
+		Select("o.event_id, o.user_id, MIN(o.created_at) AS first_order_at, SUM(o.total_price) AS spend").
+		Group("o.event_id, o.user_id")
+
+	flagged := r.db.Table("(?) AS b", buyers).
+		Select(`b.event_id, b.spend, EXISTS (SELECT 1 FROM orders p
+			WHERE p.user_id = b.user_id AND p.event_id <> b.event_id AND p.status = 'paid'
+				AND p.deleted_at IS NULL AND p.created_at < b.first_order_at) AS is_returning`)
+
+	if err := r.db.Table("(?) AS f", flagged).
+		Select(`f.event_id, e.title AS event_title, e.starts_at,
+			COUNT(*) AS buyers,
+			COALESCE(SUM(NOT f.is_returning), 0) AS first_time_buyers,
+			COALESCE(SUM(f.is_returning), 0) AS returning_buyers,
+			COALESCE(SUM(CASE WHEN NOT f.is_returning THEN f.spend END), 0) AS first_time_spend,
+			COALESCE(SUM(CASE WHEN f.is_returning THEN f.spend END), 0) AS returning_spend`).
+		Joins("JOIN events e ON e.id = f.event_id").
+		Group("f.event_id, e.title, e.starts_at").
+		Order("e.starts_at DESC, f.event_id ASC").
+		Offset((page - 1) * limit).
+		Limit(limit).
+		Scan(&results).Error; err != nil {
+		return nil, 0, err
+	}
+
+	return results, total, nil
+}
+
+// GetCustomer sums up all paid orders of a user. The user's own columns are
+// left empty.
+func (r *analyticsRepository) GetCustomer(userID uuid.UUID) (*models.CustomerData, error) {
+	var result models.CustomerData
+
+	if err := r.db.Table("orders o").
+		Select(customerColumns).
+		Where("o.deleted_at IS NULL AND o.status = ? AND o.user_id = ?", "paid", userID).
+		Scan(&result).Error; err != nil {
+		return nil, err
+	}
+
+	return &result, nil
+}
+
+// GetCustomerFunnel counts all orders of a user by status.
+func (r *analyticsRepository) GetCustomerFunnel(userID uuid.UUID) (*models.FunnelData, error) {
+	var result models.FunnelData
+
+	if err := r.db.Table("orders o").
+		Select(funnelColumns("o.total_price")).
+		Where("o.deleted_at IS NULL AND o.user_id = ?", userID).
+		Scan(&result).Error; err != nil {
+		return nil, err
+	}
+
+	return &result, nil
+}
+
+// GetCustomerEvents sums up a user's paid orders per event, newest event
+// first.
+func (r *analyticsRepository) GetCustomerEvents(userID uuid.UUID) ([]*models.CustomerEventData, error) {
+	var results []*models.CustomerEventData
+
+	orders := r.db.Table("orders o").
+		Select(`o.id, o.event_id, o.quantity, o.total_price, o.created_at,
+			(SELECT COUNT(*) FROM order_details od
+				WHERE od.order_id = o.id AND od.redeemed = TRUE AND od.deleted_at IS NULL) AS checked_in`).
+		Where("o.deleted_at IS NULL AND o.status = ? AND o.user_id = ?", "paid", userID)
+
+	if err := r.db.Table("(?) AS t", orders).
+		Select(`t.event_id, e.title AS event_title, e.starts_at,
+			COUNT(*) AS orders,
+			COALESCE(SUM(t.quantity), 0) AS tickets_bought,
+			COALESCE(SUM(t.checked_in), 0) AS checked_in,
+			COALESCE(SUM(t.total_price), 0) AS spend,
+			MIN(t.created_at) AS first_purchase_at,
+			MAX(t.created_at) AS last_purchase_at`).
+		Joins("JOIN events e ON e.id = t.event_id").
+		Group("t.event_id, e.title, e.starts_at").
+		Order("e.starts_at DESC, t.event_id ASC").
+		Scan(&results).Error; err != nil {
+		return nil, err
+	}
+
+	return results, nil
+}
+
+// mysqlZone names loc for CONVERT_TZ. MySQL only knows named zones once its
+// time zone tables are loaded; without them the zone's offset at the given
+// instant is used, which is exact for zones without daylight saving time.
+func (r *analyticsRepository) mysqlZone(loc *time.Location, at time.Time) string {
+	r.namedZonesOnce.Do(func() {
+		var converted *string
+		if err := r.db.Raw("SELECT CONVERT_TZ('2000-01-01 00:00:00', '+00:00', 'Europe/Amsterdam')").Scan(&converted).Error; err == nil {
+			r.namedZones = converted != nil
+		}
+	})
+
+	if r.namedZones && loc != time.UTC {
+		return loc.String()
+	}
+	return at.In(loc).Format("-07:00")
+}
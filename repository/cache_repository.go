@@ -0,0 +1,73 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"ticert/config"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// CacheRepository stores rendered responses in Redis. Keys are namespaced by a
+// version counter, so bumping the version invalidates every entry of the
+// namespace at once while the old entries simply expire.
+type CacheRepository interface {
+	Get(namespace, key string) ([]byte, bool, error)
+	Set(namespace, key string, value []byte, ttl time.Duration) error
+	Invalidate(namespace string) error
+}
+
+type cacheRepository struct {
+	redisClient *redis.Client
+}
+
+func NewCacheRepository() CacheRepository {
+	return &cacheRepository{
+		redisClient: config.GetRedisClient(),
+	}
+}
+
+func cacheVersionKey(namespace string) string {
+	return fmt.Sprintf("cache:%s:version", namespace)
+}
+
+func (r *cacheRepository) entryKey(ctx context.Context, namespace, key string) (string, error) {
+	version, err := r.redisClient.Get(ctx, cacheVersionKey(namespace)).Int64()
+	if err != nil && !errors.Is(err, redis.Nil) {
+		return "", err
+	}
+	return fmt.Sprintf("cache:%s:v%d:%s", namespace, version, key), nil
+}
+
+func (r *cacheRepository) Get(namespace, key string) ([]byte, bool, error) {
+	ctx := context.Background()
+	entryKey, err := r.entryKey(ctx, namespace, key)
+	if err != nil {
+		return nil, false, err
+	}
+
+	value, err := r.redisClient.Get(ctx, entryKey).Bytes()
+	if err != nil {
+		if errors.Is(err, redis.Nil) {
+			return nil, false, nil
+		}
+		return nil, false, err
+	}
+	return value, true, nil
+}
+
+func (r *cacheRepository) Set(namespace, key string, value []byte, ttl time.Duration) error {
+	ctx := context.Background()
+	entryKey, err := r.entryKey(ctx, namespace, key)
+	if err != nil {
+		return err
+	}
+	return r.redisClient.Set(ctx, entryKey, value, ttl).Err()
+}
+
+func (r *cacheRepository) Invalidate(namespace string) error {
+	ctx := context.Background()
+	return r.redisClient.Incr(ctx, cacheVersionKey(namespace)).Err()
+}
@@ -1,19 +1,25 @@
 package repository
 
 import (
+	"slices"
 	"ticert/entity"
 
 	"github.com/google/uuid"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 type CategoryRepository interface {
-	CreateCategory(category *entity.Category) error
+	CreateCategory(category *entity.Category, check func(categories []*entity.Category) error) error
 	GetCategoryByID(id uuid.UUID) (*entity.Category, error)
 	GetCategories(eventID uuid.UUID) ([]*entity.Category, error)
-	UpdateCategory(category *entity.Category) error
+	UpdateCategory(category *entity.Category, columns []string, quantityDelta int, check func(categories []*entity.Category) error) error
 	DeleteCategory(id uuid.UUID) error
-	CheckStock(categoryID uuid.UUID, quantity int) (bool, error)
+	GetCategoriesByInventoryStrategy(strategy string) ([]*entity.Category, error)
+	SyncStock(categoryID uuid.UUID, quantity int) error
+	CountPendingOrders(categoryID uuid.UUID) (int64, error)
+	ReplacePriceTiers(categoryID uuid.UUID, tiers []*entity.PriceTier) error
+	GetPeakAllocation(eventIDs []uuid.UUID) (int, error)
 }
 
 type categoryRepository struct {
@@ -24,16 +30,24 @@ func NewCategoryRepository(db *gorm.DB) CategoryRepository {
 	return &categoryRepository{db: db}
 }
 
-func (r *categoryRepository) CreateCategory(category *entity.Category) error {
-	if err := r.db.Create(category).Error; err != nil {
-		return err
-	}
-	return nil
+// CreateCategory stores a category. A non-nil check is run first on the
+// event's categories, see checkEventCategories, and its error aborts the
+// create.
+func (r *categoryRepository) CreateCategory(category *entity.Category, check func(categories []*entity.Category) error) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := checkEventCategories(tx, category.EventID, check); err != nil {
+			return err
+		}
+		return tx.Create(category).Error
+	})
 }
 
 func (r *categoryRepository) GetCategoryByID(id uuid.UUID) (*entity.Category, error) {
 	var category entity.Category
-	if err := r.db.Where("id = ?", id).Preload("Event").First(&category).Error; err != nil {
+	if err := r.db.Where("id = ?", id).Preload("Event").Preload("PriceTiers", orderByPosition).First(&category).Error; err != nil {
+		return nil, err
+	}
+	if err := fillSoldQuantities(r.db, []*entity.Category{&category}); err != nil {
 		return nil, err
 	}
 	return &category, nil
@@ -41,30 +55,200 @@ func (r *categoryRepository) GetCategoryByID(id uuid.UUID) (*entity.Category, er
 
 func (r *categoryRepository) GetCategories(eventID uuid.UUID) ([]*entity.Category, error) {
 	var categories []*entity.Category
-	if err := r.db.Where("event_id = ?", eventID).Find(&categories).Error; err != nil {
+	if err := r.db.Where("event_id = ?", eventID).Preload("PriceTiers", orderByPosition).Find(&categories).Error; err != nil {
+		return nil, err
+	}
+	if err := fillSoldQuantities(r.db, categories); err != nil {
 		return nil, err
 	}
 	return categories, nil
 }
 
-func (r *categoryRepository) UpdateCategory(category *entity.Category) error {
-	if err := r.db.Model(&entity.Category{}).Where("id = ?", category.ID).Updates(category).Error; err != nil {
+// UpdateCategory writes the given columns of a category, leaving the others
+// to concurrent writers. Stock is changed under the category row lock by
+// adding quantityDelta to the current quantity, so units sold or claimed since
+// the category was read are kept; the resulting quantity and status are set
+// on category. A non-nil check is run next on the event's categories, see
+// checkEventCategories, and its error aborts the update.
+func (r *categoryRepository) UpdateCategory(category *entity.Category, columns []string, quantityDelta int, check func(categories []*entity.Category) error) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if slices.Contains(columns, "quantity") {
+			var locked entity.Category
+			if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Select("id", "quantity").Where("id = ?", category.ID).First(&locked).Error; err != nil {
+				return err
+			}
+
+			category.Quantity = max(locked.Quantity+quantityDelta, 0)
+			category.Status = "available"
+			if category.Quantity == 0 {
+				category.Status = "sold"
+			}
+			columns = append(slices.Clip(columns), "status")
+		}
+
+		if err := checkEventCategories(tx, category.EventID, check); err != nil {
+			return err
+		}
+
+		if len(columns) == 0 {
+			return nil
+		}
+		return tx.Model(&entity.Category{}).
+			Where("id = ?", category.ID).
+			Select(columns).
+			Updates(category).Error
+	})
+}
+
+// checkEventCategories runs check on the categories of an event, with their
+// sold quantities, while the event row is locked. Category changes checked
+// this way are serialized per event, so a check sees every change committed
+// before it and none can slip in until the transaction ends.
+func checkEventCategories(tx *gorm.DB, eventID uuid.UUID, check func(categories []*entity.Category) error) error {
+	if check == nil {
+		return nil
+	}
+
+	var event entity.Event
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Select("id").Where("id = ?", eventID).First(&event).Error; err != nil {
 		return err
 	}
-	return nil
+
+	var categories []*entity.Category
+	if err := tx.Where("event_id = ?", eventID).Find(&categories).Error; err != nil {
+		return err
+	}
+	if err := fillSoldQuantities(tx, categories); err != nil {
+		return err
+	}
+
+	return check(categories)
 }
 
 func (r *categoryRepository) DeleteCategory(id uuid.UUID) error {
-	if err := r.db.Delete(&entity.Category{}, id).Error; err != nil {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("category_id = ?", id).Delete(&entity.CategorySeat{}).Error; err != nil {
+			return err
+		}
+
+		return tx.Delete(&entity.Category{}, id).Error
+	})
+}
+
+func (r *categoryRepository) GetCategoriesByInventoryStrategy(strategy string) ([]*entity.Category, error) {
+	var categories []*entity.Category
+	if err := r.db.Where("inventory_strategy = ?", strategy).Find(&categories).Error; err != nil {
+		return nil, err
+	}
+	return categories, nil
+}
+
+func (r *categoryRepository) SyncStock(categoryID uuid.UUID, quantity int) error {
+	status := "available"
+	if quantity <= 0 {
+		status = "sold"
+	}
+	if err := r.db.Model(&entity.Category{}).
+		Where("id = ?", categoryID).
+		Updates(map[string]interface{}{"quantity": quantity, "status": status}).Error; err != nil {
 		return err
 	}
 	return nil
 }
 
-func (r *categoryRepository) CheckStock(categoryID uuid.UUID, quantity int) (bool, error) {
-	var category entity.Category
-	if err := r.db.Where("id = ?", categoryID).First(&category).Error; err != nil {
-		return false, err
+func (r *categoryRepository) CountPendingOrders(categoryID uuid.UUID) (int64, error) {
+	var total int64
+	if err := r.db.Model(&entity.OrderItem{}).
+		Joins("JOIN orders ON orders.id = order_items.order_id").
+		Where("order_items.category_id = ? AND orders.status = ?", categoryID, "pending").
+		Count(&total).Error; err != nil {
+		return 0, err
 	}
-	return category.Quantity >= quantity, nil
+	return total, nil
+}
+
+func (r *categoryRepository) ReplacePriceTiers(categoryID uuid.UUID, tiers []*entity.PriceTier) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("category_id = ?", categoryID).Delete(&entity.PriceTier{}).Error; err != nil {
+			return err
+		}
+
+		for _, tier := range tiers {
+			tier.CategoryID = categoryID
+			if err := tx.Create(tier).Error; err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+}
+
+// GetPeakAllocation returns the largest number of tickets put in the venue on
+// a single event date across the given events. A category allocates its
+// remaining stock, the tickets in pending and paid orders and the units held
+// for its waitlist.
+func (r *categoryRepository) GetPeakAllocation(eventIDs []uuid.UUID) (int, error) {
+	if len(eventIDs) == 0 {
+		return 0, nil
+	}
+
+	sold := r.db.Model(&entity.OrderItem{}).
+		Select("order_items.category_id, SUM(order_items.quantity) AS sold").
+		Joins("JOIN orders ON orders.id = order_items.order_id").
+		Where("orders.status IN ?", []string{"pending", "paid"}).
+		Group("order_items.category_id")
+
+	var peak int
+	if err := r.db.Model(&entity.Category{}).
+		Select("COALESCE(SUM(categories.quantity + categories.waitlist_held + COALESCE(sold.sold, 0)), 0) AS total").
+		Joins("LEFT JOIN (?) AS sold ON sold.category_id = categories.id", sold).
+		Where("categories.event_id IN ?", eventIDs).
+		Group("categories.event_id, DATE(categories.event_date)").
+		Order("total DESC").
+		Limit(1).
+		Scan(&peak).Error; err != nil {
+		return 0, err
+	}
+	return peak, nil
+}
+
+func orderByPosition(db *gorm.DB) *gorm.DB {
+	return db.Order("position ASC")
+}
+
+// fillSoldQuantities sets SoldQuantity on each category from the tickets in
+// pending and paid orders, using a single grouped query.
+func fillSoldQuantities(db *gorm.DB, categories []*entity.Category) error {
+	if len(categories) == 0 {
+		return nil
+	}
+
+	ids := make([]uuid.UUID, len(categories))
+	for i, category := range categories {
+		ids[i] = category.ID
+	}
+
+	var rows []struct {
+		CategoryID uuid.UUID
+		Sold       int
+	}
+	if err := db.Model(&entity.OrderItem{}).
+		Select("order_items.category_id, COALESCE(SUM(order_items.quantity), 0) as sold").
+		Joins("JOIN orders ON orders.id = order_items.order_id").
+		Where("order_items.category_id IN ? AND orders.status IN ?", ids, []string{"pending", "paid"}).
+		Group("order_items.category_id").
+		Scan(&rows).Error; err != nil {
+		return err
+	}
+
+	sold := make(map[uuid.UUID]int, len(rows))
+	for _, row := range rows {
+		sold[row.CategoryID] = row.Sold
+	}
+	for _, category := range categories {
+		category.SoldQuantity = sold[category.ID]
+	}
+
+	return nil
 }
@@ -0,0 +1,61 @@
+package repository
+
+import (
+	"testing"
+	"ticert/entity"
+
+	"gorm.io/gorm"
+)
+
+func TestCategoryRepository_UpdateCategory(t *testing.T) {
+	tests := []struct {
+		name         string
+		columns      []string
+		delta        int
+		wantName     string
+		wantQuantity int
+		wantStatus   string
+	}{
+		// 3 units of the 40 read by the admin are sold before the update.
+		{name: "rename keeps stock", columns: []string{"name"}, wantName: "Festival Pass", wantQuantity: 37, wantStatus: "available"},
+		{name: "quantity applied as delta", columns: []string{"name", "quantity"}, delta: 10, wantName: "Festival Pass", wantQuantity: 47, wantStatus: "available"},
+		{name: "reduction stops at zero", columns: []string{"quantity"}, delta: -39, wantName: "Regular", wantQuantity: 0, wantStatus: "sold"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db, f := newReportTestDB(t)
+			repo := NewCategoryRepository(db)
+
+			var category entity.Category
+			if err := db.Where("id = ?", f.regular).First(&category).Error; err != nil {
+				t.Fatal(err)
+			}
+
+			if err := db.Model(&entity.Category{}).Where("id = ?", f.regular).
+				Update("quantity", gorm.Expr("quantity - ?", 3)).Error; err != nil {
+				t.Fatal(err)
+			}
+
+			category.Name = "Festival Pass"
+			category.Quantity += tt.delta
+			if err := repo.UpdateCategory(&category, tt.columns, tt.delta, nil); err != nil {
+				t.Fatal(err)
+			}
+
+			var stored entity.Category
+			if err := db.Where("id = ?", f.regular).First(&stored).Error; err != nil {
+				t.Fatal(err)
+			}
+			if stored.Name != tt.wantName {
+				t.Errorf("name = %q, want %q", stored.Name, tt.wantName)
+			}
+			if stored.Quantity != tt.wantQuantity || stored.Status != tt.wantStatus {
+				t.Errorf("stored stock = %d %s, want %d %s", stored.Quantity, stored.Status, tt.wantQuantity, tt.wantStatus)
+			}
+			if tt.delta != 0 && category.Quantity != stored.Quantity {
+				t.Errorf("category quantity = %d, want the stored %d", category.Quantity, stored.Quantity)
+			}
+		})
+	}
+}
@@ -2,18 +2,31 @@ package repository
 
 import (
 	"ticert/entity"
+	"ticert/utils/cursor"
+	"time"
 
 	"github.com/google/uuid"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 type EventRepository interface {
 	CreateEvent(event *entity.Event) error
 	GetEventByID(id uuid.UUID) (*entity.Event, error)
 	GetEventByTitle(title string) (*entity.Event, error)
-	GetEvents(page, limit int, search string, orderBy string) ([]*entity.Event, int64, error)
+	GetEvents(filter EventFilter) ([]*entity.Event, int64, error)
+	GetEventFacets(filter EventFilter) (*EventFacets, error)
 	UpdateEvent(event *entity.Event) error
 	DeleteEvent(id uuid.UUID) error
+	CreateSeries(series *entity.EventSeries, template *entity.Event, occurrences []*entity.Event) ([]*entity.Category, error)
+	GetSeriesByID(id uuid.UUID) (*entity.EventSeries, error)
+	GetSeriesEvents(seriesID uuid.UUID, from time.Time) ([]*entity.Event, error)
+	UpdateEvents(events []*entity.Event) error
+	CloneEvent(sourceID uuid.UUID, clone *entity.Event, days int) ([]*entity.Category, error)
+	AddEventImage(image *entity.EventImage) ([]*entity.EventImage, error)
+	GetEventImages(eventID uuid.UUID, kind string) ([]*entity.EventImage, error)
+	GetEventImageByID(eventID, id uuid.UUID) (*entity.EventImage, error)
+	DeleteEventImage(id uuid.UUID) error
 }
 
 type eventRepository struct {
@@ -25,7 +38,7 @@ func NewEventRepository(db *gorm.DB) EventRepository {
 }
 
 func (r *eventRepository) CreateEvent(event *entity.Event) error {
-	if err := r.db.Create(event).Error; err != nil {
+	if err := r.db.Omit(clause.Associations).Create(event).Error; err != nil {
 		return err
 	}
 	return nil
@@ -33,7 +46,7 @@ func (r *eventRepository) CreateEvent(event *entity.Event) error {
 
 func (r *eventRepository) GetEventByID(id uuid.UUID) (*entity.Event, error) {
 	var event entity.Event
-	if err := r.db.Where("id = ?", id).First(&event).Error; err != nil {
+	if err := r.db.Where("id = ?", id).Preload("Venue").Preload("Images", orderByPosition).Preload("Tags").First(&event).Error; err != nil {
 		return nil, err
 	}
 	return &event, nil
@@ -47,52 +60,231 @@ func (r *eventRepository) GetEventByTitle(title string) (*entity.Event, error) {
 	return &event, nil
 }
 
-func (r *eventRepository) GetEvents(page, limit int, search string, orderBy string) ([]*entity.Event, int64, error) {
-	var events []*entity.Event
-	var total int64
+// EventFilter narrows and orders an event listing. Zero values leave the
+// corresponding filter off.
+type EventFilter struct {
+	Page      int
+	Limit     int
+	Search    string
+	OrderBy   string
+	Status    string
+	Organizer string
+	Location  string
+	VenueID   *uuid.UUID
+	StartFrom *time.Time
+	StartTo   *time.Time
+	MinPrice  *float64
+	MaxPrice  *float64
+	Available bool
+	// Tags matches events carrying any of the tag slugs, or a child tag of
+	// one of them.
+	Tags []string
+
+	// Keyset switches the listing to cursor pagination: newest first, no
+	// offset and no total count.
+	Keyset *cursor.Cursor
+}
+
+type FacetCount struct {
+	Value string
+	Count int64
+}
 
+// EventFacets counts the events matching a filter by organizer, location and
+// availability.
+type EventFacets struct {
+	Organizers []FacetCount
+	Locations  []FacetCount
+	Available  int64
+	SoldOut    int64
+}
+
+const (
+	// fullTextMinLength is InnoDB's default innodb_ft_min_token_size; shorter
+	// search terms fall back to LIKE.
+	fullTextMinLength = 3
+
+	facetLimit = 20
+
+	eventFullTextMatch = "MATCH(events.title, events.description, events.organizer) AGAINST (? IN NATURAL LANGUAGE MODE)"
+	eventMinPrice      = "(SELECT MIN(c.price) FROM categories c WHERE c.event_id = events.id AND c.deleted_at IS NULL)"
+	eventTicketsSold   = `(SELECT COALESCE(SUM(oi.quantity), 0) FROM order_items oi
+		JOIN orders o ON o.id = oi.order_id
+		WHERE o.event_id = events.id AND o.status IN ('pending', 'paid'))`
+	eventHasStock = `EXISTS (SELECT 1 FROM categories c WHERE c.event_id = events.id AND c.deleted_at IS NULL
+		AND c.quantity > 0 AND c.status = 'available')`
+)
+
+// filterEvents applies every filter except ordering and pagination.
+func (r *eventRepository) filterEvents(filter EventFilter) *gorm.DB {
 	query := r.db.Model(&entity.Event{})
 
-	if search != "" {
-		query = query.Where("title LIKE ? OR description LIKE ? OR organizer LIKE ?", "%"+search+"%", "%"+search+"%", "%"+search+"%")
+	if filter.Status != "" {
+		query = query.Where("events.status = ?", filter.Status)
 	}
 
-	if orderBy != "" {
-		validOrderings := map[string]string{
-			"asc":       "created_at ASC",
-			"desc":      "created_at DESC",
-			"date_asc":  "start_date ASC",
-			"date_desc": "start_date DESC",
-			"time_asc":  "start_time ASC",
-			"time_desc": "start_time DESC",
+	if filter.Search != "" {
+		if len([]rune(filter.Search)) >= fullTextMinLength {
+			query = query.Where(eventFullTextMatch, filter.Search)
+		} else {
+			like := "%" + filter.Search + "%"
+			query = query.Where("events.title LIKE ? OR events.description LIKE ? OR events.organizer LIKE ?", like, like, like)
 		}
+	}
 
-		validOrderBy := ""
-		if v, exists := validOrderings[orderBy]; exists {
-			validOrderBy = v
-		}
+	if filter.Organizer != "" {
+		query = query.Where("events.organizer = ?", filter.Organizer)
+	}
+
+	if filter.Location != "" {
+		query = query.Where("events.location LIKE ?", "%"+filter.Location+"%")
+	}
+
+	if filter.VenueID != nil {
+		query = query.Where("events.venue_id = ?", *filter.VenueID)
+	}
+
+	if filter.StartFrom != nil {
+		query = query.Where("events.starts_at >= ?", *filter.StartFrom)
+	}
 
-		if validOrderBy != "" {
-			query = query.Order(validOrderBy)
+	if filter.StartTo != nil {
+		query = query.Where("events.starts_at < ?", *filter.StartTo)
+	}
+
+	if filter.MinPrice != nil || filter.MaxPrice != nil {
+		priceQuery := r.db.Table("categories c").Select("1").Where("c.event_id = events.id AND c.deleted_at IS NULL")
+		if filter.MinPrice != nil {
+			priceQuery = priceQuery.Where("c.price >= ?", *filter.MinPrice)
+		}
+		if filter.MaxPrice != nil {
+			priceQuery = priceQuery.Where("c.price <= ?", *filter.MaxPrice)
 		}
+		query = query.Where("EXISTS (?)", priceQuery)
+	}
+
+	if filter.Available {
+		query = query.Where(eventHasStock)
+	}
+
+	if len(filter.Tags) > 0 {
+		query = query.Where(`EXISTS (SELECT 1 FROM event_tags et
+			JOIN tags t ON t.id = et.tag_id
+			LEFT JOIN tags p ON p.id = t.parent_id
+			WHERE et.event_id = events.id AND (t.slug IN ? OR p.slug IN ?))`, filter.Tags, filter.Tags)
+	}
+
+	return query
+}
 
+func (r *eventRepository) GetEvents(filter EventFilter) ([]*entity.Event, int64, error) {
+	var events []*entity.Event
+	var total int64
+
+	query := r.filterEvents(filter)
+
+	if filter.Keyset != nil {
+		query = keysetPage(query, "events", filter.Keyset, filter.Limit)
 	} else {
-		query = query.Order("created_at DESC")
+		if err := query.Count(&total).Error; err != nil {
+			return nil, 0, err
+		}
+		query = r.orderEvents(query, filter).Offset((filter.Page - 1) * filter.Limit).Limit(filter.Limit)
 	}
 
-	if err := query.Count(&total).Error; err != nil {
+	if err := query.Preload("Venue").Preload("Images", orderByPosition).Preload("Tags").Preload("Categories").Preload("Categories.PriceTiers", orderByPosition).Find(&events).Error; err != nil {
 		return nil, 0, err
 	}
 
-	if err := query.Preload("Categories").Offset((page - 1) * limit).Limit(limit).Find(&events).Error; err != nil {
+	var categories []*entity.Category
+	for _, event := range events {
+		for i := range event.Categories {
+			categories = append(categories, &event.Categories[i])
+		}
+	}
+	if err := fillSoldQuantities(r.db, categories); err != nil {
 		return nil, 0, err
 	}
 
 	return events, total, nil
 }
 
+// orderEvents applies the requested ordering of an offset listing.
+func (r *eventRepository) orderEvents(query *gorm.DB, filter EventFilter) *gorm.DB {
+	validOrderings := map[string]string{
+		"asc":       "events.created_at ASC",
+		"desc":      "events.created_at DESC",
+		"date_asc":  "events.starts_at ASC",
+		"date_desc": "events.starts_at DESC",
+		"time_asc":  "events.starts_at ASC",
+		"time_desc": "events.starts_at DESC",
+		"cheapest":  eventMinPrice + " IS NULL, " + eventMinPrice + " ASC, events.starts_at ASC",
+		"popular":   eventTicketsSold + " DESC, events.starts_at ASC",
+	}
+
+	orderBy := filter.OrderBy
+	if orderBy == "" {
+		orderBy = "desc"
+		if filter.Search != "" {
+			orderBy = "relevance"
+		}
+	}
+
+	switch orderBy {
+	case "relevance":
+		if filter.Search != "" && len([]rune(filter.Search)) >= fullTextMinLength {
+			query = query.Order(clause.OrderBy{Expression: clause.Expr{SQL: eventFullTextMatch + " DESC", Vars: []interface{}{filter.Search}}})
+		}
+		query = query.Order("events.starts_at ASC")
+	case "soonest":
+		// Upcoming events first, nearest first, then past events.
+		query = query.Order(clause.OrderBy{Expression: clause.Expr{SQL: "events.starts_at < ?, events.starts_at ASC", Vars: []interface{}{time.Now()}}})
+	default:
+		if ordering, exists := validOrderings[orderBy]; exists {
+			query = query.Order(ordering)
+		}
+	}
+
+	return query
+}
+
+// GetEventFacets counts the events matching the filter, ignoring pagination.
+func (r *eventRepository) GetEventFacets(filter EventFilter) (*EventFacets, error) {
+	facets := &EventFacets{}
+
+	if err := r.filterEvents(filter).
+		Select("events.organizer AS value, COUNT(*) AS count").
+		Group("events.organizer").
+		Order("count DESC, value ASC").
+		Limit(facetLimit).
+		Scan(&facets.Organizers).Error; err != nil {
+		return nil, err
+	}
+
+	if err := r.filterEvents(filter).
+		Select("events.location AS value, COUNT(*) AS count").
+		Group("events.location").
+		Order("count DESC, value ASC").
+		Limit(facetLimit).
+		Scan(&facets.Locations).Error; err != nil {
+		return nil, err
+	}
+
+	var total int64
+	if err := r.filterEvents(filter).Count(&total).Error; err != nil {
+		return nil, err
+	}
+
+	if err := r.filterEvents(filter).Where(eventHasStock).Count(&facets.Available).Error; err != nil {
+		return nil, err
+	}
+	facets.SoldOut = total - facets.Available
+
+	return facets, nil
+}
+
 func (r *eventRepository) UpdateEvent(event *entity.Event) error {
-	if err := r.db.Model(&entity.Event{}).Where("id = ?", event.ID).Updates(event).Error; err != nil {
+	if err := r.db.Model(&entity.Event{}).Where("id = ?", event.ID).Omit(clause.Associations).Updates(event).Error; err != nil {
 		return err
 	}
 	return nil
@@ -110,3 +302,263 @@ func (r *eventRepository) DeleteEvent(id uuid.UUID) error {
 		return nil
 	})
 }
+
+// CreateSeries stores the series, attaches the template event to it and
+// creates every occurrence with a copy of the template's categories. The new
+// categories are returned so their stock can be set up outside the database.
+func (r *eventRepository) CreateSeries(series *entity.EventSeries, template *entity.Event, occurrences []*entity.Event) ([]*entity.Category, error) {
+	var categories []*entity.Category
+
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(series).Error; err != nil {
+			return err
+		}
+
+		if err := tx.Model(&entity.Event{}).Where("id = ?", template.ID).Update("series_id", series.ID).Error; err != nil {
+			return err
+		}
+		template.SeriesID = &series.ID
+
+		zone := template.Zone()
+		for _, occurrence := range occurrences {
+			occurrence.SeriesID = &series.ID
+			if err := tx.Omit(clause.Associations).Create(occurrence).Error; err != nil {
+				return err
+			}
+
+			copies, err := copyEventCategories(tx, template.ID, occurrence, calendarDays(template.StartsAt.In(zone), occurrence.StartsAt.In(zone)))
+			if err != nil {
+				return err
+			}
+			categories = append(categories, copies...)
+
+			if err := copyEventTags(tx, template.ID, occurrence.ID); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return categories, nil
+}
+
+func (r *eventRepository) GetSeriesByID(id uuid.UUID) (*entity.EventSeries, error) {
+	var series entity.EventSeries
+	if err := r.db.Where("id = ?", id).Preload("Events", func(db *gorm.DB) *gorm.DB {
+		return db.Order("starts_at ASC")
+	}).First(&series).Error; err != nil {
+		return nil, err
+	}
+	return &series, nil
+}
+
+// GetSeriesEvents lists the occurrences of a series starting at or after from.
+func (r *eventRepository) GetSeriesEvents(seriesID uuid.UUID, from time.Time) ([]*entity.Event, error) {
+	var events []*entity.Event
+	if err := r.db.Where("series_id = ? AND starts_at >= ?", seriesID, from).Order("starts_at ASC").Find(&events).Error; err != nil {
+		return nil, err
+	}
+	return events, nil
+}
+
+func (r *eventRepository) UpdateEvents(events []*entity.Event) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		for _, event := range events {
+			if err := tx.Model(&entity.Event{}).Where("id = ?", event.ID).Omit(clause.Associations).Updates(event).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// CloneEvent creates the clone and copies the source event's categories onto
+// it, moving their dates by the given number of days.
+func (r *eventRepository) CloneEvent(sourceID uuid.UUID, clone *entity.Event, days int) ([]*entity.Category, error) {
+	var categories []*entity.Category
+
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Omit(clause.Associations).Create(clone).Error; err != nil {
+			return err
+		}
+
+		copies, err := copyEventCategories(tx, sourceID, clone, days)
+		if err != nil {
+			return err
+		}
+		categories = copies
+
+		return copyEventTags(tx, sourceID, clone.ID)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return categories, nil
+}
+
+// copyEventCategories copies the categories of the source event to the
+// target, moving every date by the given number of days in the target's time
+// zone. Stock starts fresh from the category's original allocation, so sold
+// and waitlisted units are available again; price tiers and reserved seat
+// assignments are copied along.
+func copyEventCategories(tx *gorm.DB, sourceEventID uuid.UUID, target *entity.Event, days int) ([]*entity.Category, error) {
+	var categories []*entity.Category
+	if err := tx.Where("event_id = ?", sourceEventID).Preload("PriceTiers", orderByPosition).Find(&categories).Error; err != nil {
+		return nil, err
+	}
+
+	if err := fillSoldQuantities(tx, categories); err != nil {
+		return nil, err
+	}
+
+	zone := target.Zone()
+	shift := func(t *time.Time) *time.Time {
+		if t == nil {
+			return nil
+		}
+		shifted := t.In(zone).AddDate(0, 0, days).UTC()
+		return &shifted
+	}
+
+	copies := make([]*entity.Category, 0, len(categories))
+	for _, category := range categories {
+		var seats []*entity.CategorySeat
+		if err := tx.Where("category_id = ?", category.ID).Find(&seats).Error; err != nil {
+			return nil, err
+		}
+
+		quantity := category.Quantity + category.SoldQuantity + category.WaitlistHeld
+		if category.SeatingMode == "reserved" {
+			quantity = len(seats)
+		}
+
+		status := "available"
+		if quantity == 0 {
+			status = "sold"
+		}
+
+		clone := &entity.Category{
+			EventID:           target.ID,
+			Name:              category.Name,
+			Price:             category.Price,
+			EventDate:         category.EventDate.AddDate(0, 0, days),
+			Quantity:          quantity,
+			Status:            status,
+			InventoryStrategy: category.InventoryStrategy,
+			SeatingMode:       category.SeatingMode,
+			SalesStartAt:      shift(category.SalesStartAt),
+			SalesEndAt:        shift(category.SalesEndAt),
+			MinPerOrder:       category.MinPerOrder,
+			MaxPerOrder:       category.MaxPerOrder,
+			MaxPerUser:        category.MaxPerUser,
+		}
+		if err := tx.Omit(clause.Associations).Create(clone).Error; err != nil {
+			return nil, err
+		}
+
+		for _, tier := range category.PriceTiers {
+			if err := tx.Create(&entity.PriceTier{
+				CategoryID: clone.ID,
+				Name:       tier.Name,
+				Price:      tier.Price,
+				StartsAt:   shift(tier.StartsAt),
+				EndsAt:     shift(tier.EndsAt),
+				UnitLimit:  tier.UnitLimit,
+				Position:   tier.Position,
+			}).Error; err != nil {
+				return nil, err
+			}
+		}
+
+		for _, seat := range seats {
+			if err := tx.Create(&entity.CategorySeat{
+				EventID:    target.ID,
+				SeatID:     seat.SeatID,
+				CategoryID: clone.ID,
+				Status:     "available",
+			}).Error; err != nil {
+				return nil, err
+			}
+		}
+
+		copies = append(copies, clone)
+	}
+
+	return copies, nil
+}
+
+// copyEventTags gives the target event the same tags as the source event.
+func copyEventTags(tx *gorm.DB, sourceEventID, targetEventID uuid.UUID) error {
+	return tx.Exec("INSERT INTO event_tags (event_id, tag_id) SELECT ?, tag_id FROM event_tags WHERE event_id = ?", targetEventID, sourceEventID).Error
+}
+
+// calendarDays counts the calendar days from one wall clock date to another.
+func calendarDays(from, to time.Time) int {
+	fromDate := time.Date(from.Year(), from.Month(), from.Day(), 0, 0, 0, 0, time.UTC)
+	toDate := time.Date(to.Year(), to.Month(), to.Day(), 0, 0, 0, 0, time.UTC)
+	return int(toDate.Sub(fromDate).Hours() / 24)
+}
+
+// AddEventImage stores an uploaded image. A poster replaces the event's
+// current poster and gallery images are appended after the existing ones.
+// Replaced images are returned so their files can be removed from storage.
+func (r *eventRepository) AddEventImage(image *entity.EventImage) ([]*entity.EventImage, error) {
+	var replaced []*entity.EventImage
+
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		// Lock the event so concurrent uploads see each other's images.
+		var event entity.Event
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Select("id").Where("id = ?", image.EventID).First(&event).Error; err != nil {
+			return err
+		}
+
+		if image.Kind == "poster" {
+			if err := tx.Where("event_id = ? AND kind = ?", image.EventID, "poster").Find(&replaced).Error; err != nil {
+				return err
+			}
+			if err := tx.Where("event_id = ? AND kind = ?", image.EventID, "poster").Delete(&entity.EventImage{}).Error; err != nil {
+				return err
+			}
+			image.Position = 0
+		} else {
+			var position int
+			if err := tx.Model(&entity.EventImage{}).Where("event_id = ? AND kind = ?", image.EventID, image.Kind).
+				Select("COALESCE(MAX(position), 0)").Scan(&position).Error; err != nil {
+				return err
+			}
+			image.Position = position + 1
+		}
+
+		return tx.Create(image).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return replaced, nil
+}
+
+func (r *eventRepository) GetEventImages(eventID uuid.UUID, kind string) ([]*entity.EventImage, error) {
+	var images []*entity.EventImage
+	if err := r.db.Where("event_id = ? AND kind = ?", eventID, kind).Order("position ASC").Find(&images).Error; err != nil {
+		return nil, err
+	}
+	return images, nil
+}
+
+func (r *eventRepository) GetEventImageByID(eventID, id uuid.UUID) (*entity.EventImage, error) {
+	var image entity.EventImage
+	if err := r.db.Where("id = ? AND event_id = ?", id, eventID).First(&image).Error; err != nil {
+		return nil, err
+	}
+	return &image, nil
+}
+
+func (r *eventRepository) DeleteEventImage(id uuid.UUID) error {
+	return r.db.Delete(&entity.EventImage{}, "id = ?", id).Error
+}
@@ -0,0 +1,246 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"ticert/config"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+)
+
+// InventoryRepository keeps category stock in Redis for categories using the
+// "redis" inventory strategy. Stock is decremented atomically with Lua scripts
+// and every reservation is recorded as a hold that expires unless confirmed.
+type InventoryRepository interface {
+	PrimeStock(categoryID uuid.UUID, quantity int) error
+	SetStock(categoryID uuid.UUID, quantity int) error
+	AdjustStock(categoryID uuid.UUID, delta, quantity int) (int, error)
+	GetStock(categoryID uuid.UUID) (int, bool, error)
+	Reserve(holdID uuid.UUID, quantities map[uuid.UUID]int, expiresAt time.Time) (int, error)
+	Release(categoryID uuid.UUID, holdID uuid.UUID) (int, error)
+	Confirm(holdID uuid.UUID, categoryIDs []uuid.UUID) (bool, error)
+	ReleaseExpired(categoryID uuid.UUID, now time.Time) ([]uuid.UUID, error)
+	ClearStock(categoryID uuid.UUID) error
+	TakeStock(categoryID uuid.UUID, quantity int) (int, error)
+	ReturnStock(categoryID uuid.UUID, quantity int) error
+}
+
+const (
+	ReserveNotPrimed  = -1
+	ReserveOutOfStock = 0
+	ReserveOK         = 1
+)
+
+// reserveScript reserves stock for several categories at once. KEYS holds the
+// stock, holds and hold_quantity keys of each category in turn, ARGV holds the
+// hold ID, the expiry and then one quantity per category. Either every
+// category is decremented or none is.
+var reserveScript = redis.NewScript(`
+local count = #KEYS / 3
+for i = 0, count - 1 do
+	local stock = tonumber(redis.call('GET', KEYS[i * 3 + 1]))
+	if stock == nil then
+		return -1
+	end
+	if stock < tonumber(ARGV[i + 3]) then
+		return 0
+	end
+end
+for i = 0, count - 1 do
+	local quantity = tonumber(ARGV[i + 3])
+	redis.call('DECRBY', KEYS[i * 3 + 1], quantity)
+	redis.call('ZADD', KEYS[i * 3 + 2], ARGV[2], ARGV[1])
+	redis.call('HSET', KEYS[i * 3 + 3], ARGV[1], quantity)
+end
+return 1
+`)
+
+// adjustStockScript adds ARGV[1], which may be negative, to the stock and
+// returns the new stock. A missing stock is primed with ARGV[2] instead.
+// Stock may go below zero by as many units as are held, so that holds
+// released later make up for a reduction instead of going back on sale; a
+// larger reduction stops there, as the held units are all that can be
+// recovered.
+var adjustStockScript = redis.NewScript(`
+local stock = tonumber(redis.call('GET', KEYS[1]))
+if stock == nil then
+	redis.call('SET', KEYS[1], ARGV[2])
+	return tonumber(ARGV[2])
+end
+local held = 0
+for _, quantity in ipairs(redis.call('HVALS', KEYS[3])) do
+	held = held + tonumber(quantity)
+end
+stock = math.max(stock + tonumber(ARGV[1]), -held)
+redis.call('SET', KEYS[1], stock)
+return stock
+`)
+
+var releaseScript = redis.NewScript(`
+local quantity = tonumber(redis.call('HGET', KEYS[3], ARGV[1]))
+if quantity == nil then
+	return 0
+end
+redis.call('INCRBY', KEYS[1], quantity)
+redis.call('HDEL', KEYS[3], ARGV[1])
+redis.call('ZREM', KEYS[2], ARGV[1])
+return quantity
+`)
+
+// confirmScript removes a hold from several categories at once. KEYS holds
+// the stock, holds and hold_quantity keys of each category in turn and
+// ARGV[1] the hold ID. Unless the hold exists in every category it is left
+// in place everywhere, so the units still held can be released.
+var confirmScript = redis.NewScript(`
+local count = #KEYS / 3
+for i = 0, count - 1 do
+	if redis.call('HEXISTS', KEYS[i * 3 + 3], ARGV[1]) == 0 then
+		return 0
+	end
+end
+for i = 0, count - 1 do
+	redis.call('HDEL', KEYS[i * 3 + 3], ARGV[1])
+	redis.call('ZREM', KEYS[i * 3 + 2], ARGV[1])
+end
+return 1
+`)
+
+var releaseExpiredScript = redis.NewScript(`
+local holds = redis.call('ZRANGEBYSCORE', KEYS[2], '-inf', ARGV[1])
+for _, hold in ipairs(holds) do
+	local quantity = tonumber(redis.call('HGET', KEYS[3], hold))
+	if quantity ~= nil then
+		redis.call('INCRBY', KEYS[1], quantity)
+	end
+	redis.call('HDEL', KEYS[3], hold)
+	redis.call('ZREM', KEYS[2], hold)
+end
+return holds
+`)
+
+// takeScript removes up to ARGV[1] units from the stock and returns how many
+// were taken, leaving no hold behind.
+var takeScript = redis.NewScript(`
+local stock = tonumber(redis.call('GET', KEYS[1]))
+if stock == nil or stock <= 0 then
+	return 0
+end
+local quantity = math.min(stock, tonumber(ARGV[1]))
+redis.call('DECRBY', KEYS[1], quantity)
+return quantity
+`)
+
+type inventoryRepository struct {
+	redisClient *redis.Client
+}
+
+func NewInventoryRepository() InventoryRepository {
+	return &inventoryRepository{
+		redisClient: config.GetRedisClient(),
+	}
+}
+
+func inventoryKeys(categoryID uuid.UUID) []string {
+	return []string{
+		fmt.Sprintf("inventory:stock:%s", categoryID.String()),
+		fmt.Sprintf("inventory:holds:%s", categoryID.String()),
+		fmt.Sprintf("inventory:hold_quantity:%s", categoryID.String()),
+	}
+}
+
+func (r *inventoryRepository) PrimeStock(categoryID uuid.UUID, quantity int) error {
+	ctx := context.Background()
+	return r.redisClient.SetNX(ctx, inventoryKeys(categoryID)[0], quantity, 0).Err()
+}
+
+func (r *inventoryRepository) SetStock(categoryID uuid.UUID, quantity int) error {
+	ctx := context.Background()
+	return r.redisClient.Set(ctx, inventoryKeys(categoryID)[0], quantity, 0).Err()
+}
+
+func (r *inventoryRepository) AdjustStock(categoryID uuid.UUID, delta, quantity int) (int, error) {
+	ctx := context.Background()
+	return adjustStockScript.Run(ctx, r.redisClient, inventoryKeys(categoryID), delta, quantity).Int()
+}
+
+func (r *inventoryRepository) GetStock(categoryID uuid.UUID) (int, bool, error) {
+	ctx := context.Background()
+	stock, err := r.redisClient.Get(ctx, inventoryKeys(categoryID)[0]).Int()
+	if err == redis.Nil {
+		return 0, false, nil
+	}
+	if err != nil {
+		return 0, false, err
+	}
+	return stock, true, nil
+}
+
+func (r *inventoryRepository) Reserve(holdID uuid.UUID, quantities map[uuid.UUID]int, expiresAt time.Time) (int, error) {
+	ctx := context.Background()
+
+	keys := make([]string, 0, len(quantities)*3)
+	args := []interface{}{holdID.String(), expiresAt.Unix()}
+	for categoryID, quantity := range quantities {
+		keys = append(keys, inventoryKeys(categoryID)...)
+		args = append(args, quantity)
+	}
+
+	return reserveScript.Run(ctx, r.redisClient, keys, args...).Int()
+}
+
+func (r *inventoryRepository) Release(categoryID uuid.UUID, holdID uuid.UUID) (int, error) {
+	ctx := context.Background()
+	return releaseScript.Run(ctx, r.redisClient, inventoryKeys(categoryID), holdID.String()).Int()
+}
+
+func (r *inventoryRepository) Confirm(holdID uuid.UUID, categoryIDs []uuid.UUID) (bool, error) {
+	ctx := context.Background()
+
+	keys := make([]string, 0, len(categoryIDs)*3)
+	for _, categoryID := range categoryIDs {
+		keys = append(keys, inventoryKeys(categoryID)...)
+	}
+
+	confirmed, err := confirmScript.Run(ctx, r.redisClient, keys, holdID.String()).Int()
+	if err != nil {
+		return false, err
+	}
+	return confirmed == 1, nil
+}
+
+func (r *inventoryRepository) ReleaseExpired(categoryID uuid.UUID, now time.Time) ([]uuid.UUID, error) {
+	ctx := context.Background()
+	holds, err := releaseExpiredScript.Run(ctx, r.redisClient, inventoryKeys(categoryID),
+		strconv.FormatInt(now.Unix(), 10)).StringSlice()
+	if err != nil {
+		return nil, err
+	}
+
+	holdIDs := make([]uuid.UUID, 0, len(holds))
+	for _, hold := range holds {
+		holdID, err := uuid.Parse(hold)
+		if err != nil {
+			continue
+		}
+		holdIDs = append(holdIDs, holdID)
+	}
+	return holdIDs, nil
+}
+
+func (r *inventoryRepository) ClearStock(categoryID uuid.UUID) error {
+	ctx := context.Background()
+	return r.redisClient.Del(ctx, inventoryKeys(categoryID)...).Err()
+}
+
+func (r *inventoryRepository) TakeStock(categoryID uuid.UUID, quantity int) (int, error) {
+	ctx := context.Background()
+	return takeScript.Run(ctx, r.redisClient, inventoryKeys(categoryID)[:1], quantity).Int()
+}
+
+func (r *inventoryRepository) ReturnStock(categoryID uuid.UUID, quantity int) error {
+	ctx := context.Background()
+	return r.redisClient.IncrBy(ctx, inventoryKeys(categoryID)[0], int64(quantity)).Err()
+}
@@ -1,19 +1,41 @@
 package repository
 
 import (
+	"errors"
 	"ticert/entity"
+	"ticert/models"
+	"ticert/utils/cursor"
+	"time"
 
 	"github.com/google/uuid"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+var (
+	ErrInsufficientStock = errors.New("insufficient stock")
+	ErrPurchaseLimit     = errors.New("purchase limit exceeded")
+
+	// ErrOrderStatusChanged is returned when an order no longer has the
+	// status a change requires once it is locked, because a concurrent
+	// request changed it first.
+	ErrOrderStatusChanged = errors.New("order status changed")
 )
 
 type OrderRepository interface {
-	CreateOrder(order *entity.Order, orderDetails []*entity.OrderDetail, categoryID uuid.UUID, quantity int) error
+	CreateOrder(order *entity.Order) error
 	GetOrders(page, limit int, userID uuid.UUID) ([]*entity.Order, int64, error)
 	GetOrdersAdmin(page, limit int, status, search string, orderBy string) ([]*entity.Order, int64, error)
+	GetOrdersByCursor(c *cursor.Cursor, limit int, userID uuid.UUID) ([]*entity.Order, error)
+	GetOrdersAdminByCursor(c *cursor.Cursor, limit int, status, search string) ([]*entity.Order, error)
+	ExportOrdersAdmin(status, search, orderBy string, fn func(row *models.OrderExportRow) error) error
 	GetOrderById(orderID uuid.UUID) (*entity.Order, error)
 	GetOrderDetailByTicketCode(ticketCode string) (*entity.OrderDetail, error)
+	CountUserTickets(userID, categoryID uuid.UUID) (int64, error)
 	CancelOrder(orderID uuid.UUID) error
+	ExpireOrders(orderIDs []uuid.UUID) ([]uuid.UUID, error)
+	GetStalePendingOrders(before time.Time, limit int) ([]*entity.Order, error)
+	RefundOrder(orderID uuid.UUID) error
 	VerifyOrderStatus(orderID uuid.UUID) error
 	VerifyTicket(id uuid.UUID) error
 }
@@ -26,45 +48,160 @@ func NewOrderRepository(db *gorm.DB) OrderRepository {
 	return &orderRepository{db: db}
 }
 
-func (r *orderRepository) CreateOrder(order *entity.Order, orderDetails []*entity.OrderDetail, categoryID uuid.UUID, quantity int) error {
+func preloadOrder(db *gorm.DB) *gorm.DB {
+	unscoped := func(db *gorm.DB) *gorm.DB { return db.Unscoped() }
+	return db.
+		Preload("OrderItems.Category", unscoped).
+		Preload("OrderItems.OrderDetails.Seat.Section", unscoped).
+		Preload("Event", unscoped).
+		Preload("User")
+}
+
+// CreateOrder stores the order with all of its items and tickets in one
+// transaction. Stock of database-strategy categories is locked and decremented
+// here for every item; redis-strategy categories are expected to be reserved
+// by the caller beforehand. Items claiming a waitlist entry take their units
+// from the category's waitlist_held pool instead of the general stock. Items
+// are priced here, under the category locks, see priceOrderItems.
+func (r *orderRepository) CreateOrder(order *entity.Order) error {
 	return r.db.Transaction(func(tx *gorm.DB) error {
-		var category entity.Category
-		if err := tx.Set("gorm:query_option", "FOR UPDATE").
-			Where("id = ?", categoryID).
-			First(&category).Error; err != nil {
+		quantities := make(map[uuid.UUID]int)
+		claimed := make(map[uuid.UUID]int)
+		categoryIDs := make([]uuid.UUID, 0, len(order.OrderItems))
+		for _, item := range order.OrderItems {
+			if _, exists := quantities[item.CategoryID]; !exists {
+				categoryIDs = append(categoryIDs, item.CategoryID)
+			}
+			if item.WaitlistEntryID != nil {
+				claimed[item.CategoryID] += item.Quantity
+			} else {
+				quantities[item.CategoryID] += item.Quantity
+			}
+		}
+
+		var categories []*entity.Category
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Preload("PriceTiers", orderByPosition).
+			Where("id IN ?", categoryIDs).
+			Order("id").
+			Find(&categories).Error; err != nil {
 			return err
 		}
 
-		if category.Quantity < quantity {
+		if len(categories) != len(categoryIDs) {
 			return gorm.ErrRecordNotFound
 		}
 
-		if err := tx.Model(&entity.Category{}).
-			Where("id = ?", categoryID).
-			Update("quantity", gorm.Expr("quantity - ?", quantity)).Error; err != nil {
+		if err := fillSoldQuantities(tx, categories); err != nil {
 			return err
 		}
 
-		if category.Quantity-quantity == 0 {
+		// The category locks serialize orders of the same category, so the
+		// user's tickets are counted again here, after any order committed
+		// while this one waited.
+		for _, category := range categories {
+			if category.MaxPerUser == 0 {
+				continue
+			}
+
+			purchased, err := countUserTickets(tx, order.UserID, category.ID)
+			if err != nil {
+				return err
+			}
+
+			if purchased+int64(quantities[category.ID]+claimed[category.ID]) > int64(category.MaxPerUser) {
+				return ErrPurchaseLimit
+			}
+		}
+
+		priceOrderItems(order, categories, time.Now())
+
+		claims := make(map[uuid.UUID]bool)
+		for _, item := range order.OrderItems {
+			if item.WaitlistEntryID == nil || claims[*item.WaitlistEntryID] {
+				continue
+			}
+
+			if err := claimWaitlistEntry(tx, *item.WaitlistEntryID, order.UserID, item.CategoryID); err != nil {
+				return err
+			}
+			claims[*item.WaitlistEntryID] = true
+		}
+
+		for _, category := range categories {
+			if quantity := claimed[category.ID]; quantity > 0 {
+				if err := tx.Model(&entity.Category{}).
+					Where("id = ?", category.ID).
+					Update("waitlist_held", gorm.Expr("waitlist_held - ?", quantity)).Error; err != nil {
+					return err
+				}
+			}
+
+			if category.InventoryStrategy == "redis" {
+				continue
+			}
+
+			quantity := quantities[category.ID]
+			if quantity == 0 {
+				continue
+			}
+
+			if category.Quantity < quantity {
+				return ErrInsufficientStock
+			}
+
+			status := category.Status
+			if category.Quantity-quantity == 0 {
+				status = "sold"
+			}
+
 			if err := tx.Model(&entity.Category{}).
-				Where("id = ?", categoryID).
-				Update("status", "sold").Error; err != nil {
+				Where("id = ?", category.ID).
+				Updates(map[string]interface{}{
+					"quantity": gorm.Expr("quantity - ?", quantity),
+					"status":   status,
+				}).Error; err != nil {
 				return err
 			}
 		}
 
-		if err := tx.Create(order).Error; err != nil {
+		for _, item := range order.OrderItems {
+			var seatIDs []uuid.UUID
+			for _, orderDetail := range item.OrderDetails {
+				if orderDetail.SeatID != nil {
+					seatIDs = append(seatIDs, *orderDetail.SeatID)
+				}
+			}
+
+			if len(seatIDs) == 0 {
+				continue
+			}
+
+			if err := holdSeats(tx, order.ID, item.CategoryID, seatIDs); err != nil {
+				return err
+			}
+		}
+
+		if err := tx.Omit(clause.Associations).Create(order).Error; err != nil {
 			return err
 		}
 
-		for _, orderDetail := range orderDetails {
-			orderDetail.OrderID = order.ID
-			if err := tx.Create(orderDetail).Error; err != nil {
+		for _, item := range order.OrderItems {
+			item.OrderID = order.ID
+			if err := tx.Omit(clause.Associations).Create(item).Error; err != nil {
 				return err
 			}
+
+			for _, orderDetail := range item.OrderDetails {
+				orderDetail.OrderID = order.ID
+				orderDetail.OrderItemID = item.ID
+				if err := tx.Create(orderDetail).Error; err != nil {
+					return err
+				}
+			}
 		}
 
-		if err := tx.Preload("OrderDetails").Preload("Category.Event").Preload("User").First(order).Error; err != nil {
+		if err := preloadOrder(tx).First(order).Error; err != nil {
 			return err
 		}
 
@@ -72,6 +209,63 @@ func (r *orderRepository) CreateOrder(order *entity.Order, orderDetails []*entit
 	})
 }
 
+// priceOrderItems prices the items of an order from the locked categories,
+// whose sold quantities include every order committed before the lock was
+// taken. An item crossing the unit limit of a price tier is split into one
+// item per tier, its tickets shared out in order.
+func priceOrderItems(order *entity.Order, categories []*entity.Category, now time.Time) {
+	byID := make(map[uuid.UUID]*entity.Category, len(categories))
+	for _, category := range categories {
+		byID[category.ID] = category
+	}
+
+	items := make([]*entity.OrderItem, 0, len(order.OrderItems))
+	order.TotalPrice = 0
+	for _, item := range order.OrderItems {
+		category := byID[item.CategoryID]
+		orderDetails := item.OrderDetails
+
+		for _, part := range category.PriceUnits(now, item.Quantity) {
+			priced := &entity.OrderItem{
+				CategoryID:      item.CategoryID,
+				WaitlistEntryID: item.WaitlistEntryID,
+				Quantity:        part.Quantity,
+				UnitPrice:       part.Price,
+				Subtotal:        float64(part.Quantity) * part.Price,
+				OrderDetails:    orderDetails[:part.Quantity],
+			}
+			if part.Tier != nil {
+				priced.PriceTierID = &part.Tier.ID
+			}
+			orderDetails = orderDetails[part.Quantity:]
+
+			items = append(items, priced)
+			order.TotalPrice += priced.Subtotal
+		}
+
+		// A later item of the same category is priced after this one.
+		category.SoldQuantity += item.Quantity
+	}
+
+	order.OrderItems = items
+}
+
+// claimWaitlistEntry marks a notified entry as claimed. Units the order does
+// not use stay in the held pool and are allocated again by the waitlist.
+func claimWaitlistEntry(tx *gorm.DB, entryID, userID, categoryID uuid.UUID) error {
+	var entry entity.WaitlistEntry
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Where("id = ?", entryID).First(&entry).Error; err != nil {
+		return err
+	}
+
+	if entry.Status != "notified" || entry.UserID != userID || entry.CategoryID != categoryID ||
+		entry.ClaimExpiresAt == nil || time.Now().After(*entry.ClaimExpiresAt) {
+		return ErrWaitlistClaimExpired
+	}
+
+	return tx.Model(&entity.WaitlistEntry{}).Where("id = ?", entryID).Update("status", "claimed").Error
+}
+
 func (r *orderRepository) GetOrders(page, limit int, userID uuid.UUID) ([]*entity.Order, int64, error) {
 	var orders []*entity.Order
 	var total int64
@@ -81,7 +275,7 @@ func (r *orderRepository) GetOrders(page, limit int, userID uuid.UUID) ([]*entit
 		return nil, 0, err
 	}
 
-	query := r.db.Preload("OrderDetails").Preload("Category.Event").Preload("User").Where("user_id = ?", userID).Order("created_at DESC")
+	query := preloadOrder(r.db).Where("user_id = ?", userID).Order("created_at DESC")
 
 	if page > 0 && limit > 0 {
 		query = query.Offset((page - 1) * limit).Limit(limit)
@@ -94,50 +288,67 @@ func (r *orderRepository) GetOrders(page, limit int, userID uuid.UUID) ([]*entit
 	return orders, total, nil
 }
 
-func (r *orderRepository) GetOrdersAdmin(page, limit int, status, search string, orderBy string) ([]*entity.Order, int64, error) {
+// GetOrdersByCursor returns up to limit+1 of the user's orders around the
+// cursor, in cursor order.
+func (r *orderRepository) GetOrdersByCursor(c *cursor.Cursor, limit int, userID uuid.UUID) ([]*entity.Order, error) {
 	var orders []*entity.Order
-	var total int64
 
-	validOrderings := map[string]string{
-		"asc":              "orders.created_at ASC",
-		"desc":             "orders.created_at DESC",
-		"quantity_asc":     "orders.quantity ASC",
-		"quantity_desc":    "orders.quantity DESC",
-		"total_price_asc":  "orders.total_price ASC",
-		"total_price_desc": "orders.total_price DESC",
+	query := keysetPage(preloadOrder(r.db).Where("orders.user_id = ?", userID), "orders", c, limit)
+	if err := query.Find(&orders).Error; err != nil {
+		return nil, err
 	}
 
-	baseQuery := r.db.Model(&entity.Order{}).
-		Joins("JOIN categories ON categories.id = orders.category_id").
-		Joins("JOIN events ON events.id = categories.event_id").
+	return orders, nil
+}
+
+// filterOrdersAdmin builds the admin order listing query without ordering.
+func (r *orderRepository) filterOrdersAdmin(status, search string) *gorm.DB {
+	query := r.db.Model(&entity.Order{}).
+		Joins("JOIN events ON events.id = orders.event_id").
 		Joins("JOIN users ON users.id = orders.user_id")
 
 	if status != "" {
-		baseQuery = baseQuery.Where("orders.status = ?", status)
+		query = query.Where("orders.status = ?", status)
 	}
 
 	if search != "" {
 		likeSearch := "%" + search + "%"
-		baseQuery = baseQuery.Where(
-			"orders.invoice_id LIKE ? OR orders.user_id LIKE ? OR events.organizer LIKE ? OR events.title LIKE ? OR events.description LIKE ? OR events.location LIKE ? OR users.first_name LIKE ? OR users.last_name LIKE ? OR categories.name LIKE ?",
+		query = query.Where(
+			"orders.invoice_id LIKE ? OR orders.user_id LIKE ? OR events.organizer LIKE ? OR events.title LIKE ? OR events.description LIKE ? OR events.location LIKE ? OR users.first_name LIKE ? OR users.last_name LIKE ? OR EXISTS (SELECT 1 FROM order_items JOIN categories ON categories.id = order_items.category_id WHERE order_items.order_id = orders.id AND categories.name LIKE ?)",
 			likeSearch, likeSearch, likeSearch, likeSearch, likeSearch, likeSearch, likeSearch, likeSearch, likeSearch,
 		)
 	}
 
-	orderClause := "orders.created_at DESC"
-	if v, exists := validOrderings[orderBy]; exists {
-		orderClause = v
+	return query
+}
+
+var adminOrderings = map[string]string{
+	"asc":              "orders.created_at ASC",
+	"desc":             "orders.created_at DESC",
+	"quantity_asc":     "orders.quantity ASC",
+	"quantity_desc":    "orders.quantity DESC",
+	"total_price_asc":  "orders.total_price ASC",
+	"total_price_desc": "orders.total_price DESC",
+}
+
+func adminOrderClause(orderBy string) string {
+	if clause, exists := adminOrderings[orderBy]; exists {
+		return clause
 	}
-	baseQuery = baseQuery.Order(orderClause)
+	return "orders.created_at DESC"
+}
+
+func (r *orderRepository) GetOrdersAdmin(page, limit int, status, search string, orderBy string) ([]*entity.Order, int64, error) {
+	var orders []*entity.Order
+	var total int64
+
+	baseQuery := r.filterOrdersAdmin(status, search).Order(adminOrderClause(orderBy))
 
 	if err := baseQuery.Count(&total).Error; err != nil {
 		return nil, 0, err
 	}
 
-	dataQuery := baseQuery.
-		Preload("OrderDetails").
-		Preload("Category.Event").
-		Preload("User")
+	dataQuery := preloadOrder(baseQuery)
 
 	if page > 0 && limit > 0 {
 		dataQuery = dataQuery.Offset((page - 1) * limit).Limit(limit)
@@ -150,9 +361,53 @@ func (r *orderRepository) GetOrdersAdmin(page, limit int, status, search string,
 	return orders, total, nil
 }
 
+// GetOrdersAdminByCursor returns up to limit+1 orders around the cursor, in
+// cursor order, without counting the whole listing.
+func (r *orderRepository) GetOrdersAdminByCursor(c *cursor.Cursor, limit int, status, search string) ([]*entity.Order, error) {
+	var orders []*entity.Order
+
+	query := keysetPage(preloadOrder(r.filterOrdersAdmin(status, search)), "orders", c, limit)
+	if err := query.Find(&orders).Error; err != nil {
+		return nil, err
+	}
+
+	return orders, nil
+}
+
+// ExportOrdersAdmin streams every order of the admin listing to fn, one
+// flattened row at a time, without loading the listing into memory.
+func (r *orderRepository) ExportOrdersAdmin(status, search, orderBy string, fn func(row *models.OrderExportRow) error) error {
+	rows, err := r.filterOrdersAdmin(status, search).
+		Select(`orders.id, orders.invoice_id, orders.status, orders.quantity, orders.total_price,
+			orders.event_id, events.title AS event_title,
+			orders.user_id, users.first_name AS user_first_name, users.last_name AS user_last_name, users.email AS user_email,
+			(SELECT GROUP_CONCAT(CONCAT(c.name, ' x', oi.quantity) ORDER BY oi.created_at SEPARATOR '; ')
+				FROM order_items oi JOIN categories c ON c.id = oi.category_id
+				WHERE oi.order_id = orders.id AND oi.deleted_at IS NULL) AS items,
+			orders.created_at, orders.updated_at`).
+		Order(adminOrderClause(orderBy)).
+		Rows()
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var row models.OrderExportRow
+		if err := r.db.ScanRows(rows, &row); err != nil {
+			return err
+		}
+		if err := fn(&row); err != nil {
+			return err
+		}
+	}
+
+	return rows.Err()
+}
+
 func (r *orderRepository) GetOrderById(orderID uuid.UUID) (*entity.Order, error) {
 	var order entity.Order
-	if err := r.db.Preload("OrderDetails").Preload("Category.Event").Preload("User").Where("id = ?", orderID).First(&order).Error; err != nil {
+	if err := preloadOrder(r.db).Where("id = ?", orderID).First(&order).Error; err != nil {
 		return nil, err
 	}
 	return &order, nil
@@ -166,50 +421,222 @@ func (r *orderRepository) GetOrderDetailByTicketCode(ticketCode string) (*entity
 	return &orderDetail, nil
 }
 
+// CountUserTickets sums the tickets a user holds in a category across all
+// pending and paid orders.
+func (r *orderRepository) CountUserTickets(userID, categoryID uuid.UUID) (int64, error) {
+	return countUserTickets(r.db, userID, categoryID)
+}
+
+func countUserTickets(db *gorm.DB, userID, categoryID uuid.UUID) (int64, error) {
+	var total int64
+	if err := db.Model(&entity.OrderItem{}).
+		Select("COALESCE(SUM(order_items.quantity), 0)").
+		Joins("JOIN orders ON orders.id = order_items.order_id").
+		Where("orders.user_id = ? AND order_items.category_id = ? AND orders.status IN ?", userID, categoryID, []string{"pending", "paid"}).
+		Scan(&total).Error; err != nil {
+		return 0, err
+	}
+	return total, nil
+}
+
+// restockOrderItems returns the stock of an order's database-strategy items to
+// their categories. Redis-strategy stock is released by the inventory service.
+// Units that came from a waitlist claim go back to the held pool, whatever the
+// strategy, so the waitlist can offer them to the next person in line.
+func restockOrderItems(tx *gorm.DB, orderID uuid.UUID) error {
+	var items []*entity.OrderItem
+	if err := tx.Preload("Category").Where("order_id = ?", orderID).Find(&items).Error; err != nil {
+		return err
+	}
+
+	for _, item := range items {
+		if item.Quantity <= 0 {
+			continue
+		}
+
+		if item.WaitlistEntryID != nil {
+			if err := tx.Model(&entity.Category{}).
+				Where("id = ?", item.CategoryID).
+				Update("waitlist_held", gorm.Expr("waitlist_held + ?", item.Quantity)).Error; err != nil {
+				return err
+			}
+			continue
+		}
+
+		if item.Category == nil || item.Category.InventoryStrategy == "redis" {
+			continue
+		}
+
+		if err := tx.Model(&entity.Category{}).
+			Where("id = ?", item.CategoryID).
+			Updates(map[string]interface{}{
+				"quantity": gorm.Expr("quantity + ?", item.Quantity),
+				"status":   "available",
+			}).Error; err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// CancelOrder cancels a pending order. It returns ErrOrderStatusChanged,
+// leaving the stock alone, when the order was paid, expired or cancelled in
+// the meantime.
 func (r *orderRepository) CancelOrder(orderID uuid.UUID) error {
 	return r.db.Transaction(func(tx *gorm.DB) error {
 		var order entity.Order
-		if err := tx.Where("id = ?", orderID).First(&order).Error; err != nil {
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Where("id = ?", orderID).First(&order).Error; err != nil {
 			return err
 		}
 
-		if order.Quantity > 0 {
-			if err := tx.Model(&entity.Category{}).
-				Where("id = ?", order.CategoryID).
-				Update("quantity", gorm.Expr("quantity + ?", order.Quantity)).Error; err != nil {
+		if order.Status != "pending" {
+			return ErrOrderStatusChanged
+		}
+
+		if err := restockOrderItems(tx, order.ID); err != nil {
+			return err
+		}
+
+		if err := releaseOrderSeats(tx, order.ID); err != nil {
+			return err
+		}
+
+		if err := tx.Model(&entity.Order{}).
+			Where("id = ?", orderID).
+			Update("status", "cancelled").Error; err != nil {
+			return err
+		}
+
+		return recordStatusChange(tx, order.ID, order.Status, "cancelled")
+	})
+}
+
+// ExpireOrders expires those of the given orders that are still pending once
+// locked and returns their IDs. Orders paid or cancelled in the meantime are
+// left alone, so callers release Redis holds only for the returned orders.
+func (r *orderRepository) ExpireOrders(orderIDs []uuid.UUID) ([]uuid.UUID, error) {
+	if len(orderIDs) == 0 {
+		return nil, nil
+	}
+
+	var expired []uuid.UUID
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		var orders []*entity.Order
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("id IN ? AND status = ?", orderIDs, "pending").
+			Find(&orders).Error; err != nil {
+			return err
+		}
+
+		for _, order := range orders {
+			if err := restockOrderItems(tx, order.ID); err != nil {
 				return err
 			}
-			var category entity.Category
-			if err := tx.Where("id = ?", order.CategoryID).First(&category).Error; err == nil {
-				if category.Status == "sold" && category.Quantity > 0 {
-					if err := tx.Model(&entity.Category{}).
-						Where("id = ?", order.CategoryID).
-						Update("status", "available").Error; err != nil {
-						return err
-					}
-				}
+
+			if err := releaseOrderSeats(tx, order.ID); err != nil {
+				return err
 			}
+
+			if err := tx.Model(&entity.Order{}).
+				Where("id = ?", order.ID).
+				Update("status", "expired").Error; err != nil {
+				return err
+			}
+			expired = append(expired, order.ID)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return expired, nil
+}
+
+// GetStalePendingOrders returns up to limit orders still pending payment that
+// were placed before the given time, oldest first.
+func (r *orderRepository) GetStalePendingOrders(before time.Time, limit int) ([]*entity.Order, error) {
+	var orders []*entity.Order
+	if err := preloadOrder(r.db).
+		Where("status = ? AND created_at < ?", "pending", before).
+		Order("created_at ASC").
+		Limit(limit).
+		Find(&orders).Error; err != nil {
+		return nil, err
+	}
+	return orders, nil
+}
+
+// RefundOrder refunds a paid order. It returns ErrOrderStatusChanged, leaving
+// the stock alone, when the order is not paid anymore, so concurrent refunds
+// restock it only once.
+func (r *orderRepository) RefundOrder(orderID uuid.UUID) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		var order entity.Order
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Where("id = ?", orderID).First(&order).Error; err != nil {
+			return err
+		}
+
+		if order.Status != "paid" {
+			return ErrOrderStatusChanged
+		}
+
+		if err := restockOrderItems(tx, order.ID); err != nil {
+			return err
+		}
+
+		if err := releaseOrderSeats(tx, order.ID); err != nil {
+			return err
 		}
 
 		if err := tx.Model(&entity.Order{}).
 			Where("id = ?", orderID).
-			Update("status", "cancelled").Error; err != nil {
+			Update("status", "refunded").Error; err != nil {
 			return err
 		}
 
-		return nil
+		return recordStatusChange(tx, order.ID, order.Status, "refunded")
 	})
 }
 
+// VerifyOrderStatus marks a pending order as paid. It returns
+// ErrOrderStatusChanged when the order is not pending anymore, so an order
+// is counted in the sales rollup only once.
 func (r *orderRepository) VerifyOrderStatus(orderID uuid.UUID) error {
-	if err := r.db.Model(&entity.Order{}).Where("id = ?", orderID).Update("status", "paid").Error; err != nil {
-		return err
-	}
-	return nil
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		var order entity.Order
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Where("id = ?", orderID).First(&order).Error; err != nil {
+			return err
+		}
+
+		if order.Status != "pending" {
+			return ErrOrderStatusChanged
+		}
+
+		if err := tx.Model(&entity.CategorySeat{}).
+			Where("order_id = ? AND status = ?", orderID, "held").
+			Update("status", "booked").Error; err != nil {
+			return err
+		}
+
+		if err := tx.Model(&entity.Order{}).Where("id = ?", orderID).Updates(map[string]interface{}{
+			"status":  "paid",
+			"paid_at": time.Now(),
+		}).Error; err != nil {
+			return err
+		}
+
+		return recordStatusChange(tx, order.ID, order.Status, "paid")
+	})
 }
 
 func (r *orderRepository) VerifyTicket(id uuid.UUID) error {
-	if err := r.db.Model(&entity.OrderDetail{}).Where("id = ?", id).Update("redeemed", true).Error; err != nil {
+	if err := r.db.Model(&entity.OrderDetail{}).Where("id = ?", id).Updates(map[string]interface{}{
+		"redeemed":    true,
+		"redeemed_at": time.Now(),
+	}).Error; err != nil {
 		return err
 	}
 	return nil
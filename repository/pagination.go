@@ -0,0 +1,27 @@
+package repository
+
+import (
+	"fmt"
+	"ticert/utils/cursor"
+
+	"gorm.io/gorm"
+)
+
+// keysetPage limits a query to the page after the cursor in newest first
+// (created_at, id) order, or the page before it for backward cursors. One
+// extra row is fetched so cursor.Page can tell whether more rows follow.
+func keysetPage(query *gorm.DB, table string, c *cursor.Cursor, limit int) *gorm.DB {
+	createdAt := table + ".created_at"
+	id := table + ".id"
+
+	op, direction := "<", "DESC"
+	if c.Backward {
+		op, direction = ">", "ASC"
+	}
+
+	if !c.IsFirst() {
+		query = query.Where(fmt.Sprintf("%s %s ? OR (%s = ? AND %s %s ?)", createdAt, op, createdAt, id, op), c.CreatedAt, c.CreatedAt, c.ID)
+	}
+
+	return query.Order(createdAt + " " + direction).Order(id + " " + direction).Limit(limit + 1)
+}
@@ -1,68 +1,148 @@
 package repository
 
 import (
+	"fmt"
 	"ticert/entity"
 	"ticert/models"
+	"ticert/utils/cursor"
 	"time"
 
 	"github.com/google/uuid"
 	"gorm.io/gorm"
 )
 
+// ReportFilter narrows sales figures to paid orders created in
+// [StartDate, EndDate) and to an event or category. Nil fields leave the
+// corresponding filter off.
+type ReportFilter struct {
+	EventID    *uuid.UUID
+	CategoryID *uuid.UUID
+	StartDate  *time.Time
+	EndDate    *time.Time
+}
+
+// AttendanceFilter narrows attendance figures to an event or category and to
+// events starting in [StartDate, EndDate). Nil fields leave the
+// corresponding filter off.
+type AttendanceFilter struct {
+	EventID    *uuid.UUID
+	CategoryID *uuid.UUID
+	StartDate  *time.Time
+	EndDate    *time.Time
+}
+
 type ReportRepository interface {
-	GetTotalTicketsSold(startDate, endDate *time.Time) (int64, error)
-	GetTotalRevenue(startDate, endDate *time.Time) (float64, error)
+	GetSalesTotals(filter ReportFilter) (*models.EventTicketStats, error)
 	GetTotalEvents() (int64, error)
 	GetTotalCategories() (int64, error)
-	GetEventTicketStats(eventID uuid.UUID) (*models.EventTicketStats, error)
-	GetCategoryTicketStats(categoryID uuid.UUID) (*models.CategoryTicketStats, error)
-	GetEventReports(eventID *uuid.UUID, startDate, endDate *time.Time) ([]*models.EventReportData, error)
+	GetEventReports(filter ReportFilter, page, limit int) ([]*models.EventReportData, int64, error)
+	GetEventReportsByCursor(filter ReportFilter, c *cursor.Cursor, limit int) ([]*models.EventReportData, error)
+	GetEventReportsAfter(filter ReportFilter, afterID uuid.UUID, limit int) ([]*models.EventReportData, error)
+	GetCategoryReports(filter ReportFilter, eventIDs []uuid.UUID) ([]*models.CategoryReportData, error)
+	GetEventAttendance(filter AttendanceFilter, page, limit int) ([]*models.EventAttendanceData, int64, error)
+	GetCategoryAttendance(filter AttendanceFilter, eventIDs []uuid.UUID) ([]*models.CategoryAttendanceData, error)
+	GetCheckInMinutes(filter AttendanceFilter) ([]*models.CheckInMinuteData, int64, error)
 }
 
 type reportRepository struct {
-	db *gorm.DB
+	db      *gorm.DB
+	rollups *rollupAvailability
 }
 
 func NewReportRepository(db *gorm.DB) ReportRepository {
-	return &reportRepository{db: db}
+	return &reportRepository{
+		db:      db,
+		rollups: &rollupAvailability{db: db, name: salesRollupName},
+	}
 }
 
-func (r *reportRepository) GetTotalTicketsSold(startDate, endDate *time.Time) (int64, error) {
-	var total int64
-
-	query := r.db.Model(&entity.Order{}).Where("status = ?", "paid")
+// salesColumns names the columns of a sales source. Orders, Tickets and
+// Revenue are aggregates.
+type salesColumns struct {
+	EventID    string
+	CategoryID string
+	Orders     string
+	Tickets    string
+	Revenue    string
+}
 
-	if startDate != nil {
-		query = query.Where("created_at >= ?", startDate)
+var (
+	itemSales = salesColumns{
+		EventID:    "orders.event_id",
+		CategoryID: "order_items.category_id",
+		Orders:     "COUNT(DISTINCT order_items.order_id)",
+		Tickets:    "SUM(order_items.quantity)",
+		Revenue:    "SUM(order_items.subtotal)",
 	}
-	if endDate != nil {
-		query = query.Where("created_at <= ?", endDate)
+	rollupSales = salesColumns{
+		EventID:    "sales_daily.event_id",
+		CategoryID: "sales_daily.category_id",
+		Orders:     "SUM(sales_daily.order_count)",
+		Tickets:    "SUM(sales_daily.tickets_sold)",
+		Revenue:    "SUM(sales_daily.revenue)",
 	}
+)
 
-	if err := query.Select("COALESCE(SUM(quantity), 0)").Scan(&total).Error; err != nil {
-		return 0, err
+// sales selects the paid sales matching the filter from the sales_daily
+// rollup once it has been backfilled, and from the order items otherwise.
+// Report dates are whole UTC days, so both give the same figures; only the
+// order count differs, as the rollup counts an order once per category.
+func (r *reportRepository) sales(filter ReportFilter) (*gorm.DB, salesColumns) {
+	if !r.rollups.Available() {
+		return r.paidItems(filter), itemSales
 	}
 
-	return total, nil
-}
+	query := r.db.Table("sales_daily")
 
-func (r *reportRepository) GetTotalRevenue(startDate, endDate *time.Time) (float64, error) {
-	var total float64
+	if filter.EventID != nil {
+		query = query.Where("sales_daily.event_id = ?", *filter.EventID)
+	}
+	if filter.CategoryID != nil {
+		query = query.Where("sales_daily.category_id = ?", *filter.CategoryID)
+	}
+	if filter.StartDate != nil {
+		query = query.Where("sales_daily.day >= ?", filter.StartDate.Format("2006-01-02"))
+	}
+	if filter.EndDate != nil {
+		query = query.Where("sales_daily.day < ?", filter.EndDate.Format("2006-01-02"))
+	}
+
+	return query, rollupSales
+}
 
-	query := r.db.Model(&entity.Order{}).Where("status = ?", "paid")
+// paidItems selects the order items of paid orders matching the filter.
+func (r *reportRepository) paidItems(filter ReportFilter) *gorm.DB {
+	query := r.db.Table("order_items").
+		Joins("JOIN orders ON orders.id = order_items.order_id AND orders.deleted_at IS NULL").
+		Where("order_items.deleted_at IS NULL AND orders.status = ?", "paid")
 
-	if startDate != nil {
-		query = query.Where("created_at >= ?", startDate)
+	if filter.EventID != nil {
+		query = query.Where("orders.event_id = ?", *filter.EventID)
 	}
-	if endDate != nil {
-		query = query.Where("created_at <= ?", endDate)
+	if filter.CategoryID != nil {
+		query = query.Where("order_items.category_id = ?", *filter.CategoryID)
+	}
+	if filter.StartDate != nil {
+		query = query.Where("orders.created_at >= ?", *filter.StartDate)
+	}
+	if filter.EndDate != nil {
+		query = query.Where("orders.created_at < ?", *filter.EndDate)
 	}
 
-	if err := query.Select("COALESCE(SUM(total_price), 0)").Scan(&total).Error; err != nil {
-		return 0, err
+	return query
+}
+
+func (r *reportRepository) GetSalesTotals(filter ReportFilter) (*models.EventTicketStats, error) {
+	var stats models.EventTicketStats
+
+	query, c := r.sales(filter)
+	query = query.Select(fmt.Sprintf("COALESCE(%s, 0) AS total_orders, COALESCE(%s, 0) AS total_tickets, COALESCE(%s, 0) AS total_revenue", c.Orders, c.Tickets, c.Revenue))
+
+	if err := query.Scan(&stats).Error; err != nil {
+		return nil, err
 	}
 
-	return total, nil
+	return &stats, nil
 }
 
 func (r *reportRepository) GetTotalEvents() (int64, error) {
@@ -81,54 +161,181 @@ func (r *reportRepository) GetTotalCategories() (int64, error) {
 	return total, nil
 }
 
-func (r *reportRepository) GetEventTicketStats(eventID uuid.UUID) (*models.EventTicketStats, error) {
-	var stats models.EventTicketStats
+// eventReports sums the matching paid sales per event. Only events with
+// sales in the filter are included.
+func (r *reportRepository) eventReports(filter ReportFilter) *gorm.DB {
+	query, c := r.sales(filter)
+	return query.
+		Joins("JOIN events ON events.id = " + c.EventID).
+		Select(fmt.Sprintf(`events.id AS event_id, events.created_at AS event_created_at, events.title AS event_title,
+			events.starts_at AS event_starts_at, events.ends_at AS event_ends_at, events.timezone AS event_timezone,
+			COALESCE(%s, 0) AS total_sold, COALESCE(%s, 0) AS total_revenue`, c.Tickets, c.Revenue)).
+		Group("events.id")
+}
 
-	query := r.db.Model(&entity.Order{}).
-		Select("COUNT(*) as total_orders, SUM(orders.quantity) as total_tickets, COALESCE(SUM(orders.total_price), 0) as total_revenue").
-		Joins("JOIN categories ON orders.category_id = categories.id").
-		Where("categories.event_id = ? AND orders.status = ?", eventID, "paid")
+// GetEventReports returns one page of event reports, the events with the
+// highest revenue first, and the number of events with sales in the filter.
+func (r *reportRepository) GetEventReports(filter ReportFilter, page, limit int) ([]*models.EventReportData, int64, error) {
+	var results []*models.EventReportData
+	var total int64
 
-	if err := query.Scan(&stats).Error; err != nil {
+	sales, c := r.sales(filter)
+	if err := sales.Distinct(c.EventID).Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	query := r.eventReports(filter).
+		Order("total_revenue DESC").
+		Order("events.id ASC").
+		Offset((page - 1) * limit).
+		Limit(limit)
+
+	if err := query.Scan(&results).Error; err != nil {
+		return nil, 0, err
+	}
+
+	return results, total, nil
+}
+
+// GetEventReportsByCursor returns up to limit+1 event reports around the
+// cursor, keyed on the events' creation time, in cursor order.
+func (r *reportRepository) GetEventReportsByCursor(filter ReportFilter, c *cursor.Cursor, limit int) ([]*models.EventReportData, error) {
+	var results []*models.EventReportData
+
+	if err := keysetPage(r.eventReports(filter), "events", c, limit).Scan(&results).Error; err != nil {
 		return nil, err
 	}
 
-	return &stats, nil
+	return results, nil
 }
 
-func (r *reportRepository) GetCategoryTicketStats(categoryID uuid.UUID) (*models.CategoryTicketStats, error) {
-	var stats models.CategoryTicketStats
+// GetEventReportsAfter returns up to limit event reports with an ID after
+// afterID, in ID order. Unlike revenue order, the ID of an event never
+// changes, so walking every event this way neither skips nor repeats events
+// whose sales change in between. uuid.Nil starts at the first event.
+func (r *reportRepository) GetEventReportsAfter(filter ReportFilter, afterID uuid.UUID, limit int) ([]*models.EventReportData, error) {
+	var results []*models.EventReportData
 
-	query := r.db.Model(&entity.Order{}).
-		Select("COUNT(*) as total_orders, SUM(orders.quantity) as total_tickets, COALESCE(SUM(orders.total_price), 0) as total_revenue").
-		Where("category_id = ? AND status = ?", categoryID, "paid")
+	query := r.eventReports(filter)
+	if afterID != uuid.Nil {
+		query = query.Where("events.id > ?", afterID)
+	}
 
-	if err := query.Scan(&stats).Error; err != nil {
+	if err := query.Order("events.id ASC").Limit(limit).Scan(&results).Error; err != nil {
 		return nil, err
 	}
 
-	return &stats, nil
+	return results, nil
 }
 
-func (r *reportRepository) GetEventReports(eventID *uuid.UUID, startDate, endDate *time.Time) ([]*models.EventReportData, error) {
-	var results []*models.EventReportData
+// GetCategoryReports returns the categories of the given events with their
+// matching sales in one grouped query. Categories without sales are included
+// with zero figures; with a category filter only that category is returned.
+func (r *reportRepository) GetCategoryReports(filter ReportFilter, eventIDs []uuid.UUID) ([]*models.CategoryReportData, error) {
+	var results []*models.CategoryReportData
+	if len(eventIDs) == 0 {
+		return results, nil
+	}
+
+	sales, c := r.sales(filter)
+	sales = sales.
+		Select(fmt.Sprintf("%s AS category_id, %s AS total_orders, %s AS total_tickets, %s AS total_revenue", c.CategoryID, c.Orders, c.Tickets, c.Revenue)).
+		Group(c.CategoryID)
+
+	query := r.db.Table("categories").
+		Joins("LEFT JOIN (?) AS sales ON sales.category_id = categories.id", sales).
+		Select(`categories.id AS category_id, categories.event_id, categories.name AS category_name,
+			categories.quantity AS remaining_stock, categories.status,
+			COALESCE(sales.total_orders, 0) AS total_orders, COALESCE(sales.total_tickets, 0) AS total_tickets,
+			COALESCE(sales.total_revenue, 0) AS total_revenue`).
+		Where("categories.deleted_at IS NULL AND categories.event_id IN ?", eventIDs).
+		Order("categories.created_at ASC")
+
+	if filter.CategoryID != nil {
+		query = query.Where("categories.id = ?", *filter.CategoryID)
+	}
+
+	if err := query.Scan(&results).Error; err != nil {
+		return nil, err
+	}
+
+	return results, nil
+}
+
+// paidTickets selects the tickets of paid orders matching the filter.
+func (r *reportRepository) paidTickets(filter AttendanceFilter) *gorm.DB {
+	query := r.db.Table("order_details").
+		Joins("JOIN orders ON orders.id = order_details.order_id AND orders.deleted_at IS NULL").
+		Joins("JOIN order_items ON order_items.id = order_details.order_item_id AND order_items.deleted_at IS NULL").
+		Joins("JOIN events ON events.id = orders.event_id AND events.deleted_at IS NULL").
+		Where("order_details.deleted_at IS NULL AND orders.status = ?", "paid")
+
+	if filter.EventID != nil {
+		query = query.Where("orders.event_id = ?", *filter.EventID)
+	}
+	if filter.CategoryID != nil {
+		query = query.Where("order_items.category_id = ?", *filter.CategoryID)
+	}
+	if filter.StartDate != nil {
+		query = query.Where("events.starts_at >= ?", *filter.StartDate)
+	}
+	if filter.EndDate != nil {
+		query = query.Where("events.starts_at < ?", *filter.EndDate)
+	}
+
+	return query
+}
 
-	query := r.db.Model(&entity.Order{}).
-		Select("categories.event_id, SUM(orders.quantity) as total_sold, COALESCE(SUM(orders.total_price), 0) as total_revenue").
-		Joins("JOIN categories ON orders.category_id = categories.id").
-		Where("orders.status = ?", "paid")
+// GetEventAttendance returns one page of events with paid tickets in the
+// filter, the latest starting first, and the number of such events.
+func (r *reportRepository) GetEventAttendance(filter AttendanceFilter, page, limit int) ([]*models.EventAttendanceData, int64, error) {
+	var results []*models.EventAttendanceData
+	var total int64
 
-	if eventID != nil {
-		query = query.Where("categories.event_id = ?", eventID)
+	if err := r.paidTickets(filter).Distinct("orders.event_id").Count(&total).Error; err != nil {
+		return nil, 0, err
 	}
-	if startDate != nil {
-		query = query.Where("orders.created_at >= ?", startDate)
+
+	query := r.paidTickets(filter).
+		Select(`events.id AS event_id, events.title AS event_title,
+			events.starts_at AS event_starts_at, events.ends_at AS event_ends_at, events.timezone AS event_timezone,
+			COUNT(*) AS tickets_sold, COUNT(CASE WHEN order_details.redeemed THEN 1 END) AS checked_in`).
+		Group("events.id").
+		Order("events.starts_at DESC").
+		Order("events.id ASC").
+		Offset((page - 1) * limit).
+		Limit(limit)
+
+	if err := query.Scan(&results).Error; err != nil {
+		return nil, 0, err
 	}
-	if endDate != nil {
-		query = query.Where("orders.created_at <= ?", endDate)
+
+	return results, total, nil
+}
+
+// GetCategoryAttendance returns the categories of the given events with
+// their paid and checked-in tickets. Categories without paid tickets are
+// included with zero figures.
+func (r *reportRepository) GetCategoryAttendance(filter AttendanceFilter, eventIDs []uuid.UUID) ([]*models.CategoryAttendanceData, error) {
+	var results []*models.CategoryAttendanceData
+	if len(eventIDs) == 0 {
+		return results, nil
 	}
 
-	query = query.Group("categories.event_id")
+	tickets := r.paidTickets(filter).
+		Select("order_items.category_id, COUNT(*) AS tickets_sold, COUNT(CASE WHEN order_details.redeemed THEN 1 END) AS checked_in").
+		Group("order_items.category_id")
+
+	query := r.db.Table("categories").
+		Joins("LEFT JOIN (?) AS tickets ON tickets.category_id = categories.id", tickets).
+		Select(`categories.id AS category_id, categories.event_id, categories.name AS category_name,
+			COALESCE(tickets.tickets_sold, 0) AS tickets_sold, COALESCE(tickets.checked_in, 0) AS checked_in`).
+		Where("categories.deleted_at IS NULL AND categories.event_id IN ?", eventIDs).
+		Order("categories.created_at ASC")
+
+	if filter.CategoryID != nil {
+		query = query.Where("categories.id = ?", *filter.CategoryID)
+	}
 
 	if err := query.Scan(&results).Error; err != nil {
 		return nil, err
@@ -136,3 +343,29 @@ func (r *reportRepository) GetEventReports(eventID *uuid.UUID, startDate, endDat
 
 	return results, nil
 }
+
+// GetCheckInMinutes counts the check-ins of the filtered tickets per minute
+// since the start of their event, and returns how many checked-in tickets
+// have no recorded check-in time.
+func (r *reportRepository) GetCheckInMinutes(filter AttendanceFilter) ([]*models.CheckInMinuteData, int64, error) {
+	var results []*models.CheckInMinuteData
+	var undated int64
+
+	if err := r.paidTickets(filter).
+		Where("order_details.redeemed = ? AND order_details.redeemed_at IS NULL", true).
+		Count(&undated).Error; err != nil {
+		return nil, 0, err
+	}
+
+	query := r.paidTickets(filter).
+		Select("FLOOR(TIMESTAMPDIFF(SECOND, events.starts_at, order_details.redeemed_at) / 60) AS arrival_minute, COUNT(*) AS check_ins").
+		Where("order_details.redeemed = ? AND order_details.redeemed_at IS NOT NULL", true).
+		Group("arrival_minute").
+		Order("arrival_minute ASC")
+
+	if err := query.Scan(&results).Error; err != nil {
+		return nil, 0, err
+	}
+
+	return results, undated, nil
+}
@@ -0,0 +1,336 @@
+package repository
+
+import (
+	"testing"
+	"ticert/entity"
+	"ticert/models"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// reportSchema creates the tables read by the report queries. The MySQL
+// column types of the entities do not exist in SQLite, so they are spelled
+// out here instead of auto migrated.
+var reportSchema = []string{
+	`CREATE TABLE events (
+		id TEXT PRIMARY KEY, organizer TEXT, title TEXT, description TEXT,
+		starts_at DATETIME, ends_at DATETIME, timezone TEXT, location TEXT, status TEXT,
+		series_id TEXT, venue_id TEXT, seat_map_id TEXT,
+		created_at DATETIME, updated_at DATETIME, deleted_at DATETIME)`,
+	`CREATE TABLE categories (
+		id TEXT PRIMARY KEY, event_id TEXT, name TEXT, price REAL, event_date DATETIME,
+		quantity INTEGER, status TEXT, inventory_strategy TEXT, seating_mode TEXT,
+		sales_start_at DATETIME, sales_end_at DATETIME,
+		min_per_order INTEGER, max_per_order INTEGER, max_per_user INTEGER, waitlist_held INTEGER,
+		created_at DATETIME, updated_at DATETIME, deleted_at DATETIME)`,
+	`CREATE TABLE orders (
+		id TEXT PRIMARY KEY, event_id TEXT, user_id TEXT, invoice_id TEXT, status TEXT,
+		quantity INTEGER, total_price REAL, paid_at DATETIME,
+		created_at DATETIME, updated_at DATETIME, deleted_at DATETIME)`,
+	`CREATE TABLE order_items (
+		id TEXT PRIMARY KEY, order_id TEXT, category_id TEXT, price_tier_id TEXT, waitlist_entry_id TEXT,
+		quantity INTEGER, unit_price REAL, subtotal REAL,
+		created_at DATETIME, updated_at DATETIME, deleted_at DATETIME)`,
+	`CREATE TABLE sales_daily (
+		event_id TEXT, category_id TEXT, day DATE,
+		order_count INTEGER, tickets_sold INTEGER, revenue REAL, updated_at DATETIME,
+		PRIMARY KEY (event_id, category_id, day))`,
+	`CREATE TABLE rollup_states (name TEXT PRIMARY KEY, rebuilt_at DATETIME)`,
+}
+
+// reportFixture is the seeded data: a concert with sales on both sides of
+// the 10-12 Jan 2025 report window, a festival with one sale inside it and a
+// workshop with only a pending order.
+type reportFixture struct {
+	concert, festival, workshop  uuid.UUID
+	vip, regular, balcony, entry uuid.UUID
+}
+
+func newReportTestDB(t *testing.T) (*gorm.DB, reportFixture) {
+	t.Helper()
+
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{Logger: logger.Default.LogMode(logger.Silent)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	sqlDB, err := db.DB()
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Every connection to ":memory:" opens its own empty database.
+	sqlDB.SetMaxOpenConns(1)
+	t.Cleanup(func() { sqlDB.Close() })
+
+	for _, statement := range reportSchema {
+		if err := db.Exec(statement).Error; err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	created := time.Date(2024, 12, 1, 0, 0, 0, 0, time.UTC)
+	starts := time.Date(2025, 2, 1, 19, 0, 0, 0, time.UTC)
+
+	var f reportFixture
+	event := func(id *uuid.UUID, title string, offset time.Duration) {
+		e := &entity.Event{Title: title, StartsAt: starts, EndsAt: starts.Add(3 * time.Hour), Timezone: "UTC", Status: "published", CreatedAt: created.Add(offset)}
+		if err := db.Create(e).Error; err != nil {
+			t.Fatal(err)
+		}
+		*id = e.ID
+	}
+	category := func(id *uuid.UUID, eventID uuid.UUID, name string, quantity int, offset time.Duration) {
+		c := &entity.Category{EventID: eventID, Name: name, Quantity: quantity, EventDate: starts, Status: "available", CreatedAt: created.Add(offset)}
+		if err := db.Create(c).Error; err != nil {
+			t.Fatal(err)
+		}
+		*id = c.ID
+	}
+
+	event(&f.concert, "Concert", 0)
+	event(&f.festival, "Festival", time.Hour)
+	event(&f.workshop, "Workshop", 2*time.Hour)
+
+	category(&f.vip, f.concert, "VIP", 10, 0)
+	category(&f.regular, f.concert, "Regular", 40, time.Minute)
+	category(&f.balcony, f.concert, "Balcony", 25, 2*time.Minute)
+	category(&f.entry, f.festival, "Entry", 96, 3*time.Minute)
+	var seminar uuid.UUID
+	category(&seminar, f.workshop, "Seminar", 20, 4*time.Minute)
+
+	type line struct {
+		category  uuid.UUID
+		quantity  int
+		unitPrice float64
+	}
+	order := func(eventID uuid.UUID, status string, createdAt time.Time, lines ...line) {
+		o := &entity.Order{EventID: eventID, UserID: uuid.New(), InvoiceID: uuid.NewString(), Status: status, CreatedAt: createdAt}
+		for _, l := range lines {
+			subtotal := float64(l.quantity) * l.unitPrice
+			o.OrderItems = append(o.OrderItems, &entity.OrderItem{CategoryID: l.category, Quantity: l.quantity, UnitPrice: l.unitPrice, Subtotal: subtotal, CreatedAt: createdAt})
+			o.Quantity += l.quantity
+			o.TotalPrice += subtotal
+		}
+		if err := db.Create(o).Error; err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	order(f.concert, "paid", time.Date(2025, 1, 9, 23, 59, 59, 0, time.UTC), line{f.vip, 1, 100})
+	order(f.concert, "paid", time.Date(2025, 1, 10, 0, 0, 0, 0, time.UTC), line{f.vip, 2, 100}, line{f.regular, 1, 50})
+	order(f.concert, "pending", time.Date(2025, 1, 11, 9, 0, 0, 0, time.UTC), line{f.vip, 5, 100})
+	order(f.concert, "refunded", time.Date(2025, 1, 11, 10, 0, 0, 0, time.UTC), line{f.regular, 2, 50})
+	order(f.concert, "paid", time.Date(2025, 1, 12, 23, 59, 59, 0, time.UTC), line{f.regular, 3, 50})
+	order(f.concert, "paid", time.Date(2025, 1, 13, 0, 0, 0, 0, time.UTC), line{f.vip, 1, 100})
+	order(f.festival, "paid", time.Date(2025, 1, 11, 12, 0, 0, 0, time.UTC), line{f.entry, 4, 25})
+	order(f.workshop, "pending", time.Date(2025, 1, 11, 12, 0, 0, 0, time.UTC), line{seminar, 1, 75})
+
+	return db, f
+}
+
+// reportWindow is the filter for 10-12 Jan 2025 as the report service builds
+// it: the end date is inclusive, so the filter ends at the next midnight.
+func reportWindow() ReportFilter {
+	start := time.Date(2025, 1, 10, 0, 0, 0, 0, time.UTC)
+	end := time.Date(2025, 1, 13, 0, 0, 0, 0, time.UTC)
+	return ReportFilter{StartDate: &start, EndDate: &end}
+}
+
+// reportSources runs fn against the order items and against the rebuilt
+// sales rollup, which must report the same sales.
+func reportSources(t *testing.T, fn func(t *testing.T, repo ReportRepository, f reportFixture, rollup bool)) {
+	t.Run("order items", func(t *testing.T) {
+		db, f := newReportTestDB(t)
+		fn(t, NewReportRepository(db), f, false)
+	})
+	t.Run("sales rollup", func(t *testing.T) {
+		db, f := newReportTestDB(t)
+		if err := NewSalesRollupRepository(db).Rebuild(); err != nil {
+			t.Fatal(err)
+		}
+		fn(t, NewReportRepository(db), f, true)
+	})
+}
+
+func TestReportRepository_GetSalesTotals(t *testing.T) {
+	reportSources(t, func(t *testing.T, repo ReportRepository, f reportFixture, rollup bool) {
+		window := reportWindow()
+		concertWindow := reportWindow()
+		concertWindow.EventID = &f.concert
+		regularWindow := reportWindow()
+		regularWindow.CategoryID = &f.regular
+		untilStart := ReportFilter{EndDate: window.StartDate}
+
+		tests := []struct {
+			name   string
+			filter ReportFilter
+			// The rollup counts an order once per category it bought.
+			orders, rollupOrders int64
+			tickets              int64
+			revenue              float64
+		}{
+			{name: "all time", filter: ReportFilter{}, orders: 5, rollupOrders: 6, tickets: 12, revenue: 700},
+			{name: "window includes its end date", filter: window, orders: 3, rollupOrders: 4, tickets: 10, revenue: 500},
+			{name: "end date is exclusive in the filter", filter: untilStart, orders: 1, rollupOrders: 1, tickets: 1, revenue: 100},
+			{name: "event", filter: concertWindow, orders: 2, rollupOrders: 3, tickets: 6, revenue: 400},
+			{name: "category", filter: regularWindow, orders: 2, rollupOrders: 2, tickets: 4, revenue: 200},
+			{name: "event without sales", filter: ReportFilter{EventID: &f.workshop}},
+		}
+
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				stats, err := repo.GetSalesTotals(tt.filter)
+				if err != nil {
+					t.Fatal(err)
+				}
+
+				want := models.EventTicketStats{TotalOrders: tt.orders, TotalTickets: tt.tickets, TotalRevenue: tt.revenue}
+				if rollup {
+					want.TotalOrders = tt.rollupOrders
+				}
+				if *stats != want {
+					t.Errorf("GetSalesTotals() = %+v, want %+v", *stats, want)
+				}
+			})
+		}
+	})
+}
+
+func TestReportRepository_GetEventReports(t *testing.T) {
+	reportSources(t, func(t *testing.T, repo ReportRepository, f reportFixture, _ bool) {
+		regularWindow := reportWindow()
+		regularWindow.CategoryID = &f.regular
+		concert := ReportFilter{EventID: &f.concert}
+
+		type report struct {
+			event   uuid.UUID
+			sold    int64
+			revenue float64
+		}
+
+		tests := []struct {
+			name        string
+			filter      ReportFilter
+			page, limit int
+			want        []report
+			total       int64
+		}{
+			{
+				name:   "highest revenue first",
+				filter: reportWindow(), page: 1, limit: 10,
+				want:  []report{{f.concert, 6, 400}, {f.festival, 4, 100}},
+				total: 2,
+			},
+			{name: "first page", filter: reportWindow(), page: 1, limit: 1, want: []report{{f.concert, 6, 400}}, total: 2},
+			{name: "second page", filter: reportWindow(), page: 2, limit: 1, want: []report{{f.festival, 4, 100}}, total: 2},
+			{name: "past the last page", filter: reportWindow(), page: 3, limit: 1, total: 2},
+			{name: "event", filter: concert, page: 1, limit: 10, want: []report{{f.concert, 8, 600}}, total: 1},
+			{name: "category", filter: regularWindow, page: 1, limit: 10, want: []report{{f.concert, 4, 200}}, total: 1},
+		}
+
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				results, total, err := repo.GetEventReports(tt.filter, tt.page, tt.limit)
+				if err != nil {
+					t.Fatal(err)
+				}
+				if total != tt.total {
+					t.Errorf("total = %d, want %d", total, tt.total)
+				}
+
+				if len(results) != len(tt.want) {
+					t.Fatalf("got %d reports, want %d", len(results), len(tt.want))
+				}
+				for i, want := range tt.want {
+					got := results[i]
+					if got.EventID != want.event || got.TotalSold != want.sold || got.TotalRevenue != want.revenue {
+						t.Errorf("report %d = {%s %d %v}, want {%s %d %v}", i, got.EventID, got.TotalSold, got.TotalRevenue, want.event, want.sold, want.revenue)
+					}
+				}
+			})
+		}
+	})
+}
+
+func TestReportRepository_GetEventReportsAfter(t *testing.T) {
+	reportSources(t, func(t *testing.T, repo ReportRepository, f reportFixture, _ bool) {
+		want := []uuid.UUID{f.concert, f.festival}
+		if want[1].String() < want[0].String() {
+			want[0], want[1] = want[1], want[0]
+		}
+
+		var got []uuid.UUID
+		var afterID uuid.UUID
+		for {
+			results, err := repo.GetEventReportsAfter(reportWindow(), afterID, 1)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(results) == 0 {
+				break
+			}
+			afterID = results[0].EventID
+			got = append(got, afterID)
+		}
+
+		if len(got) != len(want) || got[0] != want[0] || got[1] != want[1] {
+			t.Errorf("events = %v, want %v", got, want)
+		}
+	})
+}
+
+func TestReportRepository_GetCategoryReports(t *testing.T) {
+	reportSources(t, func(t *testing.T, repo ReportRepository, f reportFixture, _ bool) {
+		regularWindow := reportWindow()
+		regularWindow.CategoryID = &f.regular
+
+		tests := []struct {
+			name     string
+			filter   ReportFilter
+			eventIDs []uuid.UUID
+			want     []models.CategoryReportData
+		}{
+			{
+				name:     "categories without sales included",
+				filter:   reportWindow(),
+				eventIDs: []uuid.UUID{f.concert, f.festival},
+				want: []models.CategoryReportData{
+					{CategoryID: f.vip, EventID: f.concert, CategoryName: "VIP", RemainingStock: 10, Status: "available", TotalOrders: 1, TotalTickets: 2, TotalRevenue: 200},
+					{CategoryID: f.regular, EventID: f.concert, CategoryName: "Regular", RemainingStock: 40, Status: "available", TotalOrders: 2, TotalTickets: 4, TotalRevenue: 200},
+					{CategoryID: f.balcony, EventID: f.concert, CategoryName: "Balcony", RemainingStock: 25, Status: "available"},
+					{CategoryID: f.entry, EventID: f.festival, CategoryName: "Entry", RemainingStock: 96, Status: "available", TotalOrders: 1, TotalTickets: 4, TotalRevenue: 100},
+				},
+			},
+			{
+				name:     "category",
+				filter:   regularWindow,
+				eventIDs: []uuid.UUID{f.concert},
+				want: []models.CategoryReportData{
+					{CategoryID: f.regular, EventID: f.concert, CategoryName: "Regular", RemainingStock: 40, Status: "available", TotalOrders: 2, TotalTickets: 4, TotalRevenue: 200},
+				},
+			},
+			{name: "no events", filter: reportWindow()},
+		}
+
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				results, err := repo.GetCategoryReports(tt.filter, tt.eventIDs)
+				if err != nil {
+					t.Fatal(err)
+				}
+
+				if len(results) != len(tt.want) {
+					t.Fatalf("got %d reports, want %d", len(results), len(tt.want))
+				}
+				for i, want := range tt.want {
+					if *results[i] != want {
+						t.Errorf("report %d = %+v, want %+v", i, *results[i], want)
+					}
+				}
+			})
+		}
+	})
+}
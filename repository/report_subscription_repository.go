@@ -0,0 +1,168 @@
+package repository
+
+import (
+	"ticert/entity"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type ReportSubscriptionRepository interface {
+	CreateSubscription(subscription *entity.ReportSubscription) error
+	GetSubscriptionByID(id uuid.UUID) (*entity.ReportSubscription, error)
+	GetSubscriptions(page, limit int) ([]*entity.ReportSubscription, int64, error)
+	UpdateSubscription(subscription *entity.ReportSubscription) error
+	DeleteSubscription(id uuid.UUID) error
+	ClaimDueRuns(now time.Time, limit int, plan func(subscription *entity.ReportSubscription) *entity.ReportRun) ([]*entity.ReportRun, error)
+	FinishRun(run *entity.ReportRun) error
+	GetRuns(subscriptionID uuid.UUID, page, limit int) ([]*entity.ReportRun, int64, error)
+}
+
+type reportSubscriptionRepository struct {
+	db *gorm.DB
+}
+
+func NewReportSubscriptionRepository(db *gorm.DB) ReportSubscriptionRepository {
+	return &reportSubscriptionRepository{db: db}
+}
+
+func (r *reportSubscriptionRepository) CreateSubscription(subscription *entity.ReportSubscription) error {
+	if err := r.db.Omit(clause.Associations).Create(subscription).Error; err != nil {
+		return err
+	}
+	return nil
+}
+
+func (r *reportSubscriptionRepository) GetSubscriptionByID(id uuid.UUID) (*entity.ReportSubscription, error) {
+	var subscription entity.ReportSubscription
+	if err := r.db.Preload("Event").Where("id = ?", id).First(&subscription).Error; err != nil {
+		return nil, err
+	}
+	return &subscription, nil
+}
+
+func (r *reportSubscriptionRepository) GetSubscriptions(page, limit int) ([]*entity.ReportSubscription, int64, error) {
+	var subscriptions []*entity.ReportSubscription
+	var total int64
+
+	query := r.db.Model(&entity.ReportSubscription{})
+
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	if err := query.Preload("Event").
+		Order("created_at DESC").
+		Offset((page - 1) * limit).
+		Limit(limit).
+		Find(&subscriptions).Error; err != nil {
+		return nil, 0, err
+	}
+
+	return subscriptions, total, nil
+}
+
+func (r *reportSubscriptionRepository) UpdateSubscription(subscription *entity.ReportSubscription) error {
+	if err := r.db.Model(&entity.ReportSubscription{}).
+		Where("id = ?", subscription.ID).
+		Select("recipient", "event_id", "kind", "schedule", "timezone", "format", "active", "next_run_at").
+		Updates(subscription).Error; err != nil {
+		return err
+	}
+	return nil
+}
+
+func (r *reportSubscriptionRepository) DeleteSubscription(id uuid.UUID) error {
+	if err := r.db.Where("id = ?", id).Delete(&entity.ReportSubscription{}).Error; err != nil {
+		return err
+	}
+	return nil
+}
+
+// ClaimDueRuns locks up to limit active subscriptions due at now and lets
+// plan move each one to its next run. The runs plan returns are saved as
+// running in the same transaction, so schedulers in other instances skip
+// these subscriptions and never send a run twice. A nil run reschedules the
+// subscription without sending anything.
+func (r *reportSubscriptionRepository) ClaimDueRuns(now time.Time, limit int, plan func(subscription *entity.ReportSubscription) *entity.ReportRun) ([]*entity.ReportRun, error) {
+	var runs []*entity.ReportRun
+
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		var subscriptions []*entity.ReportSubscription
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+			Where("active = ? AND next_run_at <= ?", true, now).
+			Order("next_run_at ASC").
+			Limit(limit).
+			Find(&subscriptions).Error; err != nil {
+			return err
+		}
+
+		for _, subscription := range subscriptions {
+			if subscription.EventID != nil {
+				var event entity.Event
+				if err := tx.Where("id = ?", *subscription.EventID).First(&event).Error; err == nil {
+					subscription.Event = &event
+				}
+			}
+
+			run := plan(subscription)
+
+			if err := tx.Model(&entity.ReportSubscription{}).
+				Where("id = ?", subscription.ID).
+				Select("active", "next_run_at", "last_run_at").
+				Updates(subscription).Error; err != nil {
+				return err
+			}
+
+			if run == nil {
+				continue
+			}
+
+			run.SubscriptionID = subscription.ID
+			if err := tx.Omit(clause.Associations).Create(run).Error; err != nil {
+				return err
+			}
+			run.Subscription = subscription
+			runs = append(runs, run)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return runs, nil
+}
+
+func (r *reportSubscriptionRepository) FinishRun(run *entity.ReportRun) error {
+	if err := r.db.Model(&entity.ReportRun{}).
+		Where("id = ?", run.ID).
+		Select("status", "error", "finished_at").
+		Updates(run).Error; err != nil {
+		return err
+	}
+	return nil
+}
+
+func (r *reportSubscriptionRepository) GetRuns(subscriptionID uuid.UUID, page, limit int) ([]*entity.ReportRun, int64, error) {
+	var runs []*entity.ReportRun
+	var total int64
+
+	query := r.db.Model(&entity.ReportRun{}).Where("subscription_id = ?", subscriptionID)
+
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	if err := query.Order("scheduled_at DESC").
+		Offset((page - 1) * limit).
+		Limit(limit).
+		Find(&runs).Error; err != nil {
+		return nil, 0, err
+	}
+
+	return runs, total, nil
+}
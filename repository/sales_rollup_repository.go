@@ -0,0 +1,157 @@
+package repository
+
+import (
+	"sync"
+	"ticert/entity"
+	"ticert/models"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+const salesRollupName = "sales_daily"
+
+// rawSalesDaily aggregates the paid order items per event, category and the
+// UTC day their order was placed, in the shape of the sales_daily table.
+const rawSalesDaily = `SELECT orders.event_id, order_items.category_id, DATE(orders.created_at) AS day,
+		COUNT(DISTINCT orders.id) AS order_count, SUM(order_items.quantity) AS tickets_sold,
+		SUM(order_items.subtotal) AS revenue
+	FROM order_items
+	JOIN orders ON orders.id = order_items.order_id AND orders.deleted_at IS NULL
+	WHERE order_items.deleted_at IS NULL AND orders.status = 'paid'
+	GROUP BY orders.event_id, order_items.category_id, DATE(orders.created_at)`
+
+type SalesRollupRepository interface {
+	Rebuild() error
+	GetRebuiltAt() (*time.Time, error)
+	GetRollupRows() ([]*models.SalesRollupRow, error)
+	GetRawRows() ([]*models.SalesRollupRow, error)
+}
+
+type salesRollupRepository struct {
+	db *gorm.DB
+}
+
+func NewSalesRollupRepository(db *gorm.DB) SalesRollupRepository {
+	return &salesRollupRepository{db: db}
+}
+
+// Rebuild replaces the rollup with a fresh aggregation of the raw tables and
+// marks it as available to reports. Reading the paid orders locks them, so
+// status changes made during the rebuild wait for it instead of being lost.
+func (r *salesRollupRepository) Rebuild() error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Exec("DELETE FROM sales_daily").Error; err != nil {
+			return err
+		}
+
+		if err := tx.Exec(`INSERT INTO sales_daily (event_id, category_id, day, order_count, tickets_sold, revenue, updated_at)
+			SELECT raw.*, ? FROM (`+rawSalesDaily+`) AS raw`, time.Now()).Error; err != nil {
+			return err
+		}
+
+		return tx.Save(&entity.RollupState{Name: salesRollupName, RebuiltAt: time.Now()}).Error
+	})
+}
+
+// GetRebuiltAt returns when the rollup was last rebuilt, or nil if it never
+// was.
+func (r *salesRollupRepository) GetRebuiltAt() (*time.Time, error) {
+	var states []entity.RollupState
+	if err := r.db.Where("name = ?", salesRollupName).Limit(1).Find(&states).Error; err != nil {
+		return nil, err
+	}
+	if len(states) == 0 {
+		return nil, nil
+	}
+	return &states[0].RebuiltAt, nil
+}
+
+func (r *salesRollupRepository) GetRollupRows() ([]*models.SalesRollupRow, error) {
+	var rows []*models.SalesRollupRow
+	if err := r.db.Table("sales_daily").
+		Select("event_id, category_id, day, order_count, tickets_sold, revenue").
+		Scan(&rows).Error; err != nil {
+		return nil, err
+	}
+	return rows, nil
+}
+
+func (r *salesRollupRepository) GetRawRows() ([]*models.SalesRollupRow, error) {
+	var rows []*models.SalesRollupRow
+	if err := r.db.Raw(rawSalesDaily).Scan(&rows).Error; err != nil {
+		return nil, err
+	}
+	return rows, nil
+}
+
+// applySalesRollup adds the items of an order to the rollup, or with a
+// negative sign takes them out again. It runs in the transaction changing
+// the order's status, so the rollup moves together with the order.
+func applySalesRollup(tx *gorm.DB, orderID uuid.UUID, sign int) error {
+	return tx.Exec(`INSERT INTO sales_daily (event_id, category_id, day, order_count, tickets_sold, revenue, updated_at)
+		SELECT * FROM (
+			SELECT orders.event_id, order_items.category_id, DATE(orders.created_at) AS day,
+				? * COUNT(DISTINCT orders.id) AS order_delta, ? * SUM(order_items.quantity) AS tickets_delta,
+				? * SUM(order_items.subtotal) AS revenue_delta, ? AS changed_at
+			FROM order_items
+			JOIN orders ON orders.id = order_items.order_id
+			WHERE orders.id = ? AND order_items.deleted_at IS NULL
+			GROUP BY orders.event_id, order_items.category_id, DATE(orders.created_at)
+		) AS delta
+		ON DUPLICATE KEY UPDATE
+			order_count = sales_daily.order_count + delta.order_delta,
+			tickets_sold = sales_daily.tickets_sold + delta.tickets_delta,
+			revenue = sales_daily.revenue + delta.revenue_delta,
+			updated_at = delta.changed_at`,
+		sign, sign, sign, time.Now(), orderID).Error
+}
+
+// recordStatusChange keeps the rollup in step with an order moving from one
+// status to another. Only moves into or out of "paid" change the sales.
+func recordStatusChange(tx *gorm.DB, orderID uuid.UUID, from, to string) error {
+	switch {
+	case from != "paid" && to == "paid":
+		return applySalesRollup(tx, orderID, 1)
+	case from == "paid" && to != "paid":
+		if err := applySalesRollup(tx, orderID, -1); err != nil {
+			return err
+		}
+		// Drop the days left without sales, so reports from the rollup
+		// list the same events as reports from the orders.
+		return tx.Exec(`DELETE FROM sales_daily
+			WHERE order_count = 0 AND event_id = (SELECT event_id FROM orders WHERE id = ?)`, orderID).Error
+	}
+	return nil
+}
+
+// rollupAvailability reports whether a rollup has been rebuilt at least
+// once, which is when it holds the full history and can stand in for the
+// raw tables. Rebuilds run in another process, so a negative answer is
+// checked again after a minute.
+type rollupAvailability struct {
+	db   *gorm.DB
+	name string
+
+	mu        sync.Mutex
+	available bool
+	checkedAt time.Time
+}
+
+func (a *rollupAvailability) Available() bool {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	if a.available || time.Since(a.checkedAt) < time.Minute {
+		return a.available
+	}
+
+	var count int64
+	if err := a.db.Model(&entity.RollupState{}).Where("name = ?", a.name).Count(&count).Error; err == nil {
+		a.available = count > 0
+	}
+	a.checkedAt = time.Now()
+
+	return a.available
+}
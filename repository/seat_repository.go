@@ -0,0 +1,304 @@
+package repository
+
+import (
+	"errors"
+	"ticert/entity"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+var (
+	ErrSeatUnavailable       = errors.New("seat unavailable")
+	ErrSeatsInUse            = errors.New("seats in use")
+	ErrSeatAssignedElsewhere = errors.New("seat assigned to another category")
+)
+
+type SeatRepository interface {
+	CreateSeatMap(seatMap *entity.SeatMap) error
+	GetSeatMapByID(id uuid.UUID) (*entity.SeatMap, error)
+	GetSeatMaps() ([]*entity.SeatMap, error)
+	DeleteSeatMap(id uuid.UUID) error
+	CountEventsUsingSeatMap(seatMapID uuid.UUID) (int64, error)
+	FindSeatIDs(seatMapID uuid.UUID, sectionIDs, seatIDs []uuid.UUID) ([]uuid.UUID, error)
+	CountEventSeats(eventID uuid.UUID) (int64, error)
+	GetEventSeats(eventID uuid.UUID) ([]*entity.CategorySeat, error)
+	ReplaceCategorySeats(category *entity.Category, seatIDs []uuid.UUID) error
+	GetLapsedHoldOrderIDs(heldBefore time.Time) ([]uuid.UUID, error)
+}
+
+type seatRepository struct {
+	db *gorm.DB
+}
+
+func NewSeatRepository(db *gorm.DB) SeatRepository {
+	return &seatRepository{db: db}
+}
+
+func preloadSeatMap(db *gorm.DB) *gorm.DB {
+	return db.
+		Preload("Sections", orderByPosition).
+		Preload("Sections.Seats", orderByPosition)
+}
+
+func (r *seatRepository) CreateSeatMap(seatMap *entity.SeatMap) error {
+	if err := r.db.Create(seatMap).Error; err != nil {
+		return err
+	}
+	return nil
+}
+
+func (r *seatRepository) GetSeatMapByID(id uuid.UUID) (*entity.SeatMap, error) {
+	var seatMap entity.SeatMap
+	if err := preloadSeatMap(r.db).Where("id = ?", id).First(&seatMap).Error; err != nil {
+		return nil, err
+	}
+	return &seatMap, nil
+}
+
+func (r *seatRepository) GetSeatMaps() ([]*entity.SeatMap, error) {
+	var seatMaps []*entity.SeatMap
+	if err := r.db.Order("name ASC").Find(&seatMaps).Error; err != nil {
+		return nil, err
+	}
+	return seatMaps, nil
+}
+
+func (r *seatRepository) DeleteSeatMap(id uuid.UUID) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		var sectionIDs []uuid.UUID
+		if err := tx.Model(&entity.SeatSection{}).Where("seat_map_id = ?", id).Pluck("id", &sectionIDs).Error; err != nil {
+			return err
+		}
+
+		if len(sectionIDs) > 0 {
+			if err := tx.Where("section_id IN ?", sectionIDs).Delete(&entity.Seat{}).Error; err != nil {
+				return err
+			}
+
+			if err := tx.Where("id IN ?", sectionIDs).Delete(&entity.SeatSection{}).Error; err != nil {
+				return err
+			}
+		}
+
+		return tx.Delete(&entity.SeatMap{}, id).Error
+	})
+}
+
+func (r *seatRepository) CountEventsUsingSeatMap(seatMapID uuid.UUID) (int64, error) {
+	var total int64
+	if err := r.db.Model(&entity.Event{}).Where("seat_map_id = ?", seatMapID).Count(&total).Error; err != nil {
+		return 0, err
+	}
+	return total, nil
+}
+
+// FindSeatIDs resolves whole sections and individual seats to the seats of a
+// seat map. Seats or sections outside the map are ignored.
+func (r *seatRepository) FindSeatIDs(seatMapID uuid.UUID, sectionIDs, seatIDs []uuid.UUID) ([]uuid.UUID, error) {
+	query := r.db.Model(&entity.Seat{}).
+		Joins("JOIN seat_sections ON seat_sections.id = seats.section_id").
+		Where("seat_sections.seat_map_id = ? AND seat_sections.deleted_at IS NULL", seatMapID)
+
+	switch {
+	case len(sectionIDs) > 0 && len(seatIDs) > 0:
+		query = query.Where("seats.section_id IN ? OR seats.id IN ?", sectionIDs, seatIDs)
+	case len(sectionIDs) > 0:
+		query = query.Where("seats.section_id IN ?", sectionIDs)
+	case len(seatIDs) > 0:
+		query = query.Where("seats.id IN ?", seatIDs)
+	default:
+		return nil, nil
+	}
+
+	var ids []uuid.UUID
+	if err := query.Pluck("seats.id", &ids).Error; err != nil {
+		return nil, err
+	}
+	return ids, nil
+}
+
+func (r *seatRepository) CountEventSeats(eventID uuid.UUID) (int64, error) {
+	var total int64
+	if err := r.db.Model(&entity.CategorySeat{}).Where("event_id = ?", eventID).Count(&total).Error; err != nil {
+		return 0, err
+	}
+	return total, nil
+}
+
+func (r *seatRepository) GetEventSeats(eventID uuid.UUID) ([]*entity.CategorySeat, error) {
+	var seats []*entity.CategorySeat
+	if err := r.db.Preload("Category.PriceTiers", orderByPosition).Where("event_id = ?", eventID).Find(&seats).Error; err != nil {
+		return nil, err
+	}
+
+	categories := make(map[uuid.UUID]*entity.Category)
+	for _, seat := range seats {
+		if seat.Category != nil {
+			if category, exists := categories[seat.CategoryID]; exists {
+				seat.Category = category
+			} else {
+				categories[seat.CategoryID] = seat.Category
+			}
+		}
+	}
+
+	unique := make([]*entity.Category, 0, len(categories))
+	for _, category := range categories {
+		unique = append(unique, category)
+	}
+	if err := fillSoldQuantities(r.db, unique); err != nil {
+		return nil, err
+	}
+
+	return seats, nil
+}
+
+// ReplaceCategorySeats sets the seats sold through a category. Seats already
+// held or booked cannot be taken away, and a seat belongs to at most one
+// category per event. The category switches to reserved seating while it has
+// seats and its stock follows the number of available seats.
+func (r *seatRepository) ReplaceCategorySeats(category *entity.Category, seatIDs []uuid.UUID) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		var locked entity.Category
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Where("id = ?", category.ID).First(&locked).Error; err != nil {
+			return err
+		}
+
+		wanted := make(map[uuid.UUID]bool, len(seatIDs))
+		for _, seatID := range seatIDs {
+			wanted[seatID] = true
+		}
+
+		if len(seatIDs) > 0 {
+			var taken int64
+			if err := tx.Model(&entity.CategorySeat{}).
+				Where("event_id = ? AND seat_id IN ? AND category_id <> ?", category.EventID, seatIDs, category.ID).
+				Count(&taken).Error; err != nil {
+				return err
+			}
+			if taken > 0 {
+				return ErrSeatAssignedElsewhere
+			}
+		}
+
+		var existing []*entity.CategorySeat
+		if err := tx.Where("category_id = ?", category.ID).Find(&existing).Error; err != nil {
+			return err
+		}
+
+		have := make(map[uuid.UUID]bool, len(existing))
+		var removed []uuid.UUID
+		for _, categorySeat := range existing {
+			have[categorySeat.SeatID] = true
+			if wanted[categorySeat.SeatID] {
+				continue
+			}
+			if categorySeat.Status != "available" {
+				return ErrSeatsInUse
+			}
+			removed = append(removed, categorySeat.ID)
+		}
+
+		if len(removed) > 0 {
+			if err := tx.Where("id IN ?", removed).Delete(&entity.CategorySeat{}).Error; err != nil {
+				return err
+			}
+		}
+
+		for _, seatID := range seatIDs {
+			if have[seatID] {
+				continue
+			}
+			if err := tx.Create(&entity.CategorySeat{
+				EventID:    category.EventID,
+				SeatID:     seatID,
+				CategoryID: category.ID,
+				Status:     "available",
+			}).Error; err != nil {
+				return err
+			}
+		}
+
+		var available int64
+		if err := tx.Model(&entity.CategorySeat{}).
+			Where("category_id = ? AND status = ?", category.ID, "available").
+			Count(&available).Error; err != nil {
+			return err
+		}
+
+		seatingMode := "reserved"
+		if len(seatIDs) == 0 {
+			seatingMode = "general"
+		}
+
+		updates := map[string]interface{}{"seating_mode": seatingMode}
+		if seatingMode == "reserved" {
+			quantity := int(available) - locked.WaitlistHeld
+			if quantity < 0 {
+				quantity = 0
+			}
+			status := "available"
+			if quantity == 0 {
+				status = "sold"
+			}
+			updates["quantity"] = quantity
+			updates["status"] = status
+			category.Quantity = quantity
+			category.Status = status
+		}
+		category.SeatingMode = seatingMode
+
+		return tx.Model(&entity.Category{}).Where("id = ?", category.ID).Updates(updates).Error
+	})
+}
+
+// GetLapsedHoldOrderIDs lists pending orders that have held seats since
+// before the given time.
+func (r *seatRepository) GetLapsedHoldOrderIDs(heldBefore time.Time) ([]uuid.UUID, error) {
+	var orderIDs []uuid.UUID
+	if err := r.db.Model(&entity.CategorySeat{}).
+		Joins("JOIN orders ON orders.id = category_seats.order_id").
+		Where("category_seats.status = ? AND orders.status = ? AND orders.created_at < ?", "held", "pending", heldBefore).
+		Distinct().
+		Pluck("category_seats.order_id", &orderIDs).Error; err != nil {
+		return nil, err
+	}
+	return orderIDs, nil
+}
+
+// holdSeats marks the chosen seats of a category as held by an order. Rows are
+// locked in a fixed order so concurrent orders for overlapping seats cannot
+// deadlock, and any seat that is not available fails the whole order.
+func holdSeats(tx *gorm.DB, orderID, categoryID uuid.UUID, seatIDs []uuid.UUID) error {
+	var seats []*entity.CategorySeat
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("category_id = ? AND seat_id IN ?", categoryID, seatIDs).
+		Order("seat_id").
+		Find(&seats).Error; err != nil {
+		return err
+	}
+
+	if len(seats) != len(seatIDs) {
+		return ErrSeatUnavailable
+	}
+
+	for _, seat := range seats {
+		if seat.Status != "available" {
+			return ErrSeatUnavailable
+		}
+	}
+
+	return tx.Model(&entity.CategorySeat{}).
+		Where("category_id = ? AND seat_id IN ?", categoryID, seatIDs).
+		Updates(map[string]interface{}{"status": "held", "order_id": orderID}).Error
+}
+
+// releaseOrderSeats puts the seats of an order back on sale.
+func releaseOrderSeats(tx *gorm.DB, orderID uuid.UUID) error {
+	return tx.Model(&entity.CategorySeat{}).
+		Where("order_id = ?", orderID).
+		Updates(map[string]interface{}{"status": "available", "order_id": nil}).Error
+}
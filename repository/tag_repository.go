@@ -0,0 +1,163 @@
+package repository
+
+import (
+	"ticert/entity"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type TagRepository interface {
+	CreateTag(tag *entity.Tag) error
+	GetTagByID(id uuid.UUID) (*entity.Tag, error)
+	GetTagBySlug(slug string) (*entity.Tag, error)
+	GetTags(search string) ([]*entity.Tag, error)
+	GetTagsByIDs(ids []uuid.UUID) ([]*entity.Tag, error)
+	UpdateTag(tag *entity.Tag) error
+	DeleteTag(id uuid.UUID) error
+	CountChildren(id uuid.UUID) (int64, error)
+	GetTagEventCounts() (map[uuid.UUID]int64, error)
+	SetEventTags(eventID uuid.UUID, tagIDs []uuid.UUID) error
+}
+
+type tagRepository struct {
+	db *gorm.DB
+}
+
+func NewTagRepository(db *gorm.DB) TagRepository {
+	return &tagRepository{db: db}
+}
+
+func (r *tagRepository) CreateTag(tag *entity.Tag) error {
+	if err := r.db.Omit("Parent").Create(tag).Error; err != nil {
+		return err
+	}
+	return nil
+}
+
+func (r *tagRepository) GetTagByID(id uuid.UUID) (*entity.Tag, error) {
+	var tag entity.Tag
+	if err := r.db.Where("id = ?", id).First(&tag).Error; err != nil {
+		return nil, err
+	}
+	return &tag, nil
+}
+
+func (r *tagRepository) GetTagBySlug(slug string) (*entity.Tag, error) {
+	var tag entity.Tag
+	if err := r.db.Where("slug = ?", slug).First(&tag).Error; err != nil {
+		return nil, err
+	}
+	return &tag, nil
+}
+
+// GetTags lists tags by name. A search matches the tag's name or slug.
+func (r *tagRepository) GetTags(search string) ([]*entity.Tag, error) {
+	var tags []*entity.Tag
+
+	query := r.db.Model(&entity.Tag{})
+	if search != "" {
+		query = query.Where("name LIKE ? OR slug LIKE ?", "%"+search+"%", "%"+search+"%")
+	}
+
+	if err := query.Order("name ASC").Find(&tags).Error; err != nil {
+		return nil, err
+	}
+	return tags, nil
+}
+
+func (r *tagRepository) GetTagsByIDs(ids []uuid.UUID) ([]*entity.Tag, error) {
+	var tags []*entity.Tag
+	if len(ids) == 0 {
+		return tags, nil
+	}
+	if err := r.db.Where("id IN ?", ids).Find(&tags).Error; err != nil {
+		return nil, err
+	}
+	return tags, nil
+}
+
+func (r *tagRepository) UpdateTag(tag *entity.Tag) error {
+	if err := r.db.Model(&entity.Tag{}).
+		Where("id = ?", tag.ID).
+		Select("name", "slug", "parent_id").
+		Updates(tag).Error; err != nil {
+		return err
+	}
+	return nil
+}
+
+// DeleteTag removes the tag from every event and deletes it.
+func (r *tagRepository) DeleteTag(id uuid.UUID) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Exec("DELETE FROM event_tags WHERE tag_id = ?", id).Error; err != nil {
+			return err
+		}
+		return tx.Delete(&entity.Tag{}, "id = ?", id).Error
+	})
+}
+
+func (r *tagRepository) CountChildren(id uuid.UUID) (int64, error) {
+	var count int64
+	if err := r.db.Model(&entity.Tag{}).Where("parent_id = ?", id).Count(&count).Error; err != nil {
+		return 0, err
+	}
+	return count, nil
+}
+
+// GetTagEventCounts counts the published events under each tag. A top-level
+// tag counts the events tagged with it or any of its children once each.
+func (r *tagRepository) GetTagEventCounts() (map[uuid.UUID]int64, error) {
+	type tagCount struct {
+		TagID uuid.UUID
+		Count int64
+	}
+
+	published := func(db *gorm.DB) *gorm.DB {
+		return db.Table("event_tags et").
+			Joins("JOIN events e ON e.id = et.event_id AND e.deleted_at IS NULL AND e.status = ?", "published").
+			Joins("JOIN tags t ON t.id = et.tag_id")
+	}
+
+	var childCounts []tagCount
+	if err := r.db.Scopes(published).
+		Select("t.id AS tag_id, COUNT(DISTINCT et.event_id) AS count").
+		Where("t.parent_id IS NOT NULL").
+		Group("t.id").
+		Scan(&childCounts).Error; err != nil {
+		return nil, err
+	}
+
+	var rootCounts []tagCount
+	if err := r.db.Scopes(published).
+		Select("COALESCE(t.parent_id, t.id) AS tag_id, COUNT(DISTINCT et.event_id) AS count").
+		Group("COALESCE(t.parent_id, t.id)").
+		Scan(&rootCounts).Error; err != nil {
+		return nil, err
+	}
+
+	counts := make(map[uuid.UUID]int64, len(childCounts)+len(rootCounts))
+	for _, count := range append(childCounts, rootCounts...) {
+		counts[count.TagID] = count.Count
+	}
+	return counts, nil
+}
+
+// SetEventTags replaces the tags of an event.
+func (r *tagRepository) SetEventTags(eventID uuid.UUID, tagIDs []uuid.UUID) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Exec("DELETE FROM event_tags WHERE event_id = ?", eventID).Error; err != nil {
+			return err
+		}
+
+		if len(tagIDs) == 0 {
+			return nil
+		}
+
+		rows := make([]map[string]interface{}, 0, len(tagIDs))
+		for _, tagID := range tagIDs {
+			rows = append(rows, map[string]interface{}{"event_id": eventID, "tag_id": tagID})
+		}
+		return tx.Table("event_tags").Create(rows).Error
+	})
+}
@@ -0,0 +1,86 @@
+package repository
+
+import (
+	"ticert/entity"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type VenueRepository interface {
+	CreateVenue(venue *entity.Venue) error
+	GetVenueByID(id uuid.UUID) (*entity.Venue, error)
+	GetVenues(page, limit int, search string) ([]*entity.Venue, int64, error)
+	UpdateVenue(venue *entity.Venue) error
+	DeleteVenue(id uuid.UUID) error
+	GetEventIDsByVenue(venueID uuid.UUID) ([]uuid.UUID, error)
+}
+
+type venueRepository struct {
+	db *gorm.DB
+}
+
+func NewVenueRepository(db *gorm.DB) VenueRepository {
+	return &venueRepository{db: db}
+}
+
+func (r *venueRepository) CreateVenue(venue *entity.Venue) error {
+	if err := r.db.Create(venue).Error; err != nil {
+		return err
+	}
+	return nil
+}
+
+func (r *venueRepository) GetVenueByID(id uuid.UUID) (*entity.Venue, error) {
+	var venue entity.Venue
+	if err := r.db.Where("id = ?", id).First(&venue).Error; err != nil {
+		return nil, err
+	}
+	return &venue, nil
+}
+
+func (r *venueRepository) GetVenues(page, limit int, search string) ([]*entity.Venue, int64, error) {
+	var venues []*entity.Venue
+	var total int64
+
+	query := r.db.Model(&entity.Venue{})
+
+	if search != "" {
+		query = query.Where("name LIKE ? OR address LIKE ?", "%"+search+"%", "%"+search+"%")
+	}
+
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	if err := query.Order("name ASC").Offset((page - 1) * limit).Limit(limit).Find(&venues).Error; err != nil {
+		return nil, 0, err
+	}
+
+	return venues, total, nil
+}
+
+func (r *venueRepository) UpdateVenue(venue *entity.Venue) error {
+	if err := r.db.Model(&entity.Venue{}).
+		Where("id = ?", venue.ID).
+		Select("name", "address", "latitude", "longitude", "timezone", "capacity").
+		Updates(venue).Error; err != nil {
+		return err
+	}
+	return nil
+}
+
+func (r *venueRepository) DeleteVenue(id uuid.UUID) error {
+	if err := r.db.Delete(&entity.Venue{}, id).Error; err != nil {
+		return err
+	}
+	return nil
+}
+
+func (r *venueRepository) GetEventIDsByVenue(venueID uuid.UUID) ([]uuid.UUID, error) {
+	var eventIDs []uuid.UUID
+	if err := r.db.Model(&entity.Event{}).Where("venue_id = ?", venueID).Pluck("id", &eventIDs).Error; err != nil {
+		return nil, err
+	}
+	return eventIDs, nil
+}
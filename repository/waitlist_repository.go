@@ -0,0 +1,190 @@
+package repository
+
+import (
+	"errors"
+	"ticert/entity"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+var ErrWaitlistClaimExpired = errors.New("waitlist claim expired")
+
+// WaitlistRepository stores waitlist entries for sold-out categories. Stock
+// that comes back while people are waiting is moved into the category's
+// waitlist_held pool and assigned to entries in order of joining; each
+// assigned entry has a claim window before its units go back on general sale.
+type WaitlistRepository interface {
+	CreateEntry(entry *entity.WaitlistEntry) error
+	GetActiveEntry(categoryID, userID uuid.UUID) (*entity.WaitlistEntry, error)
+	CountAhead(entry *entity.WaitlistEntry) (int64, error)
+	CancelEntry(id uuid.UUID) error
+	SumWaitingQuantity(categoryID uuid.UUID) (int, error)
+	ExpireClaims(now time.Time) error
+	GetCategoryIDsToAllocate() ([]uuid.UUID, error)
+	AllocateClaims(categoryID uuid.UUID, extraStock int, claimExpiresAt time.Time) ([]*entity.WaitlistEntry, int, error)
+}
+
+type waitlistRepository struct {
+	db *gorm.DB
+}
+
+func NewWaitlistRepository(db *gorm.DB) WaitlistRepository {
+	return &waitlistRepository{db: db}
+}
+
+func (r *waitlistRepository) CreateEntry(entry *entity.WaitlistEntry) error {
+	if err := r.db.Create(entry).Error; err != nil {
+		return err
+	}
+	return nil
+}
+
+// GetActiveEntry returns the user's waiting or notified entry for a category.
+func (r *waitlistRepository) GetActiveEntry(categoryID, userID uuid.UUID) (*entity.WaitlistEntry, error) {
+	var entry entity.WaitlistEntry
+	if err := r.db.Where("category_id = ? AND user_id = ? AND status IN ?", categoryID, userID, []string{"waiting", "notified"}).
+		First(&entry).Error; err != nil {
+		return nil, err
+	}
+	return &entry, nil
+}
+
+// CountAhead counts the waiting entries that joined before the given one.
+func (r *waitlistRepository) CountAhead(entry *entity.WaitlistEntry) (int64, error) {
+	var total int64
+	if err := r.db.Model(&entity.WaitlistEntry{}).
+		Where("category_id = ? AND status = ? AND created_at < ?", entry.CategoryID, "waiting", entry.CreatedAt).
+		Count(&total).Error; err != nil {
+		return 0, err
+	}
+	return total, nil
+}
+
+func (r *waitlistRepository) CancelEntry(id uuid.UUID) error {
+	if err := r.db.Model(&entity.WaitlistEntry{}).Where("id = ?", id).Update("status", "cancelled").Error; err != nil {
+		return err
+	}
+	return nil
+}
+
+func (r *waitlistRepository) SumWaitingQuantity(categoryID uuid.UUID) (int, error) {
+	var total int
+	if err := r.db.Model(&entity.WaitlistEntry{}).
+		Select("COALESCE(SUM(quantity), 0)").
+		Where("category_id = ? AND status = ?", categoryID, "waiting").
+		Scan(&total).Error; err != nil {
+		return 0, err
+	}
+	return total, nil
+}
+
+// ExpireClaims marks notified entries whose claim window has passed as
+// expired. Their units stay in the held pool until the category is allocated
+// again.
+func (r *waitlistRepository) ExpireClaims(now time.Time) error {
+	if err := r.db.Model(&entity.WaitlistEntry{}).
+		Where("status = ? AND claim_expires_at < ?", "notified", now).
+		Update("status", "expired").Error; err != nil {
+		return err
+	}
+	return nil
+}
+
+// GetCategoryIDsToAllocate lists categories with people waiting or with held
+// units that are no longer assigned to a claim.
+func (r *waitlistRepository) GetCategoryIDsToAllocate() ([]uuid.UUID, error) {
+	var categoryIDs []uuid.UUID
+	if err := r.db.Model(&entity.Category{}).
+		Where("waitlist_held > 0 OR EXISTS (SELECT 1 FROM waitlist_entries WHERE waitlist_entries.category_id = categories.id AND waitlist_entries.status = ? AND waitlist_entries.deleted_at IS NULL)", "waiting").
+		Pluck("id", &categoryIDs).Error; err != nil {
+		return nil, err
+	}
+	return categoryIDs, nil
+}
+
+// AllocateClaims assigns free units to waiting entries in order of joining,
+// skipping entries that ask for more than is left. Free units are the held
+// units not assigned to a claim, extraStock taken from Redis by the caller
+// and, for database-strategy categories, the general stock. Units not assigned
+// are put back on general sale: for database-strategy categories directly,
+// for redis-strategy categories they are returned as leftover for the caller
+// to give back to Redis.
+func (r *waitlistRepository) AllocateClaims(categoryID uuid.UUID, extraStock int, claimExpiresAt time.Time) ([]*entity.WaitlistEntry, int, error) {
+	var notified []*entity.WaitlistEntry
+	leftover := 0
+
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		var category entity.Category
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Where("id = ?", categoryID).First(&category).Error; err != nil {
+			return err
+		}
+
+		var assigned int
+		if err := tx.Model(&entity.WaitlistEntry{}).
+			Select("COALESCE(SUM(quantity), 0)").
+			Where("category_id = ? AND status = ?", categoryID, "notified").
+			Scan(&assigned).Error; err != nil {
+			return err
+		}
+
+		pool := category.WaitlistHeld - assigned + extraStock
+		if category.InventoryStrategy != "redis" {
+			pool += category.Quantity
+		}
+
+		var waiting []*entity.WaitlistEntry
+		if err := tx.Preload("User").
+			Where("category_id = ? AND status = ?", categoryID, "waiting").
+			Order("created_at ASC, id ASC").
+			Find(&waiting).Error; err != nil {
+			return err
+		}
+
+		now := time.Now()
+		for _, entry := range waiting {
+			if entry.Quantity > pool {
+				continue
+			}
+
+			if err := tx.Model(&entity.WaitlistEntry{}).
+				Where("id = ?", entry.ID).
+				Updates(map[string]interface{}{
+					"status":           "notified",
+					"notified_at":      now,
+					"claim_expires_at": claimExpiresAt,
+				}).Error; err != nil {
+				return err
+			}
+
+			entry.Status = "notified"
+			entry.NotifiedAt = &now
+			entry.ClaimExpiresAt = &claimExpiresAt
+			notified = append(notified, entry)
+
+			pool -= entry.Quantity
+			assigned += entry.Quantity
+		}
+
+		updates := map[string]interface{}{"waitlist_held": assigned}
+		if category.InventoryStrategy == "redis" {
+			leftover = pool
+		} else {
+			status := "available"
+			if pool <= 0 {
+				status = "sold"
+			}
+			updates["quantity"] = pool
+			updates["status"] = status
+		}
+
+		return tx.Model(&entity.Category{}).Where("id = ?", categoryID).Updates(updates).Error
+	})
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return notified, leftover, nil
+}
@@ -0,0 +1,23 @@
+package routes
+
+import (
+	"ticert/controller"
+	"ticert/middleware"
+
+	"github.com/gin-gonic/gin"
+)
+
+func SetupAnalyticsRoutes(r *gin.Engine, analyticsController *controller.AnalyticsController) {
+	protected := r.Group("/api/v1/analytics")
+	protected.Use(middleware.AuthMiddleware())
+	protected.Use(middleware.RoleMiddleware("admin"))
+
+	{
+		protected.GET("/sales", analyticsController.GetSalesSeries)
+		protected.GET("/funnel", analyticsController.GetFunnelReport)
+		protected.GET("/customers", analyticsController.GetTopCustomers)
+		protected.GET("/customers/repeat", analyticsController.GetRepeatPurchases)
+		protected.GET("/customers/events", analyticsController.GetEventBuyers)
+		protected.GET("/customers/:id", analyticsController.GetCustomerSummary)
+	}
+}
@@ -0,0 +1,19 @@
+package routes
+
+import (
+	"ticert/controller"
+
+	"github.com/gin-gonic/gin"
+)
+
+// SetupCatalogueRoutes registers the public, read-only catalogue. These
+// routes need no authentication and only expose published events.
+func SetupCatalogueRoutes(r *gin.Engine, catalogueController *controller.CatalogueController) {
+	public := r.Group("/api/v1/public")
+
+	{
+		public.GET("/events", catalogueController.GetEvents)
+		public.GET("/events/:id", catalogueController.GetEvent)
+		public.GET("/categories/:id", catalogueController.GetCategory)
+	}
+}
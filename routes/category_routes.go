@@ -17,5 +17,7 @@ func SetupCategoryRoutes(r *gin.Engine, categoryController *controller.CategoryC
 		protected.GET("/event/:event_id", categoryController.GetCategories)
 		protected.PATCH("/:id", middleware.RoleMiddleware("admin"), categoryController.UpdateCategory)
 		protected.DELETE("/:id", middleware.RoleMiddleware("admin"), categoryController.DeleteCategory)
+		protected.GET("/:id/price-tiers", categoryController.GetPriceTiers)
+		protected.PUT("/:id/price-tiers", middleware.RoleMiddleware("admin"), categoryController.UpdatePriceTiers)
 	}
 }
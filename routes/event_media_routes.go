@@ -0,0 +1,27 @@
+package routes
+
+import (
+	"ticert/config"
+	"ticert/controller"
+	"ticert/middleware"
+
+	"github.com/gin-gonic/gin"
+)
+
+func SetupEventMediaRoutes(r *gin.Engine, eventMediaController *controller.EventMediaController) {
+	protected := r.Group("/api/v1/events")
+	protected.Use(middleware.AuthMiddleware(), middleware.RoleMiddleware("admin"))
+
+	{
+		protected.PUT("/:id/poster", eventMediaController.UploadPoster)
+		protected.POST("/:id/images", eventMediaController.UploadGalleryImage)
+		protected.DELETE("/:id/images/:imageId", eventMediaController.DeleteImage)
+	}
+
+	// Files in local storage are served by the application itself; S3
+	// storage serves them from the bucket.
+	cfg := config.GetConfig()
+	if cfg.StorageDriver == "local" {
+		r.Static(config.LocalMediaPath, cfg.StorageLocalDir)
+	}
+}
@@ -16,6 +16,8 @@ func SetupEventRoutes(r *gin.Engine, eventController *controller.EventController
 		protected.GET("/:id", eventController.GetEventByID)
 		protected.GET("/", eventController.GetEvents)
 		protected.PATCH("/:id", middleware.RoleMiddleware("admin"), eventController.UpdateEvent)
+		protected.PATCH("/:id/cancel", middleware.RoleMiddleware("admin"), eventController.CancelEvent)
+		protected.POST("/:id/clone", middleware.RoleMiddleware("admin"), eventController.CloneEvent)
 		protected.DELETE("/:id", middleware.RoleMiddleware("admin"), eventController.DeleteEvent)
 	}
 }
@@ -0,0 +1,18 @@
+package routes
+
+import (
+	"ticert/controller"
+	"ticert/middleware"
+
+	"github.com/gin-gonic/gin"
+)
+
+func SetupEventSeriesRoutes(r *gin.Engine, eventSeriesController *controller.EventSeriesController) {
+	protected := r.Group("/api/v1/event-series")
+	protected.Use(middleware.AuthMiddleware())
+
+	{
+		protected.POST("/", middleware.RoleMiddleware("admin"), eventSeriesController.CreateSeries)
+		protected.GET("/:id", eventSeriesController.GetSeries)
+	}
+}
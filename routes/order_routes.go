@@ -18,6 +18,7 @@ func SetupOrderRoutes(r *gin.Engine, orderController *controller.OrderController
 		protected.PATCH("/:id/cancel", orderController.CancelOrder)
 		protected.GET("/admin", middleware.RoleMiddleware("admin"), orderController.GetOrdersAdmin)
 		protected.PATCH("/:id/verify", middleware.RoleMiddleware("admin"), orderController.VerifyOrderStatus)
+		protected.PATCH("/:id/refund", middleware.RoleMiddleware("admin"), orderController.RefundOrder)
 		protected.PATCH("/redeem/:ticket_code", middleware.RoleMiddleware("admin"), orderController.VerifyTicket)
 	}
 }
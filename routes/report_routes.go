@@ -15,5 +15,8 @@ func SetupReportRoutes(r *gin.Engine, reportController *controller.ReportControl
 	{
 		protected.GET("/summary", reportController.GenerateSummaryReport)
 		protected.GET("/", reportController.GetReportList)
+		protected.GET("/attendance", reportController.GetAttendanceReport)
+		protected.GET("/attendance/:id/check-ins", reportController.GetCheckInTimeline)
+		protected.GET("/attendance/:id/arrivals", reportController.GetArrivalReport)
 	}
 }
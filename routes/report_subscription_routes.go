@@ -0,0 +1,23 @@
+package routes
+
+import (
+	"ticert/controller"
+	"ticert/middleware"
+
+	"github.com/gin-gonic/gin"
+)
+
+func SetupReportSubscriptionRoutes(r *gin.Engine, reportSubscriptionController *controller.ReportSubscriptionController) {
+	protected := r.Group("/api/v1/report-subscriptions")
+	protected.Use(middleware.AuthMiddleware())
+	protected.Use(middleware.RoleMiddleware("admin"))
+
+	{
+		protected.POST("", reportSubscriptionController.CreateSubscription)
+		protected.GET("", reportSubscriptionController.GetSubscriptions)
+		protected.GET("/:id", reportSubscriptionController.GetSubscriptionByID)
+		protected.PATCH("/:id", reportSubscriptionController.UpdateSubscription)
+		protected.DELETE("/:id", reportSubscriptionController.DeleteSubscription)
+		protected.GET("/:id/runs", reportSubscriptionController.GetRuns)
+	}
+}
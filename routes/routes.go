@@ -1,6 +1,8 @@
 package routes
 
 import (
+	"context"
+	"ticert/config"
 	"ticert/controller"
 	"ticert/repository"
 	"ticert/service"
@@ -18,18 +20,45 @@ func SetupRoutes(db *gorm.DB, r *gin.Engine) {
 	categoryRepo := repository.NewCategoryRepository(db)
 	orderRepo := repository.NewOrderRepository(db)
 	reportRepo := repository.NewReportRepository(db)
+	inventoryRepo := repository.NewInventoryRepository()
+	waitlistRepo := repository.NewWaitlistRepository(db)
+	seatRepo := repository.NewSeatRepository(db)
+	venueRepo := repository.NewVenueRepository(db)
+	cacheRepo := repository.NewCacheRepository()
+	tagRepo := repository.NewTagRepository(db)
+	analyticsRepo := repository.NewAnalyticsRepository(db)
+	reportSubscriptionRepo := repository.NewReportSubscriptionRepository(db)
 
 	userService := service.NewUserService(userRepo, authRepo)
-	eventService := service.NewEventService(eventRepo)
-	categoryService := service.NewCategoryService(categoryRepo, eventRepo)
-	orderService := service.NewOrderService(orderRepo, userRepo, categoryRepo)
-	reportService := service.NewReportService(reportRepo, eventRepo, categoryRepo)
+	inventoryService := service.NewInventoryService(inventoryRepo, categoryRepo, orderRepo)
+	waitlistService := service.NewWaitlistService(waitlistRepo, categoryRepo, inventoryService, service.NewLogNotifier())
+	catalogueService := service.NewCatalogueService(eventRepo, categoryRepo, cacheRepo)
+	eventService := service.NewEventService(eventRepo, seatRepo, venueRepo, categoryRepo, inventoryService, catalogueService)
+	categoryService := service.NewCategoryService(categoryRepo, eventRepo, inventoryService, waitlistService, catalogueService)
+	orderService := service.NewOrderService(orderRepo, userRepo, categoryRepo, inventoryService, waitlistService)
+	reportService := service.NewReportService(reportRepo, eventRepo)
+	seatService := service.NewSeatService(seatRepo, eventRepo, categoryRepo, orderRepo, venueRepo)
+	venueService := service.NewVenueService(venueRepo, categoryRepo)
+	eventSeriesService := service.NewEventSeriesService(eventRepo, inventoryService, catalogueService)
+	eventMediaService := service.NewEventMediaService(eventRepo, config.GetStorage(), catalogueService)
+	tagService := service.NewTagService(tagRepo, eventRepo, catalogueService)
+	analyticsService := service.NewAnalyticsService(analyticsRepo, eventRepo, categoryRepo, userRepo)
+	reportSubscriptionService := service.NewReportSubscriptionService(reportSubscriptionRepo, eventRepo, reportService, config.GetMailer())
 
 	userController := controller.NewUserController(userService)
 	eventController := controller.NewEventController(eventService)
 	categoryController := controller.NewCategoryController(categoryService)
 	orderController := controller.NewOrderController(orderService)
 	reportController := controller.NewReportController(reportService)
+	waitlistController := controller.NewWaitlistController(waitlistService)
+	seatController := controller.NewSeatController(seatService)
+	venueController := controller.NewVenueController(venueService)
+	eventSeriesController := controller.NewEventSeriesController(eventSeriesService)
+	catalogueController := controller.NewCatalogueController(catalogueService)
+	eventMediaController := controller.NewEventMediaController(eventMediaService)
+	tagController := controller.NewTagController(tagService)
+	analyticsController := controller.NewAnalyticsController(analyticsService)
+	reportSubscriptionController := controller.NewReportSubscriptionController(reportSubscriptionService)
 
 	SetupAuthRoutes(r, userController)
 	SetupUserRoutes(r, userController)
@@ -37,4 +66,18 @@ func SetupRoutes(db *gorm.DB, r *gin.Engine) {
 	SetupCategoryRoutes(r, categoryController)
 	SetupOrderRoutes(r, orderController)
 	SetupReportRoutes(r, reportController)
+	SetupWaitlistRoutes(r, waitlistController)
+	SetupSeatRoutes(r, seatController)
+	SetupVenueRoutes(r, venueController)
+	SetupEventSeriesRoutes(r, eventSeriesController)
+	SetupCatalogueRoutes(r, catalogueController)
+	SetupEventMediaRoutes(r, eventMediaController)
+	SetupTagRoutes(r, tagController)
+	SetupAnalyticsRoutes(r, analyticsController)
+	SetupReportSubscriptionRoutes(r, reportSubscriptionController)
+
+	go inventoryService.StartReconciler(context.Background())
+	go waitlistService.StartSweeper(context.Background())
+	go seatService.StartSweeper(context.Background())
+	go reportSubscriptionService.StartScheduler(context.Background())
 }
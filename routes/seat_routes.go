@@ -0,0 +1,28 @@
+package routes
+
+import (
+	"ticert/controller"
+	"ticert/middleware"
+
+	"github.com/gin-gonic/gin"
+)
+
+func SetupSeatRoutes(r *gin.Engine, seatController *controller.SeatController) {
+	seatMaps := r.Group("/api/v1/seat-maps")
+	seatMaps.Use(middleware.AuthMiddleware(), middleware.RoleMiddleware("admin"))
+
+	{
+		seatMaps.POST("/", seatController.CreateSeatMap)
+		seatMaps.GET("/", seatController.GetSeatMaps)
+		seatMaps.GET("/:id", seatController.GetSeatMapByID)
+		seatMaps.DELETE("/:id", seatController.DeleteSeatMap)
+	}
+
+	protected := r.Group("/api/v1")
+	protected.Use(middleware.AuthMiddleware())
+
+	{
+		protected.GET("/events/:id/seats", seatController.GetEventSeats)
+		protected.PUT("/categories/:id/seats", middleware.RoleMiddleware("admin"), seatController.AssignCategorySeats)
+	}
+}
@@ -0,0 +1,31 @@
+package routes
+
+import (
+	"ticert/controller"
+	"ticert/middleware"
+
+	"github.com/gin-gonic/gin"
+)
+
+func SetupTagRoutes(r *gin.Engine, tagController *controller.TagController) {
+	protected := r.Group("/api/v1/tags")
+	protected.Use(middleware.AuthMiddleware())
+
+	{
+		protected.POST("/", middleware.RoleMiddleware("admin"), tagController.CreateTag)
+		protected.GET("/", tagController.GetTags)
+		protected.PATCH("/:id", middleware.RoleMiddleware("admin"), tagController.UpdateTag)
+		protected.DELETE("/:id", middleware.RoleMiddleware("admin"), tagController.DeleteTag)
+	}
+
+	// The tag tree only counts published events, so browsing apps can read
+	// it without signing in.
+	r.GET("/api/v1/public/tags", tagController.GetTags)
+
+	events := r.Group("/api/v1/events")
+	events.Use(middleware.AuthMiddleware(), middleware.RoleMiddleware("admin"))
+
+	{
+		events.PUT("/:id/tags", tagController.SetEventTags)
+	}
+}
@@ -0,0 +1,21 @@
+package routes
+
+import (
+	"ticert/controller"
+	"ticert/middleware"
+
+	"github.com/gin-gonic/gin"
+)
+
+func SetupVenueRoutes(r *gin.Engine, venueController *controller.VenueController) {
+	protected := r.Group("/api/v1/venues")
+	protected.Use(middleware.AuthMiddleware())
+
+	{
+		protected.POST("/", middleware.RoleMiddleware("admin"), venueController.CreateVenue)
+		protected.GET("/", venueController.GetVenues)
+		protected.GET("/:id", venueController.GetVenueByID)
+		protected.PATCH("/:id", middleware.RoleMiddleware("admin"), venueController.UpdateVenue)
+		protected.DELETE("/:id", middleware.RoleMiddleware("admin"), venueController.DeleteVenue)
+	}
+}
@@ -0,0 +1,19 @@
+package routes
+
+import (
+	"ticert/controller"
+	"ticert/middleware"
+
+	"github.com/gin-gonic/gin"
+)
+
+func SetupWaitlistRoutes(r *gin.Engine, waitlistController *controller.WaitlistController) {
+	protected := r.Group("/api/v1/categories")
+	protected.Use(middleware.AuthMiddleware())
+
+	{
+		protected.POST("/:id/waitlist", waitlistController.JoinWaitlist)
+		protected.GET("/:id/waitlist", waitlistController.GetWaitlistEntry)
+		protected.DELETE("/:id/waitlist", waitlistController.LeaveWaitlist)
+	}
+}
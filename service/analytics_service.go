@@ -0,0 +1,569 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"ticert/dto/request"
+	"ticert/dto/response"
+	"ticert/models"
+	"ticert/repository"
+	"ticert/utils/errs"
+	utils_response "ticert/utils/response"
+	"ticert/utils/validator"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// maxSeriesBuckets bounds the length of a time series.
+const maxSeriesBuckets = 1000
+
+const bucketLayout = "2006-01-02 15:04:05"
+
+type AnalyticsService interface {
+	GetSalesSeries(ctx context.Context, req *request.SalesSeriesRequest) (*response.SalesSeriesResponse, map[string]string, error)
+	GetFunnelReport(ctx context.Context, req *request.FunnelReportRequest) (*response.FunnelReportResponse, map[string]string, error)
+	GetTopCustomers(ctx context.Context, req *request.TopCustomersRequest) (*response.TopCustomersResponse, map[string]string, error)
+	GetRepeatPurchases(ctx context.Context, req *request.RepeatPurchaseRequest) (*response.RepeatPurchaseResponse, map[string]string, error)
+	GetEventBuyers(ctx context.Context, req *request.EventBuyersRequest) (*response.EventBuyersListResponse, map[string]string, error)
+	GetCustomerSummary(ctx context.Context, userID uuid.UUID) (*response.CustomerSummaryResponse, error)
+}
+
+type analyticsService struct {
+	analyticsRepo repository.AnalyticsRepository
+	eventRepo     repository.EventRepository
+	categoryRepo  repository.CategoryRepository
+	userRepo      repository.UserRepository
+}
+
+func NewAnalyticsService(analyticsRepo repository.AnalyticsRepository, eventRepo repository.EventRepository, categoryRepo repository.CategoryRepository, userRepo repository.UserRepository) AnalyticsService {
+	return &analyticsService{analyticsRepo: analyticsRepo, eventRepo: eventRepo, categoryRepo: categoryRepo, userRepo: userRepo}
+}
+
+// GetSalesSeries returns sales figures bucketed by the creation time of the
+// orders, over whole days from start_date to end_date in the requested time
+// zone. Buckets without orders are filled with zeros.
+func (s *analyticsService) GetSalesSeries(ctx context.Context, req *request.SalesSeriesRequest) (*response.SalesSeriesResponse, map[string]string, error) {
+	if req.Interval == "" {
+		req.Interval = "day"
+	}
+	if req.Timezone == "" {
+		req.Timezone = "UTC"
+	}
+
+	validationErrors := validator.HandleValidationErrors(req)
+	if validationErrors != nil {
+		return nil, validationErrors, nil
+	}
+
+	from, to, validationErrors := parseDayRange(req.StartDate, req.EndDate, req.Timezone)
+	if validationErrors != nil {
+		return nil, validationErrors, nil
+	}
+	loc := from.Location()
+
+	filter := repository.SalesSeriesFilter{
+		Interval: req.Interval,
+		From:     from,
+		To:       to,
+		Location: loc,
+	}
+
+	eventID, err := s.getEventID(req.EventID)
+	if err != nil {
+		return nil, nil, err
+	}
+	filter.EventID = eventID
+
+	if req.CategoryID != "" {
+		categoryID := uuid.MustParse(req.CategoryID)
+		if _, err := s.categoryRepo.GetCategoryByID(categoryID); err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return nil, nil, errs.ErrCategoryNotFound
+			}
+			return nil, nil, errs.ErrInternalServerError
+		}
+		filter.CategoryID = &categoryID
+	}
+
+	starts := bucketStarts(req.Interval, filter.From, filter.To)
+	if len(starts) > maxSeriesBuckets {
+		return nil, map[string]string{"interval": fmt.Sprintf("The range has more than %d buckets; use a larger interval or a shorter range", maxSeriesBuckets)}, nil
+	}
+
+	rows, err := s.analyticsRepo.GetSalesSeries(filter)
+	if err != nil {
+		return nil, nil, errs.ErrInternalServerError
+	}
+
+	figures := make(map[string]response.SalesFiguresResponse, len(rows))
+	for _, row := range rows {
+		figures[row.Bucket] = response.SalesFiguresResponse{
+			TicketsSold:   row.TicketsSold,
+			Revenue:       row.Revenue,
+			OrdersCreated: row.OrdersCreated,
+			Cancellations: row.Cancellations,
+			Redemptions:   row.Redemptions,
+		}
+	}
+
+	totals := &response.SalesFiguresResponse{}
+	buckets := make([]*response.SalesBucketResponse, 0, len(starts))
+	for _, start := range starts {
+		bucket := figures[start.Format(bucketLayout)]
+
+		totals.TicketsSold += bucket.TicketsSold
+		totals.Revenue += bucket.Revenue
+		totals.OrdersCreated += bucket.OrdersCreated
+		totals.Cancellations += bucket.Cancellations
+		totals.Redemptions += bucket.Redemptions
+
+		buckets = append(buckets, &response.SalesBucketResponse{Start: start, SalesFiguresResponse: bucket})
+	}
+
+	return &response.SalesSeriesResponse{
+		Interval:  req.Interval,
+		Timezone:  loc.String(),
+		StartDate: req.StartDate,
+		EndDate:   req.EndDate,
+		Totals:    totals,
+		Buckets:   buckets,
+	}, nil, nil
+}
+
+// GetFunnelReport follows the orders created from start_date to end_date in
+// the requested time zone to their current status: how many were paid, how
+// long paying took, and how many were cancelled or left to expire and what
+// they were worth.
+func (s *analyticsService) GetFunnelReport(ctx context.Context, req *request.FunnelReportRequest) (*response.FunnelReportResponse, map[string]string, error) {
+	if req.Timezone == "" {
+		req.Timezone = "UTC"
+	}
+
+	validationErrors := validator.HandleValidationErrors(req)
+	if validationErrors != nil {
+		return nil, validationErrors, nil
+	}
+
+	from, to, validationErrors := parseDayRange(req.StartDate, req.EndDate, req.Timezone)
+	if validationErrors != nil {
+		return nil, validationErrors, nil
+	}
+
+	eventID, err := s.getEventID(req.EventID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	filter := repository.FunnelFilter{From: from, To: to, EventID: eventID}
+
+	totals, err := s.analyticsRepo.GetFunnel(filter)
+	if err != nil {
+		return nil, nil, errs.ErrInternalServerError
+	}
+
+	categories, err := s.analyticsRepo.GetCategoryFunnels(filter)
+	if err != nil {
+		return nil, nil, errs.ErrInternalServerError
+	}
+
+	medians, err := s.analyticsRepo.GetTimeToPay(filter)
+	if err != nil {
+		return nil, nil, errs.ErrInternalServerError
+	}
+
+	var overallMedian *float64
+	categoryMedians := make(map[uuid.UUID]*float64, len(medians))
+	for _, median := range medians {
+		if median.CategoryID == nil {
+			overallMedian = &median.MedianSeconds
+		} else {
+			categoryMedians[*median.CategoryID] = &median.MedianSeconds
+		}
+	}
+
+	categoryResponses := make([]*response.CategoryFunnelResponse, 0, len(categories))
+	for _, category := range categories {
+		categoryResponses = append(categoryResponses, &response.CategoryFunnelResponse{
+			CategoryID:            category.CategoryID,
+			CategoryName:          category.CategoryName,
+			EventID:               category.EventID,
+			EventTitle:            category.EventTitle,
+			FunnelFiguresResponse: *newFunnelFigures(&category.FunnelData, categoryMedians[category.CategoryID]),
+		})
+	}
+
+	return &response.FunnelReportResponse{
+		Timezone:   from.Location().String(),
+		StartDate:  req.StartDate,
+		EndDate:    req.EndDate,
+		EventID:    eventID,
+		Totals:     newFunnelFigures(totals, overallMedian),
+		Categories: categoryResponses,
+	}, nil, nil
+}
+
+// GetTopCustomers ranks customers by what they spent on paid orders or by
+// the tickets they bought.
+func (s *analyticsService) GetTopCustomers(ctx context.Context, req *request.TopCustomersRequest) (*response.TopCustomersResponse, map[string]string, error) {
+	if req.SortBy == "" {
+		req.SortBy = "spend"
+	}
+	if req.Page < 1 {
+		req.Page = 1
+	}
+	if req.Limit < 1 {
+		req.Limit = 10
+	}
+
+	validationErrors := validator.HandleValidationErrors(req)
+	if validationErrors != nil {
+		return nil, validationErrors, nil
+	}
+
+	filter, timezone, validationErrors := customerFilter(req.StartDate, req.EndDate, req.Timezone)
+	if validationErrors != nil {
+		return nil, validationErrors, nil
+	}
+
+	eventID, err := s.getEventID(req.EventID)
+	if err != nil {
+		return nil, nil, err
+	}
+	filter.EventID = eventID
+
+	customers, total, err := s.analyticsRepo.GetTopCustomers(filter, req.SortBy, req.Page, req.Limit)
+	if err != nil {
+		return nil, nil, errs.ErrInternalServerError
+	}
+
+	customerResponses := make([]*response.CustomerResponse, 0, len(customers))
+	for _, customer := range customers {
+		customerResponses = append(customerResponses, newCustomerResponse(customer))
+	}
+
+	totalPages := int((total + int64(req.Limit) - 1) / int64(req.Limit))
+
+	return &response.TopCustomersResponse{
+		SortBy:    req.SortBy,
+		Timezone:  timezone,
+		StartDate: req.StartDate,
+		EndDate:   req.EndDate,
+		Customers: customerResponses,
+		Pagination: &utils_response.Pagination{
+			Page:       req.Page,
+			Limit:      req.Limit,
+			TotalPages: totalPages,
+			Total:      total,
+		},
+	}, nil, nil
+}
+
+// GetRepeatPurchases returns the share of customers who bought tickets for
+// more than one event.
+func (s *analyticsService) GetRepeatPurchases(ctx context.Context, req *request.RepeatPurchaseRequest) (*response.RepeatPurchaseResponse, map[string]string, error) {
+	validationErrors := validator.HandleValidationErrors(req)
+	if validationErrors != nil {
+		return nil, validationErrors, nil
+	}
+
+	filter, timezone, validationErrors := customerFilter(req.StartDate, req.EndDate, req.Timezone)
+	if validationErrors != nil {
+		return nil, validationErrors, nil
+	}
+
+	data, err := s.analyticsRepo.GetRepeatPurchases(filter)
+	if err != nil {
+		return nil, nil, errs.ErrInternalServerError
+	}
+
+	var averageEvents float64
+	if data.Customers > 0 {
+		averageEvents = math.Round(float64(data.Events)*100/float64(data.Customers)) / 100
+	}
+
+	return &response.RepeatPurchaseResponse{
+		Timezone:                 timezone,
+		StartDate:                req.StartDate,
+		EndDate:                  req.EndDate,
+		Customers:                data.Customers,
+		RepeatCustomers:          data.RepeatCustomers,
+		OneTimeCustomers:         data.Customers - data.RepeatCustomers,
+		RepeatPurchaseRate:       percentage(data.RepeatCustomers, data.Customers),
+		AverageEventsPerCustomer: averageEvents,
+	}, nil, nil
+}
+
+// GetEventBuyers splits the buyers of each event into first-time and
+// returning buyers.
+func (s *analyticsService) GetEventBuyers(ctx context.Context, req *request.EventBuyersRequest) (*response.EventBuyersListResponse, map[string]string, error) {
+	if req.Page < 1 {
+		req.Page = 1
+	}
+	if req.Limit < 1 {
+		req.Limit = 10
+	}
+
+	validationErrors := validator.HandleValidationErrors(req)
+	if validationErrors != nil {
+		return nil, validationErrors, nil
+	}
+
+	filter, timezone, validationErrors := customerFilter(req.StartDate, req.EndDate, req.Timezone)
+	if validationErrors != nil {
+		return nil, validationErrors, nil
+	}
+
+	eventID, err := s.getEventID(req.EventID)
+	if err != nil {
+		return nil, nil, err
+	}
+	filter.EventID = eventID
+
+	events, total, err := s.analyticsRepo.GetEventBuyers(filter, req.Page, req.Limit)
+	if err != nil {
+		return nil, nil, errs.ErrInternalServerError
+	}
+
+	eventResponses := make([]*response.EventBuyersResponse, 0, len(events))
+	for _, event := range events {
+		eventResponses = append(eventResponses, &response.EventBuyersResponse{
+			EventID:         event.EventID,
+			EventTitle:      event.EventTitle,
+			StartsAt:        event.StartsAt,
+			Buyers:          event.Buyers,
+			FirstTimeBuyers: event.FirstTimeBuyers,
+			ReturningBuyers: event.ReturningBuyers,
+			ReturningRate:   percentage(event.ReturningBuyers, event.Buyers),
+			FirstTimeSpend:  event.FirstTimeSpend,
+			ReturningSpend:  event.ReturningSpend,
+		})
+	}
+
+	totalPages := int((total + int64(req.Limit) - 1) / int64(req.Limit))
+
+	return &response.EventBuyersListResponse{
+		Timezone:  timezone,
+		StartDate: req.StartDate,
+		EndDate:   req.EndDate,
+		Events:    eventResponses,
+		Pagination: &utils_response.Pagination{
+			Page:       req.Page,
+			Limit:      req.Limit,
+			TotalPages: totalPages,
+			Total:      total,
+		},
+	}, nil, nil
+}
+
+// GetCustomerSummary returns the purchase history of a user.
+func (s *analyticsService) GetCustomerSummary(ctx context.Context, userID uuid.UUID) (*response.CustomerSummaryResponse, error) {
+	user, err := s.userRepo.GetUserByID(userID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errs.ErrUserNotFound
+		}
+		return nil, errs.ErrInternalServerError
+	}
+
+	customer, err := s.analyticsRepo.GetCustomer(userID)
+	if err != nil {
+		return nil, errs.ErrInternalServerError
+	}
+	customer.UserID = user.ID
+	customer.Email = user.Email
+	customer.FirstName = user.FirstName
+	customer.LastName = user.LastName
+
+	statuses, err := s.analyticsRepo.GetCustomerFunnel(userID)
+	if err != nil {
+		return nil, errs.ErrInternalServerError
+	}
+
+	events, err := s.analyticsRepo.GetCustomerEvents(userID)
+	if err != nil {
+		return nil, errs.ErrInternalServerError
+	}
+
+	eventResponses := make([]*response.CustomerEventResponse, 0, len(events))
+	for _, event := range events {
+		eventResponses = append(eventResponses, &response.CustomerEventResponse{
+			EventID:         event.EventID,
+			EventTitle:      event.EventTitle,
+			StartsAt:        event.StartsAt,
+			Orders:          event.Orders,
+			TicketsBought:   event.TicketsBought,
+			CheckedIn:       event.CheckedIn,
+			Spend:           event.Spend,
+			FirstPurchaseAt: event.FirstPurchaseAt,
+			LastPurchaseAt:  event.LastPurchaseAt,
+		})
+	}
+
+	return &response.CustomerSummaryResponse{
+		Customer: newCustomerResponse(customer),
+		OrderStatuses: &response.CustomerOrderStatusResponse{
+			Total:     statuses.OrdersCreated,
+			Pending:   statuses.Pending,
+			Paid:      statuses.Paid,
+			Refunded:  statuses.Refunded,
+			Cancelled: statuses.Cancelled,
+			Expired:   statuses.Expired,
+		},
+		Events: eventResponses,
+	}, nil
+}
+
+// getEventID parses an optional event filter and checks the event exists.
+func (s *analyticsService) getEventID(id string) (*uuid.UUID, error) {
+	if id == "" {
+		return nil, nil
+	}
+
+	eventID := uuid.MustParse(id)
+	if _, err := s.eventRepo.GetEventByID(eventID); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errs.ErrEventNotFound
+		}
+		return nil, errs.ErrInternalServerError
+	}
+	return &eventID, nil
+}
+
+func newFunnelFigures(data *models.FunnelData, medianTimeToPay *float64) *response.FunnelFiguresResponse {
+	settled := data.OrdersCreated - data.Pending
+
+	return &response.FunnelFiguresResponse{
+		OrdersCreated:          data.OrdersCreated,
+		Pending:                data.Pending,
+		Paid:                   data.Paid,
+		Refunded:               data.Refunded,
+		Cancelled:              data.Cancelled,
+		Expired:                data.Expired,
+		ConversionRate:         percentage(data.Paid, settled),
+		CancellationRate:       percentage(data.Cancelled, settled),
+		ExpiryRate:             percentage(data.Expired, settled),
+		AbandonmentRate:        percentage(data.Cancelled+data.Expired, settled),
+		MedianTimeToPaySeconds: medianTimeToPay,
+		Revenue:                data.Revenue,
+		LostRevenue:            data.LostRevenue,
+	}
+}
+
+func newCustomerResponse(customer *models.CustomerData) *response.CustomerResponse {
+	var averageOrderValue float64
+	if customer.Orders > 0 {
+		averageOrderValue = math.Round(customer.Spend*100/float64(customer.Orders)) / 100
+	}
+
+	return &response.CustomerResponse{
+		UserID:            customer.UserID,
+		Email:             customer.Email,
+		FirstName:         customer.FirstName,
+		LastName:          customer.LastName,
+		Orders:            customer.Orders,
+		Events:            customer.Events,
+		TicketsBought:     customer.TicketsBought,
+		Spend:             customer.Spend,
+		AverageOrderValue: averageOrderValue,
+		FirstPurchaseAt:   customer.FirstPurchaseAt,
+		LastPurchaseAt:    customer.LastPurchaseAt,
+	}
+}
+
+// customerFilter selects the orders of an optional date range and returns
+// the name of the time zone it was read in, which is empty without a range.
+func customerFilter(startDate, endDate, timezone string) (repository.CustomerFilter, string, map[string]string) {
+	if startDate == "" {
+		return repository.CustomerFilter{}, "", nil
+	}
+
+	if timezone == "" {
+		timezone = "UTC"
+	}
+
+	from, to, validationErrors := parseDayRange(startDate, endDate, timezone)
+	if validationErrors != nil {
+		return repository.CustomerFilter{}, "", validationErrors
+	}
+
+	return repository.CustomerFilter{From: from, To: to}, from.Location().String(), nil
+}
+
+// parseDayRange returns the range [from, to) covering whole days from
+// startDate to endDate in the named time zone.
+func parseDayRange(startDate, endDate, timezone string) (time.Time, time.Time, map[string]string) {
+	loc, err := time.LoadLocation(timezone)
+	if err != nil {
+		return time.Time{}, time.Time{}, map[string]string{"timezone": "Invalid time zone"}
+	}
+
+	from, err := time.ParseInLocation("2006-01-02", startDate, loc)
+	if err != nil {
+		return time.Time{}, time.Time{}, map[string]string{"start_date": "Invalid start date format. Use YYYY-MM-DD"}
+	}
+
+	end, err := time.ParseInLocation("2006-01-02", endDate, loc)
+	if err != nil {
+		return time.Time{}, time.Time{}, map[string]string{"end_date": "Invalid end date format. Use YYYY-MM-DD"}
+	}
+
+	if end.Before(from) {
+		return time.Time{}, time.Time{}, map[string]string{"end_date": "End date must not be before the start date"}
+	}
+
+	return from, end.AddDate(0, 0, 1), nil
+}
+
+// bucketStarts lists the wall clock starts of the buckets covering [from, to)
+// in from's location. The first bucket may start before from, as weeks and
+// months are aligned to Monday and the first of the month. It stops early
+// once there are more than maxSeriesBuckets buckets.
+func bucketStarts(interval string, from, to time.Time) []time.Time {
+	year, month, day := from.Date()
+	loc := from.Location()
+
+	var start time.Time
+	switch interval {
+	case "hour":
+		start = time.Date(year, month, day, from.Hour(), 0, 0, 0, loc)
+	case "day":
+		start = time.Date(year, month, day, 0, 0, 0, 0, loc)
+	case "week":
+		start = time.Date(year, month, day-weekdayOffset(from.Weekday()), 0, 0, 0, 0, loc)
+	case "month":
+		start = time.Date(year, month, 1, 0, 0, 0, 0, loc)
+	}
+
+	var starts []time.Time
+	for start.Before(to) && len(starts) <= maxSeriesBuckets {
+		starts = append(starts, start)
+
+		year, month, day := start.Date()
+		switch interval {
+		case "hour":
+			// Step by wall clock so buckets line up with the local hours
+			// MySQL groups by, even across daylight saving changes.
+			next := time.Date(year, month, day, start.Hour()+1, 0, 0, 0, loc)
+			if !next.After(start) {
+				next = start.Add(time.Hour)
+			}
+			start = next
+		case "day":
+			start = time.Date(year, month, day+1, 0, 0, 0, 0, loc)
+		case "week":
+			start = time.Date(year, month, day+7, 0, 0, 0, 0, loc)
+		case "month":
+			start = time.Date(year, month+1, 1, 0, 0, 0, 0, loc)
+		}
+	}
+	return starts
+}
+
+// weekdayOffset counts days since Monday.
+func weekdayOffset(day time.Weekday) int {
+	return (int(day) + 6) % 7
+}
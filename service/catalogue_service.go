@@ -0,0 +1,179 @@
+package service
+
+import (
+	"context"
+	"crypto/sha1"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"ticert/dto/request"
+	"ticert/dto/response"
+	"ticert/repository"
+	"ticert/utils/errs"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+const (
+	catalogueCacheNamespace = "catalogue"
+
+	// CatalogueCacheTTL is how long catalogue responses are cached, both in
+	// Redis and by HTTP clients. Stock changes from orders do not invalidate
+	// the cache, so sale status can lag by up to this long.
+	CatalogueCacheTTL = time.Minute
+)
+
+// CatalogueService serves the public, read-only view of published events.
+// Responses are cached in Redis; the cache is best effort, so Redis failures
+// fall back to the database.
+type CatalogueService interface {
+	GetEvents(ctx context.Context, req *request.GetEventsRequest) (*response.PublicEventListResponse, map[string]string, error)
+	GetEvent(ctx context.Context, id uuid.UUID) (*response.PublicEventResponse, error)
+	GetCategory(ctx context.Context, id uuid.UUID) (*response.PublicCategoryResponse, error)
+	Invalidate(ctx context.Context)
+}
+
+type catalogueService struct {
+	eventRepo    repository.EventRepository
+	categoryRepo repository.CategoryRepository
+	cacheRepo    repository.CacheRepository
+}
+
+func NewCatalogueService(eventRepo repository.EventRepository, categoryRepo repository.CategoryRepository, cacheRepo repository.CacheRepository) CatalogueService {
+	return &catalogueService{eventRepo: eventRepo, categoryRepo: categoryRepo, cacheRepo: cacheRepo}
+}
+
+func (s *catalogueService) GetEvents(ctx context.Context, req *request.GetEventsRequest) (*response.PublicEventListResponse, map[string]string, error) {
+	filter, validationErrors := newEventFilter(req)
+	if validationErrors != nil {
+		return nil, validationErrors, nil
+	}
+	filter.Status = "published"
+
+	// The request is already normalised, so its JSON form identifies the page.
+	query, err := json.Marshal(req)
+	if err != nil {
+		return nil, nil, errs.ErrInternalServerError
+	}
+	cacheKey := fmt.Sprintf("events:%x", sha1.Sum(query))
+
+	var cached response.PublicEventListResponse
+	if s.fromCache(cacheKey, &cached) {
+		return &cached, nil, nil
+	}
+
+	events, total, err := s.eventRepo.GetEvents(filter)
+	if err != nil {
+		return nil, nil, errs.ErrInternalServerError
+	}
+
+	facets, err := s.eventRepo.GetEventFacets(filter)
+	if err != nil {
+		return nil, nil, errs.ErrInternalServerError
+	}
+
+	events, pagination := newEventPage(events, total, filter)
+
+	eventResponses := make([]*response.PublicEventResponse, 0, len(events))
+	for _, event := range events {
+		eventResponses = append(eventResponses, response.NewPublicEventResponse(event))
+	}
+
+	listResponse := &response.PublicEventListResponse{
+		Events:     eventResponses,
+		Pagination: pagination,
+		Facets:     newEventFacetsResponse(facets),
+	}
+
+	s.toCache(cacheKey, listResponse)
+	return listResponse, nil, nil
+}
+
+func (s *catalogueService) GetEvent(ctx context.Context, id uuid.UUID) (*response.PublicEventResponse, error) {
+	cacheKey := "event:" + id.String()
+	var cached response.PublicEventResponse
+	if s.fromCache(cacheKey, &cached) {
+		return &cached, nil
+	}
+
+	event, err := s.eventRepo.GetEventByID(id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errs.ErrEventNotFound
+		}
+		return nil, errs.ErrInternalServerError
+	}
+
+	if event.Status != "published" {
+		return nil, errs.ErrEventNotFound
+	}
+
+	categories, err := s.categoryRepo.GetCategories(event.ID)
+	if err != nil {
+		return nil, errs.ErrInternalServerError
+	}
+	for _, category := range categories {
+		event.Categories = append(event.Categories, *category)
+	}
+
+	eventResponse := response.NewPublicEventResponse(event)
+	s.toCache(cacheKey, eventResponse)
+	return eventResponse, nil
+}
+
+func (s *catalogueService) GetCategory(ctx context.Context, id uuid.UUID) (*response.PublicCategoryResponse, error) {
+	cacheKey := "category:" + id.String()
+	var cached response.PublicCategoryResponse
+	if s.fromCache(cacheKey, &cached) {
+		return &cached, nil
+	}
+
+	category, err := s.categoryRepo.GetCategoryByID(id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errs.ErrCategoryNotFound
+		}
+		return nil, errs.ErrInternalServerError
+	}
+
+	if category.Event == nil || category.Event.Status != "published" {
+		return nil, errs.ErrCategoryNotFound
+	}
+
+	categoryResponse := response.NewPublicCategoryResponse(category)
+	s.toCache(cacheKey, categoryResponse)
+	return categoryResponse, nil
+}
+
+// Invalidate drops every cached catalogue response. It is called whenever
+// events or categories change.
+func (s *catalogueService) Invalidate(ctx context.Context) {
+	if err := s.cacheRepo.Invalidate(catalogueCacheNamespace); err != nil {
+		log.Printf("catalogue: failed to invalidate cache: %v", err)
+	}
+}
+
+func (s *catalogueService) fromCache(key string, target interface{}) bool {
+	value, found, err := s.cacheRepo.Get(catalogueCacheNamespace, key)
+	if err != nil {
+		log.Printf("catalogue: failed to read cache %s: %v", key, err)
+		return false
+	}
+	if !found {
+		return false
+	}
+	return json.Unmarshal(value, target) == nil
+}
+
+func (s *catalogueService) toCache(key string, value interface{}) {
+	payload, err := json.Marshal(value)
+	if err != nil {
+		return
+	}
+	if err := s.cacheRepo.Set(catalogueCacheNamespace, key, payload, CatalogueCacheTTL); err != nil {
+		log.Printf("catalogue: failed to write cache %s: %v", key, err)
+	}
+}
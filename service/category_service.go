@@ -3,6 +3,9 @@ package service
 import (
 	"context"
 	"errors"
+	"fmt"
+	"log"
+	"slices"
 	"ticert/dto/request"
 	"ticert/dto/response"
 	"ticert/entity"
@@ -21,15 +24,20 @@ type CategoryService interface {
 	GetCategories(ctx context.Context, eventID uuid.UUID) ([]*response.CategoryResponse, error)
 	UpdateCategory(ctx context.Context, id uuid.UUID, req *request.UpdateCategoryRequest) (*response.CategoryResponse, map[string]string, error)
 	DeleteCategory(ctx context.Context, id uuid.UUID) error
+	GetPriceTiers(ctx context.Context, id uuid.UUID) ([]*response.PriceTierResponse, error)
+	UpdatePriceTiers(ctx context.Context, id uuid.UUID, req *request.UpdatePriceTiersRequest) ([]*response.PriceTierResponse, map[string]string, error)
 }
 
 type categoryService struct {
-	categoryRepo repository.CategoryRepository
-	eventRepo    repository.EventRepository
+	categoryRepo     repository.CategoryRepository
+	eventRepo        repository.EventRepository
+	inventoryService InventoryService
+	waitlistService  WaitlistService
+	catalogueService CatalogueService
 }
 
-func NewCategoryService(categoryRepo repository.CategoryRepository, eventRepo repository.EventRepository) CategoryService {
-	return &categoryService{categoryRepo: categoryRepo, eventRepo: eventRepo}
+func NewCategoryService(categoryRepo repository.CategoryRepository, eventRepo repository.EventRepository, inventoryService InventoryService, waitlistService WaitlistService, catalogueService CatalogueService) CategoryService {
+	return &categoryService{categoryRepo: categoryRepo, eventRepo: eventRepo, inventoryService: inventoryService, waitlistService: waitlistService, catalogueService: catalogueService}
 }
 
 func (s *categoryService) CreateCategory(ctx context.Context, req *request.CreateCategoryRequest) (*response.CategoryResponse, map[string]string, error) {
@@ -38,7 +46,7 @@ func (s *categoryService) CreateCategory(ctx context.Context, req *request.Creat
 		return nil, validationErrors, nil
 	}
 
-	_, err := s.eventRepo.GetEventByID(req.EventID)
+	event, err := s.eventRepo.GetEventByID(req.EventID)
 	if err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
 			return nil, nil, errs.ErrEventNotFound
@@ -51,18 +59,64 @@ func (s *categoryService) CreateCategory(ctx context.Context, req *request.Creat
 		return nil, map[string]string{"event_date": "Invalid event date format. Use YYYY-MM-DD"}, nil
 	}
 
+	inventoryStrategy := req.InventoryStrategy
+	if inventoryStrategy == "" {
+		inventoryStrategy = "database"
+	}
+
+	minPerOrder := req.MinPerOrder
+	if minPerOrder == 0 {
+		minPerOrder = 1
+	}
+
 	category := &entity.Category{
-		EventID:   req.EventID,
-		Name:      req.Name,
-		Price:     req.Price,
-		Quantity:  req.Quantity,
-		EventDate: parsedDate,
+		EventID:           req.EventID,
+		Name:              req.Name,
+		Price:             req.Price,
+		Quantity:          req.Quantity,
+		EventDate:         parsedDate,
+		InventoryStrategy: inventoryStrategy,
+		MinPerOrder:       minPerOrder,
+		MaxPerOrder:       req.MaxPerOrder,
+		MaxPerUser:        req.MaxPerUser,
 	}
 
-	if err := s.categoryRepo.CreateCategory(category); err != nil {
+	if req.SalesStartAt != "" {
+		salesStartAt, err := time.Parse(time.RFC3339, req.SalesStartAt)
+		if err != nil {
+			return nil, map[string]string{"sales_start_at": "Invalid sales start format. Use RFC 3339, e.g. 2025-01-02T09:00:00+07:00"}, nil
+		}
+		category.SalesStartAt = &salesStartAt
+	}
+
+	if req.SalesEndAt != "" {
+		salesEndAt, err := time.Parse(time.RFC3339, req.SalesEndAt)
+		if err != nil {
+			return nil, map[string]string{"sales_end_at": "Invalid sales end format. Use RFC 3339, e.g. 2025-01-02T21:00:00+07:00"}, nil
+		}
+		category.SalesEndAt = &salesEndAt
+	}
+
+	if validationErrors := validateSalesSettings(category); validationErrors != nil {
+		return nil, validationErrors, nil
+	}
+
+	if err := s.categoryRepo.CreateCategory(category, venueCapacityCheck(event, category)); err != nil {
+		var capacityErr *capacityExceededError
+		if errors.As(err, &capacityErr) {
+			return nil, capacityErr.validationErrors, nil
+		}
 		return nil, nil, errs.ErrInternalServerError
 	}
 
+	if category.InventoryStrategy == "redis" {
+		if err := s.inventoryService.SetStock(ctx, category.ID, category.Quantity); err != nil {
+			return nil, nil, errs.ErrInternalServerError
+		}
+	}
+
+	s.catalogueService.Invalidate(ctx)
+
 	return response.NewCategoryResponse(category), nil, nil
 }
 
@@ -102,11 +156,6 @@ func (s *categoryService) UpdateCategory(ctx context.Context, id uuid.UUID, req
 		return nil, validationErrors, nil
 	}
 
-	parsedDate, err := time.Parse("2006-01-02", req.EventDate)
-	if err != nil {
-		return nil, map[string]string{"event_date": "Invalid event date format. Use YYYY-MM-DD"}, nil
-	}
-
 	category, err := s.categoryRepo.GetCategoryByID(id)
 	if err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
@@ -115,39 +164,323 @@ func (s *categoryService) UpdateCategory(ctx context.Context, id uuid.UUID, req
 		return nil, nil, errs.ErrInternalServerError
 	}
 
+	// Only the columns the request changes are written, so stock moved by
+	// orders and waitlist claims since the category was read is kept.
+	var columns []string
+
 	if req.Name != "" {
 		category.Name = req.Name
+		columns = append(columns, "name")
 	}
 
 	if req.Price != 0 {
 		category.Price = req.Price
+		columns = append(columns, "price")
 	}
 
+	quantityDelta := 0
 	if req.Quantity != 0 {
+		quantityDelta = req.Quantity - category.Quantity
 		category.Quantity = req.Quantity
+		columns = append(columns, "quantity")
 	}
 
 	if req.EventDate != "" {
+		parsedDate, err := time.Parse("2006-01-02", req.EventDate)
+		if err != nil {
+			return nil, map[string]string{"event_date": "Invalid event date format. Use YYYY-MM-DD"}, nil
+		}
 		category.EventDate = parsedDate
+		columns = append(columns, "event_date")
+	}
+
+	if req.SalesStartAt != nil {
+		category.SalesStartAt = nil
+		if *req.SalesStartAt != "" {
+			salesStartAt, err := time.Parse(time.RFC3339, *req.SalesStartAt)
+			if err != nil {
+				return nil, map[string]string{"sales_start_at": "Invalid sales start format. Use RFC 3339, e.g. 2025-01-02T09:00:00+07:00"}, nil
+			}
+			category.SalesStartAt = &salesStartAt
+		}
+		columns = append(columns, "sales_start_at")
+	}
+
+	if req.SalesEndAt != nil {
+		category.SalesEndAt = nil
+		if *req.SalesEndAt != "" {
+			salesEndAt, err := time.Parse(time.RFC3339, *req.SalesEndAt)
+			if err != nil {
+				return nil, map[string]string{"sales_end_at": "Invalid sales end format. Use RFC 3339, e.g. 2025-01-02T21:00:00+07:00"}, nil
+			}
+			category.SalesEndAt = &salesEndAt
+		}
+		columns = append(columns, "sales_end_at")
+	}
+
+	if req.MinPerOrder != nil {
+		category.MinPerOrder = *req.MinPerOrder
+		columns = append(columns, "min_per_order")
+	}
+
+	if req.MaxPerOrder != nil {
+		category.MaxPerOrder = *req.MaxPerOrder
+		columns = append(columns, "max_per_order")
+	}
+
+	if req.MaxPerUser != nil {
+		category.MaxPerUser = *req.MaxPerUser
+		columns = append(columns, "max_per_user")
+	}
+
+	if validationErrors := validateSalesSettings(category); validationErrors != nil {
+		return nil, validationErrors, nil
+	}
+
+	var capacityCheck func(categories []*entity.Category) error
+	if req.Quantity != 0 || req.EventDate != "" {
+		event, err := s.eventRepo.GetEventByID(category.EventID)
+		if err != nil {
+			return nil, nil, errs.ErrInternalServerError
+		}
+		capacityCheck = venueCapacityCheck(event, category)
+	}
+
+	if category.SeatingMode == "reserved" && req.Quantity != 0 {
+		return nil, map[string]string{"quantity": "Quantity of a reserved seating category follows its assigned seats"}, nil
+	}
+
+	stockChanged := req.Quantity != 0
+	attached := false
+	if req.InventoryStrategy != "" && req.InventoryStrategy != category.InventoryStrategy {
+		if req.InventoryStrategy == "redis" && category.SeatingMode == "reserved" {
+			return nil, nil, errs.ErrReservedSeatingStrategy
+		}
+
+		pendingOrders, err := s.categoryRepo.CountPendingOrders(category.ID)
+		if err != nil {
+			return nil, nil, errs.ErrInternalServerError
+		}
+
+		if pendingOrders > 0 {
+			return nil, nil, errs.ErrInventoryStrategyLocked
+		}
+
+		if category.InventoryStrategy == "redis" {
+			if err := s.inventoryService.Detach(ctx, category.ID); err != nil {
+				return nil, nil, errs.ErrInternalServerError
+			}
+
+			if !stockChanged {
+				detached, err := s.categoryRepo.GetCategoryByID(id)
+				if err != nil {
+					return nil, nil, errs.ErrInternalServerError
+				}
+				category.Quantity = detached.Quantity
+				category.Status = detached.Status
+			}
+		} else {
+			stockChanged = true
+			attached = true
+			// Re-read under the lock, as the quantity becomes the stock.
+			if !slices.Contains(columns, "quantity") {
+				columns = append(columns, "quantity")
+			}
+		}
+
+		category.InventoryStrategy = req.InventoryStrategy
+		columns = append(columns, "inventory_strategy")
 	}
 
-	if err := s.categoryRepo.UpdateCategory(category); err != nil {
+	if err := s.categoryRepo.UpdateCategory(category, columns, quantityDelta, capacityCheck); err != nil {
+		var capacityErr *capacityExceededError
+		if errors.As(err, &capacityErr) {
+			return nil, capacityErr.validationErrors, nil
+		}
 		return nil, nil, errs.ErrInternalServerError
 	}
 
+	// A category moving to Redis has no holds yet and takes its quantity as
+	// the stock; otherwise the change is applied to the live stock.
+	if category.InventoryStrategy == "redis" && attached {
+		if err := s.inventoryService.SetStock(ctx, category.ID, category.Quantity); err != nil {
+			return nil, nil, errs.ErrInternalServerError
+		}
+	} else if category.InventoryStrategy == "redis" && stockChanged {
+		if err := s.inventoryService.AdjustStock(ctx, category.ID, quantityDelta, category.Quantity); err != nil {
+			return nil, nil, errs.ErrInternalServerError
+		}
+	}
+
+	// New stock is offered to the waitlist before it goes on general sale.
+	if stockChanged {
+		if err := s.waitlistService.Offer(ctx, category.ID); err != nil {
+			log.Printf("Failed to offer waitlist stock for category %s: %v", category.ID, err)
+		}
+
+		category, err = s.categoryRepo.GetCategoryByID(id)
+		if err != nil {
+			return nil, nil, errs.ErrInternalServerError
+		}
+	}
+
+	s.catalogueService.Invalidate(ctx)
+
 	return response.NewCategoryResponse(category), nil, nil
 }
 
 func (s *categoryService) DeleteCategory(ctx context.Context, id uuid.UUID) error {
-	_, err := s.categoryRepo.GetCategoryByID(id)
+	category, err := s.categoryRepo.GetCategoryByID(id)
 	if err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
 			return errs.ErrCategoryNotFound
 		}
 		return errs.ErrInternalServerError
 	}
+
+	if category.InventoryStrategy == "redis" {
+		if err := s.inventoryService.Detach(ctx, category.ID); err != nil {
+			return errs.ErrInternalServerError
+		}
+	}
 	if err := s.categoryRepo.DeleteCategory(id); err != nil {
 		return errs.ErrInternalServerError
 	}
+
+	s.catalogueService.Invalidate(ctx)
+
+	return nil
+}
+
+func (s *categoryService) GetPriceTiers(ctx context.Context, id uuid.UUID) ([]*response.PriceTierResponse, error) {
+	category, err := s.categoryRepo.GetCategoryByID(id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errs.ErrCategoryNotFound
+		}
+		return nil, errs.ErrInternalServerError
+	}
+
+	return response.NewPriceTierListResponse(category.PriceTiers), nil
+}
+
+// UpdatePriceTiers replaces the whole price schedule of a category. Tiers are
+// evaluated in the order given; an empty list removes the schedule.
+func (s *categoryService) UpdatePriceTiers(ctx context.Context, id uuid.UUID, req *request.UpdatePriceTiersRequest) ([]*response.PriceTierResponse, map[string]string, error) {
+	validationErrors := validator.HandleValidationErrors(req)
+	if validationErrors != nil {
+		return nil, validationErrors, nil
+	}
+
+	if _, err := s.categoryRepo.GetCategoryByID(id); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil, errs.ErrCategoryNotFound
+		}
+		return nil, nil, errs.ErrInternalServerError
+	}
+
+	tiers := make([]*entity.PriceTier, 0, len(req.Tiers))
+	for i, tierReq := range req.Tiers {
+		tier := &entity.PriceTier{
+			Name:      tierReq.Name,
+			Price:     tierReq.Price,
+			UnitLimit: tierReq.UnitLimit,
+			Position:  i,
+		}
+
+		if tierReq.StartsAt != "" {
+			startsAt, err := time.Parse(time.RFC3339, tierReq.StartsAt)
+			if err != nil {
+				return nil, map[string]string{fmt.Sprintf("tiers[%d].starts_at", i): "Invalid start format. Use RFC 3339, e.g. 2025-01-02T09:00:00+07:00"}, nil
+			}
+			tier.StartsAt = &startsAt
+		}
+
+		if tierReq.EndsAt != "" {
+			endsAt, err := time.Parse(time.RFC3339, tierReq.EndsAt)
+			if err != nil {
+				return nil, map[string]string{fmt.Sprintf("tiers[%d].ends_at", i): "Invalid end format. Use RFC 3339, e.g. 2025-01-02T21:00:00+07:00"}, nil
+			}
+			tier.EndsAt = &endsAt
+		}
+
+		if tier.StartsAt != nil && tier.EndsAt != nil && !tier.EndsAt.After(*tier.StartsAt) {
+			return nil, map[string]string{fmt.Sprintf("tiers[%d].ends_at", i): "Tier end must be after tier start"}, nil
+		}
+
+		tiers = append(tiers, tier)
+	}
+
+	if err := s.categoryRepo.ReplacePriceTiers(id, tiers); err != nil {
+		return nil, nil, errs.ErrInternalServerError
+	}
+
+	category, err := s.categoryRepo.GetCategoryByID(id)
+	if err != nil {
+		return nil, nil, errs.ErrInternalServerError
+	}
+
+	s.catalogueService.Invalidate(ctx)
+
+	return response.NewPriceTierListResponse(category.PriceTiers), nil, nil
+}
+
+// capacityExceededError carries the validation errors of a failed venue
+// capacity check out of the repository transaction.
+type capacityExceededError struct {
+	validationErrors map[string]string
+}
+
+func (e *capacityExceededError) Error() string {
+	return "venue capacity exceeded"
+}
+
+// venueCapacityCheck returns a check that the tickets of all categories on
+// the category's event date, counting sold and waitlist-held units, fit the
+// venue. The repository runs it on the event's categories under a lock, so
+// concurrent changes cannot both pass it. Events without a venue are not
+// checked.
+func venueCapacityCheck(event *entity.Event, category *entity.Category) func(categories []*entity.Category) error {
+	if event.Venue == nil {
+		return nil
+	}
+	capacity := event.Venue.Capacity
+
+	return func(categories []*entity.Category) error {
+		eventDate := category.EventDate.Format("2006-01-02")
+		total := category.Quantity
+		for _, other := range categories {
+			if other.ID == category.ID {
+				total += other.SoldQuantity + other.WaitlistHeld
+				continue
+			}
+			if other.EventDate.Format("2006-01-02") != eventDate {
+				continue
+			}
+			total += other.Quantity + other.SoldQuantity + other.WaitlistHeld
+		}
+
+		if total > capacity {
+			return &capacityExceededError{map[string]string{"quantity": fmt.Sprintf("Tickets on %s would total %d, exceeding the venue capacity of %d", eventDate, total, capacity)}}
+		}
+		return nil
+	}
+}
+
+// validateSalesSettings checks that the sale window is ordered and that the
+// purchase limits are consistent. A limit of zero means unlimited.
+func validateSalesSettings(category *entity.Category) map[string]string {
+	if category.SalesStartAt != nil && category.SalesEndAt != nil && !category.SalesEndAt.After(*category.SalesStartAt) {
+		return map[string]string{"sales_end_at": "Sales end must be after sales start"}
+	}
+
+	if category.MaxPerOrder > 0 && category.MaxPerOrder < category.MinPerOrder {
+		return map[string]string{"max_per_order": "Maximum per order cannot be less than minimum per order"}
+	}
+
+	if category.MaxPerUser > 0 && category.MaxPerUser < category.MinPerOrder {
+		return map[string]string{"max_per_user": "Maximum per user cannot be less than minimum per order"}
+	}
+
 	return nil
 }
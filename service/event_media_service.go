@@ -0,0 +1,187 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"mime/multipart"
+	"ticert/dto/response"
+	"ticert/entity"
+	"ticert/repository"
+	"ticert/utils/errs"
+	"ticert/utils/imaging"
+	"ticert/utils/storage"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+const (
+	// MaxImageSize is the largest image file accepted for upload.
+	MaxImageSize = 10 << 20
+
+	maxGalleryImages = 20
+
+	thumbnailWidth  = 480
+	thumbnailHeight = 480
+)
+
+type EventMediaService interface {
+	UploadPoster(ctx context.Context, eventID uuid.UUID, file *multipart.FileHeader) (*response.EventImageResponse, map[string]string, error)
+	UploadGalleryImage(ctx context.Context, eventID uuid.UUID, file *multipart.FileHeader) (*response.EventImageResponse, map[string]string, error)
+	DeleteImage(ctx context.Context, eventID, imageID uuid.UUID) error
+}
+
+type eventMediaService struct {
+	eventRepo        repository.EventRepository
+	storage          storage.Storage
+	catalogueService CatalogueService
+}
+
+func NewEventMediaService(eventRepo repository.EventRepository, storage storage.Storage, catalogueService CatalogueService) EventMediaService {
+	return &eventMediaService{eventRepo: eventRepo, storage: storage, catalogueService: catalogueService}
+}
+
+// UploadPoster stores the event's poster, replacing the previous one.
+func (s *eventMediaService) UploadPoster(ctx context.Context, eventID uuid.UUID, file *multipart.FileHeader) (*response.EventImageResponse, map[string]string, error) {
+	return s.upload(ctx, eventID, "poster", file)
+}
+
+func (s *eventMediaService) UploadGalleryImage(ctx context.Context, eventID uuid.UUID, file *multipart.FileHeader) (*response.EventImageResponse, map[string]string, error) {
+	images, err := s.eventRepo.GetEventImages(eventID, "gallery")
+	if err != nil {
+		return nil, nil, errs.ErrInternalServerError
+	}
+	if len(images) >= maxGalleryImages {
+		return nil, nil, errs.ErrEventGalleryFull
+	}
+
+	return s.upload(ctx, eventID, "gallery", file)
+}
+
+// upload checks the file, stores it with its thumbnail and records the image.
+// The content type is sniffed from the file itself rather than trusted from
+// the client.
+func (s *eventMediaService) upload(ctx context.Context, eventID uuid.UUID, kind string, file *multipart.FileHeader) (*response.EventImageResponse, map[string]string, error) {
+	if _, err := s.eventRepo.GetEventByID(eventID); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil, errs.ErrEventNotFound
+		}
+		return nil, nil, errs.ErrInternalServerError
+	}
+
+	data, validationErrors := readImageFile(file)
+	if validationErrors != nil {
+		return nil, validationErrors, nil
+	}
+
+	info, err := imaging.Inspect(data)
+	if err != nil {
+		if errors.Is(err, imaging.ErrTooLarge) {
+			return nil, map[string]string{"file": fmt.Sprintf("Image must be at most %d megapixels", imaging.MaxPixels/1_000_000)}, nil
+		}
+		return nil, map[string]string{"file": "File must be a JPEG, PNG or GIF image"}, nil
+	}
+
+	thumbnail, _, err := imaging.Thumbnail(data, thumbnailWidth, thumbnailHeight)
+	if err != nil {
+		return nil, map[string]string{"file": "File must be a JPEG, PNG or GIF image"}, nil
+	}
+
+	image := &entity.EventImage{
+		ID:          uuid.New(),
+		EventID:     eventID,
+		Kind:        kind,
+		ContentType: info.ContentType,
+		Size:        int64(len(data)),
+		Width:       info.Width,
+		Height:      info.Height,
+	}
+	image.Key = fmt.Sprintf("events/%s/%s%s", eventID, image.ID, info.Extension)
+	image.ThumbnailKey = fmt.Sprintf("events/%s/%s_thumb.jpg", eventID, image.ID)
+	image.URL = s.storage.URL(image.Key)
+	image.ThumbnailURL = s.storage.URL(image.ThumbnailKey)
+
+	if err := s.storage.Put(ctx, image.Key, bytes.NewReader(data), int64(len(data)), info.ContentType); err != nil {
+		log.Printf("media: failed to store %s: %v", image.Key, err)
+		return nil, nil, errs.ErrInternalServerError
+	}
+	if err := s.storage.Put(ctx, image.ThumbnailKey, bytes.NewReader(thumbnail), int64(len(thumbnail)), "image/jpeg"); err != nil {
+		log.Printf("media: failed to store %s: %v", image.ThumbnailKey, err)
+		s.removeFiles(ctx, image)
+		return nil, nil, errs.ErrInternalServerError
+	}
+
+	replaced, err := s.eventRepo.AddEventImage(image)
+	if err != nil {
+		s.removeFiles(ctx, image)
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil, errs.ErrEventNotFound
+		}
+		return nil, nil, errs.ErrInternalServerError
+	}
+
+	for _, old := range replaced {
+		s.removeFiles(ctx, old)
+	}
+
+	s.catalogueService.Invalidate(ctx)
+
+	return response.NewEventImageResponse(image), nil, nil
+}
+
+func (s *eventMediaService) DeleteImage(ctx context.Context, eventID, imageID uuid.UUID) error {
+	image, err := s.eventRepo.GetEventImageByID(eventID, imageID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return errs.ErrEventImageNotFound
+		}
+		return errs.ErrInternalServerError
+	}
+
+	if err := s.eventRepo.DeleteEventImage(image.ID); err != nil {
+		return errs.ErrInternalServerError
+	}
+
+	s.removeFiles(ctx, image)
+	s.catalogueService.Invalidate(ctx)
+	return nil
+}
+
+// removeFiles deletes an image and its thumbnail from storage. Failures only
+// leave orphaned files behind, so they are logged rather than returned.
+func (s *eventMediaService) removeFiles(ctx context.Context, image *entity.EventImage) {
+	for _, key := range []string{image.Key, image.ThumbnailKey} {
+		if err := s.storage.Delete(ctx, key); err != nil {
+			log.Printf("media: failed to delete %s: %v", key, err)
+		}
+	}
+}
+
+func readImageFile(file *multipart.FileHeader) ([]byte, map[string]string) {
+	tooLarge := map[string]string{"file": fmt.Sprintf("File must be at most %d MB", MaxImageSize>>20)}
+	if file.Size > MaxImageSize {
+		return nil, tooLarge
+	}
+
+	f, err := file.Open()
+	if err != nil {
+		return nil, map[string]string{"file": "File could not be read"}
+	}
+	defer f.Close()
+
+	data, err := io.ReadAll(io.LimitReader(f, MaxImageSize+1))
+	if err != nil {
+		return nil, map[string]string{"file": "File could not be read"}
+	}
+	if len(data) > MaxImageSize {
+		return nil, tooLarge
+	}
+	if len(data) == 0 {
+		return nil, map[string]string{"file": "File is empty"}
+	}
+	return data, nil
+}
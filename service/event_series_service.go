@@ -0,0 +1,140 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"ticert/dto/request"
+	"ticert/dto/response"
+	"ticert/entity"
+	"ticert/repository"
+	"ticert/utils/errs"
+	"ticert/utils/rrule"
+	"ticert/utils/validator"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// maxSeriesOccurrences bounds how many events one recurrence rule may create.
+const maxSeriesOccurrences = 366
+
+type EventSeriesService interface {
+	CreateSeries(ctx context.Context, req *request.CreateEventSeriesRequest) (*response.EventSeriesResponse, map[string]string, error)
+	GetSeries(ctx context.Context, id uuid.UUID) (*response.EventSeriesResponse, error)
+}
+
+type eventSeriesService struct {
+	eventRepo        repository.EventRepository
+	inventoryService InventoryService
+	catalogueService CatalogueService
+}
+
+func NewEventSeriesService(eventRepo repository.EventRepository, inventoryService InventoryService, catalogueService CatalogueService) EventSeriesService {
+	return &eventSeriesService{eventRepo: eventRepo, inventoryService: inventoryService, catalogueService: catalogueService}
+}
+
+// CreateSeries turns an existing event into the first occurrence of a series
+// and generates the other occurrences from the recurrence rule. Each
+// occurrence keeps the template's wall clock time in its time zone and gets
+// its own copy of the template's categories and stock.
+func (s *eventSeriesService) CreateSeries(ctx context.Context, req *request.CreateEventSeriesRequest) (*response.EventSeriesResponse, map[string]string, error) {
+	validationErrors := validator.HandleValidationErrors(req)
+	if validationErrors != nil {
+		return nil, validationErrors, nil
+	}
+
+	template, err := s.eventRepo.GetEventByID(req.TemplateEventID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil, errs.ErrEventNotFound
+		}
+		return nil, nil, errs.ErrInternalServerError
+	}
+
+	if template.SeriesID != nil {
+		return nil, nil, errs.ErrEventAlreadyInSeries
+	}
+
+	if template.Status == "cancelled" {
+		return nil, nil, errs.ErrEventCancelled
+	}
+
+	rule, err := rrule.Parse(req.RRule)
+	if err != nil {
+		return nil, map[string]string{"rrule": err.Error()}, nil
+	}
+
+	zone := template.Zone()
+	starts, err := rule.Occurrences(template.StartsAt.In(zone), maxSeriesOccurrences)
+	if err != nil {
+		if errors.Is(err, rrule.ErrTooManyOccurrences) {
+			return nil, map[string]string{"rrule": fmt.Sprintf("A series can have at most %d occurrences", maxSeriesOccurrences)}, nil
+		}
+		return nil, map[string]string{"rrule": err.Error()}, nil
+	}
+
+	duration := template.EndsAt.Sub(template.StartsAt)
+	occurrences := make([]*entity.Event, 0, len(starts)-1)
+	for _, start := range starts[1:] {
+		title := fmt.Sprintf("%s - %s", template.Title, start.Format("02 Jan 2006"))
+		if existing, _ := s.eventRepo.GetEventByTitle(title); existing != nil {
+			return nil, nil, errs.ErrEventTitleAlreadyExists
+		}
+
+		occurrences = append(occurrences, &entity.Event{
+			Organizer:   template.Organizer,
+			Title:       title,
+			Description: template.Description,
+			StartsAt:    start.UTC(),
+			EndsAt:      start.Add(duration).UTC(),
+			Timezone:    template.Timezone,
+			Location:    template.Location,
+			Status:      template.Status,
+			VenueID:     template.VenueID,
+			SeatMapID:   template.SeatMapID,
+		})
+	}
+
+	series := &entity.EventSeries{
+		Title:           template.Title,
+		RRule:           req.RRule,
+		Timezone:        template.Timezone,
+		TemplateEventID: template.ID,
+	}
+
+	categories, err := s.eventRepo.CreateSeries(series, template, occurrences)
+	if err != nil {
+		return nil, nil, errs.ErrInternalServerError
+	}
+
+	for _, category := range categories {
+		if category.InventoryStrategy != "redis" {
+			continue
+		}
+		if err := s.inventoryService.SetStock(ctx, category.ID, category.Quantity); err != nil {
+			return nil, nil, errs.ErrInternalServerError
+		}
+	}
+
+	s.catalogueService.Invalidate(ctx)
+
+	series.Events = append(series.Events, *template)
+	for _, occurrence := range occurrences {
+		series.Events = append(series.Events, *occurrence)
+	}
+
+	return response.NewEventSeriesResponse(series), nil, nil
+}
+
+func (s *eventSeriesService) GetSeries(ctx context.Context, id uuid.UUID) (*response.EventSeriesResponse, error) {
+	series, err := s.eventRepo.GetSeriesByID(id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errs.ErrEventSeriesNotFound
+		}
+		return nil, errs.ErrInternalServerError
+	}
+
+	return response.NewEventSeriesResponse(series), nil
+}
@@ -3,10 +3,12 @@ package service
 import (
 	"context"
 	"errors"
+	"strings"
 	"ticert/dto/request"
 	"ticert/dto/response"
 	"ticert/entity"
 	"ticert/repository"
+	"ticert/utils/cursor"
 	"ticert/utils/errs"
 	utils_response "ticert/utils/response"
 	"ticert/utils/validator"
@@ -22,14 +24,21 @@ type EventService interface {
 	GetEvents(ctx context.Context, req *request.GetEventsRequest) (*response.EventListResponse, map[string]string, error)
 	UpdateEvent(ctx context.Context, id uuid.UUID, req *request.UpdateEventRequest) (*response.EventResponse, map[string]string, error)
 	DeleteEvent(ctx context.Context, id uuid.UUID) error
+	CancelEvent(ctx context.Context, id uuid.UUID, req *request.CancelEventRequest) (*response.EventResponse, map[string]string, error)
+	CloneEvent(ctx context.Context, id uuid.UUID, req *request.CloneEventRequest) (*response.EventResponse, map[string]string, error)
 }
 
 type eventService struct {
-	eventRepo repository.EventRepository
+	eventRepo        repository.EventRepository
+	seatRepo         repository.SeatRepository
+	venueRepo        repository.VenueRepository
+	categoryRepo     repository.CategoryRepository
+	inventoryService InventoryService
+	catalogueService CatalogueService
 }
 
-func NewEventService(eventRepo repository.EventRepository) EventService {
-	return &eventService{eventRepo: eventRepo}
+func NewEventService(eventRepo repository.EventRepository, seatRepo repository.SeatRepository, venueRepo repository.VenueRepository, categoryRepo repository.CategoryRepository, inventoryService InventoryService, catalogueService CatalogueService) EventService {
+	return &eventService{eventRepo: eventRepo, seatRepo: seatRepo, venueRepo: venueRepo, categoryRepo: categoryRepo, inventoryService: inventoryService, catalogueService: catalogueService}
 }
 
 func (s *eventService) CreateEvent(ctx context.Context, req *request.CreateEventRequest) (*response.EventResponse, map[string]string, error) {
@@ -43,55 +52,63 @@ func (s *eventService) CreateEvent(ctx context.Context, req *request.CreateEvent
 		return nil, nil, errs.ErrEventTitleAlreadyExists
 	}
 
-	startDate, err := time.Parse("2006-01-02", req.StartDate)
-	if err != nil {
-		return nil, map[string]string{"start_date": "Invalid start date format. Use YYYY-MM-DD"}, nil
+	event := &entity.Event{
+		Organizer:   req.Organizer,
+		Title:       req.Title,
+		Description: req.Description,
+		Timezone:    req.Timezone,
+		Location:    req.Location,
 	}
 
-	endDate, err := time.Parse("2006-01-02", req.EndDate)
-	if err != nil {
-		return nil, map[string]string{"end_date": "Invalid end date format. Use YYYY-MM-DD"}, nil
-	}
+	if req.VenueID != nil {
+		venue, err := s.venueRepo.GetVenueByID(*req.VenueID)
+		if err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return nil, nil, errs.ErrVenueNotFound
+			}
+			return nil, nil, errs.ErrInternalServerError
+		}
+		event.VenueID = req.VenueID
+		event.Venue = venue
 
-	startTime, err := time.Parse("15:04", req.StartTime)
-	if err != nil {
-		return nil, map[string]string{"start_time": "Invalid start time format. Use HH:MM"}, nil
+		if event.Location == "" {
+			event.Location = venue.Name
+		}
+		if event.Timezone == "" {
+			event.Timezone = venue.Timezone
+		}
 	}
 
-	endTime, err := time.Parse("15:04", req.EndTime)
+	startsAt, err := parseEventTime(req.StartsAt, event.Zone())
 	if err != nil {
-		return nil, map[string]string{"end_time": "Invalid end time format. Use HH:MM"}, nil
+		return nil, map[string]string{"starts_at": eventTimeFormatMessage}, nil
 	}
 
-	if startDate.After(endDate) {
-		return nil, map[string]string{"start_date": "Start date must be before end date"}, nil
+	endsAt, err := parseEventTime(req.EndsAt, event.Zone())
+	if err != nil {
+		return nil, map[string]string{"ends_at": eventTimeFormatMessage}, nil
 	}
 
-	if endDate.Before(startDate) {
-		return nil, map[string]string{"end_date": "End date must be after start date"}, nil
+	if !endsAt.After(startsAt) {
+		return nil, map[string]string{"ends_at": "End time must be after start time"}, nil
 	}
 
-	baseDate := time.Date(2025, 1, 1, 0, 0, 0, 0, time.Local)
-	startDateTime := time.Date(baseDate.Year(), baseDate.Month(), baseDate.Day(),
-		startTime.Hour(), startTime.Minute(), 0, 0, time.Local)
-	endDateTime := time.Date(baseDate.Year(), baseDate.Month(), baseDate.Day(),
-		endTime.Hour(), endTime.Minute(), 0, 0, time.Local)
+	event.StartsAt = startsAt
+	event.EndsAt = endsAt
 
-	event := &entity.Event{
-		Organizer:   req.Organizer,
-		Title:       req.Title,
-		Description: req.Description,
-		StartDate:   startDate,
-		EndDate:     endDate,
-		StartTime:   startDateTime,
-		EndTime:     endDateTime,
-		Location:    req.Location,
+	if req.SeatMapID != nil {
+		if err := s.checkSeatMap(*req.SeatMapID, event.VenueID); err != nil {
+			return nil, nil, err
+		}
+		event.SeatMapID = req.SeatMapID
 	}
 
 	if err := s.eventRepo.CreateEvent(event); err != nil {
 		return nil, nil, errs.ErrInternalServerError
 	}
 
+	s.catalogueService.Invalidate(ctx)
+
 	return response.NewEventResponse(event), nil, nil
 }
 
@@ -107,6 +124,62 @@ func (s *eventService) GetEventByID(ctx context.Context, id uuid.UUID) (*respons
 }
 
 func (s *eventService) GetEvents(ctx context.Context, req *request.GetEventsRequest) (*response.EventListResponse, map[string]string, error) {
+	filter, validationErrors := newEventFilter(req)
+	if validationErrors != nil {
+		return nil, validationErrors, nil
+	}
+
+	events, total, err := s.eventRepo.GetEvents(filter)
+	if err != nil {
+		return nil, nil, errs.ErrInternalServerError
+	}
+
+	facets, err := s.eventRepo.GetEventFacets(filter)
+	if err != nil {
+		return nil, nil, errs.ErrInternalServerError
+	}
+
+	events, pagination := newEventPage(events, total, filter)
+
+	eventResponses := make([]*response.EventResponse, 0, len(events))
+	for _, event := range events {
+		eventResponses = append(eventResponses, response.NewEventResponse(event))
+	}
+
+	return &response.EventListResponse{
+		Events:     eventResponses,
+		Pagination: pagination,
+		Facets:     newEventFacetsResponse(facets),
+	}, nil, nil
+}
+
+// newEventPage trims a listing fetched with the filter to one page and
+// describes its pagination in either offset or cursor mode.
+func newEventPage(events []*entity.Event, total int64, filter repository.EventFilter) ([]*entity.Event, *utils_response.Pagination) {
+	if filter.Keyset == nil {
+		return events, &utils_response.Pagination{
+			Page:       filter.Page,
+			Limit:      filter.Limit,
+			TotalPages: int((total + int64(filter.Limit) - 1) / int64(filter.Limit)),
+			Total:      total,
+		}
+	}
+
+	page, next, prev := cursor.Page(events, filter.Keyset, filter.Limit, func(event *entity.Event) (time.Time, uuid.UUID) {
+		return event.CreatedAt, event.ID
+	})
+
+	return page, &utils_response.Pagination{
+		Limit:      filter.Limit,
+		NextCursor: next,
+		PrevCursor: prev,
+	}
+}
+
+// newEventFilter validates a listing request, applies the paging defaults and
+// turns it into a repository filter. Dates may be RFC 3339 timestamps or plain
+// YYYY-MM-DD days in UTC; start_to includes the whole day.
+func newEventFilter(req *request.GetEventsRequest) (repository.EventFilter, map[string]string) {
 	if req.Page < 1 {
 		req.Page = 1
 	}
@@ -119,42 +192,104 @@ func (s *eventService) GetEvents(ctx context.Context, req *request.GetEventsRequ
 
 	validationErrors := validator.HandleValidationErrors(req)
 	if validationErrors != nil {
-		return nil, validationErrors, nil
+		return repository.EventFilter{}, validationErrors
+	}
+
+	filter := repository.EventFilter{
+		Page:      req.Page,
+		Limit:     req.Limit,
+		Search:    req.Search,
+		OrderBy:   req.OrderBy,
+		Organizer: req.Organizer,
+		Location:  req.Location,
+		MinPrice:  req.MinPrice,
+		MaxPrice:  req.MaxPrice,
+		Available: req.Available,
+	}
+
+	for _, value := range req.Tags {
+		for _, slug := range strings.Split(value, ",") {
+			if slug = strings.ToLower(strings.TrimSpace(slug)); slug != "" {
+				filter.Tags = append(filter.Tags, slug)
+			}
+		}
 	}
 
-	events, total, err := s.eventRepo.GetEvents(req.Page, req.Limit, req.Search, req.OrderBy)
-	if err != nil {
-		return nil, nil, errs.ErrInternalServerError
+	if req.VenueID != "" {
+		venueID := uuid.MustParse(req.VenueID)
+		filter.VenueID = &venueID
 	}
 
-	if len(events) == 0 {
-		return &response.EventListResponse{
-			Events: []*response.EventResponse{},
-			Pagination: &utils_response.Pagination{
-				Page:       req.Page,
-				Limit:      req.Limit,
-				TotalPages: 0,
-				Total:      total,
-			},
-		}, nil, nil
+	if req.StartFrom != "" {
+		startFrom, err := parseFilterTime(req.StartFrom, false)
+		if err != nil {
+			return repository.EventFilter{}, map[string]string{"start_from": "Invalid date format. Use YYYY-MM-DD or RFC 3339"}
+		}
+		filter.StartFrom = &startFrom
 	}
 
-	var eventResponses []*response.EventResponse
-	for _, event := range events {
-		eventResponses = append(eventResponses, response.NewEventResponse(event))
+	if req.StartTo != "" {
+		startTo, err := parseFilterTime(req.StartTo, true)
+		if err != nil {
+			return repository.EventFilter{}, map[string]string{"start_to": "Invalid date format. Use YYYY-MM-DD or RFC 3339"}
+		}
+		filter.StartTo = &startTo
 	}
 
-	totalPages := int((total + int64(req.Limit) - 1) / int64(req.Limit))
+	if filter.StartFrom != nil && filter.StartTo != nil && !filter.StartTo.After(*filter.StartFrom) {
+		return repository.EventFilter{}, map[string]string{"start_to": "End of range must be after its start"}
+	}
 
-	return &response.EventListResponse{
-		Events: eventResponses,
-		Pagination: &utils_response.Pagination{
-			Page:       req.Page,
-			Limit:      req.Limit,
-			TotalPages: totalPages,
-			Total:      total,
+	if req.MinPrice != nil && req.MaxPrice != nil && *req.MaxPrice < *req.MinPrice {
+		return repository.EventFilter{}, map[string]string{"max_price": "Maximum price must not be below the minimum price"}
+	}
+
+	keyset, validationErrors := newCursor(req.Pagination, req.Cursor)
+	if validationErrors == nil {
+		validationErrors = checkCursorOrdering(keyset, req.OrderBy)
+	}
+	if validationErrors != nil {
+		return repository.EventFilter{}, validationErrors
+	}
+	filter.Keyset = keyset
+
+	return filter, nil
+}
+
+// parseFilterTime parses an RFC 3339 timestamp or a date. With endOfDay a
+// date yields the start of the following day, for use as an exclusive bound.
+func parseFilterTime(value string, endOfDay bool) (time.Time, error) {
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t.UTC(), nil
+	}
+
+	date, err := time.Parse("2006-01-02", value)
+	if err != nil {
+		return time.Time{}, err
+	}
+	if endOfDay {
+		date = date.AddDate(0, 0, 1)
+	}
+	return date, nil
+}
+
+func newEventFacetsResponse(facets *repository.EventFacets) *response.EventFacetsResponse {
+	newCounts := func(counts []repository.FacetCount) []*response.FacetCountResponse {
+		responses := make([]*response.FacetCountResponse, 0, len(counts))
+		for _, count := range counts {
+			responses = append(responses, &response.FacetCountResponse{Value: count.Value, Count: count.Count})
+		}
+		return responses
+	}
+
+	return &response.EventFacetsResponse{
+		Organizers: newCounts(facets.Organizers),
+		Locations:  newCounts(facets.Locations),
+		Availability: &response.AvailabilityFacetResponse{
+			Available: facets.Available,
+			SoldOut:   facets.SoldOut,
 		},
-	}, nil, nil
+	}
 }
 
 func (s *eventService) UpdateEvent(ctx context.Context, id uuid.UUID, req *request.UpdateEventRequest) (*response.EventResponse, map[string]string, error) {
@@ -176,6 +311,21 @@ func (s *eventService) UpdateEvent(ctx context.Context, id uuid.UUID, req *reque
 		return nil, nil, errs.ErrInternalServerError
 	}
 
+	if event.Status == "cancelled" {
+		return nil, nil, errs.ErrEventCancelled
+	}
+
+	if req.Scope == "future" {
+		if event.SeriesID == nil {
+			return nil, nil, errs.ErrEventNotInSeries
+		}
+		if req.Title != "" || req.VenueID != nil || req.SeatMapID != nil {
+			return nil, map[string]string{"scope": "Title, venue and seat map can only be changed for a single occurrence"}, nil
+		}
+	}
+
+	original := *event
+
 	if req.Organizer != "" {
 		event.Organizer = req.Organizer
 	}
@@ -185,61 +335,308 @@ func (s *eventService) UpdateEvent(ctx context.Context, id uuid.UUID, req *reque
 	if req.Description != "" {
 		event.Description = req.Description
 	}
-	if req.StartDate != "" {
-		parsedDate, err := time.Parse("2006-01-02", req.StartDate)
+	if req.Status != "" {
+		event.Status = req.Status
+	}
+	if req.Timezone != "" {
+		event.Timezone = req.Timezone
+	}
+	if req.StartsAt != "" {
+		startsAt, err := parseEventTime(req.StartsAt, event.Zone())
 		if err != nil {
-			return nil, map[string]string{"start_date": "Invalid start date format. Use YYYY-MM-DD"}, nil
+			return nil, map[string]string{"starts_at": eventTimeFormatMessage}, nil
 		}
-		event.StartDate = parsedDate
-		if event.StartDate.After(event.EndDate) {
-			return nil, map[string]string{"start_date": "Start date must be before end date"}, nil
+		event.StartsAt = startsAt
+	}
+	if req.EndsAt != "" {
+		endsAt, err := parseEventTime(req.EndsAt, event.Zone())
+		if err != nil {
+			return nil, map[string]string{"ends_at": eventTimeFormatMessage}, nil
 		}
+		event.EndsAt = endsAt
+	}
+	if !event.EndsAt.After(event.StartsAt) {
+		return nil, map[string]string{"ends_at": "End time must be after start time"}, nil
 	}
-	if req.EndDate != "" {
-		parsedDate, err := time.Parse("2006-01-02", req.EndDate)
+
+	if req.Location != "" {
+		event.Location = req.Location
+	}
+
+	// Moving an event to another venue requires its tickets to fit there.
+	if req.VenueID != nil && (event.VenueID == nil || *event.VenueID != *req.VenueID) {
+		venue, err := s.venueRepo.GetVenueByID(*req.VenueID)
 		if err != nil {
-			return nil, map[string]string{"end_date": "Invalid end date format. Use YYYY-MM-DD"}, nil
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return nil, nil, errs.ErrVenueNotFound
+			}
+			return nil, nil, errs.ErrInternalServerError
 		}
-		event.EndDate = parsedDate
-		if event.EndDate.Before(event.StartDate) {
-			return nil, map[string]string{"end_date": "End date must be after start date"}, nil
+
+		peak, err := s.categoryRepo.GetPeakAllocation([]uuid.UUID{event.ID})
+		if err != nil {
+			return nil, nil, errs.ErrInternalServerError
 		}
+
+		if peak > venue.Capacity {
+			return nil, nil, errs.ErrVenueCapacityExceeded
+		}
+
+		event.VenueID = req.VenueID
+		event.Venue = venue
 	}
-	if req.StartTime != "" {
-		parsedTime, err := time.Parse("15:04", req.StartTime)
+
+	// The seat map can only change while no seats are assigned to categories.
+	if req.SeatMapID != nil && (event.SeatMapID == nil || *event.SeatMapID != *req.SeatMapID) {
+		if err := s.checkSeatMap(*req.SeatMapID, event.VenueID); err != nil {
+			return nil, nil, err
+		}
+
+		assignedSeats, err := s.seatRepo.CountEventSeats(event.ID)
 		if err != nil {
-			return nil, map[string]string{"start_time": "Invalid start time format. Use HH:MM"}, nil
+			return nil, nil, errs.ErrInternalServerError
+		}
+
+		if assignedSeats > 0 {
+			return nil, nil, errs.ErrSeatMapLocked
 		}
-		baseDate := time.Date(2025, 1, 1, 0, 0, 0, 0, time.Local)
-		event.StartTime = time.Date(baseDate.Year(), baseDate.Month(), baseDate.Day(),
-			parsedTime.Hour(), parsedTime.Minute(), 0, 0, time.Local)
+
+		event.SeatMapID = req.SeatMapID
 	}
-	if req.EndTime != "" {
-		parsedTime, err := time.Parse("15:04", req.EndTime)
+
+	if event.Organizer == "" && event.Title == "" && event.Description == "" && event.StartsAt.Equal(event.EndsAt) && event.Location == "" {
+		return nil, nil, errs.ErrAtleastOneField
+	}
+
+	if req.Scope != "future" {
+		err = s.eventRepo.UpdateEvent(event)
 		if err != nil {
-			return nil, map[string]string{"end_time": "Invalid end time format. Use HH:MM"}, nil
+			return nil, nil, errs.ErrInternalServerError
 		}
-		baseDate := time.Date(2025, 1, 1, 0, 0, 0, 0, time.Local)
-		event.EndTime = time.Date(baseDate.Year(), baseDate.Month(), baseDate.Day(),
-			parsedTime.Hour(), parsedTime.Minute(), 0, 0, time.Local)
+
+		s.catalogueService.Invalidate(ctx)
+
+		return response.NewEventResponse(event), nil, nil
 	}
 
-	if req.Location != "" {
-		event.Location = req.Location
+	// Later occurrences take over the changed fields, and their start and end
+	// move by the same wall clock amount as this occurrence's did.
+	siblings, err := s.eventRepo.GetSeriesEvents(*event.SeriesID, original.StartsAt)
+	if err != nil {
+		return nil, nil, errs.ErrInternalServerError
 	}
 
-	if event.Organizer == "" && event.Title == "" && event.Description == "" && event.StartDate.Equal(event.EndDate) && event.StartTime.Equal(event.EndTime) && event.Location == "" {
-		return nil, nil, errs.ErrAtleastOneField
+	startShift := wallClock(event.StartsAt, event.Zone()).Sub(wallClock(original.StartsAt, original.Zone()))
+	endShift := wallClock(event.EndsAt, event.Zone()).Sub(wallClock(original.EndsAt, original.Zone()))
+
+	events := []*entity.Event{event}
+	for _, sibling := range siblings {
+		if sibling.ID == event.ID || sibling.Status == "cancelled" {
+			continue
+		}
+
+		from := sibling.Zone()
+		if req.Organizer != "" {
+			sibling.Organizer = req.Organizer
+		}
+		if req.Description != "" {
+			sibling.Description = req.Description
+		}
+		if req.Location != "" {
+			sibling.Location = req.Location
+		}
+		if req.Status != "" {
+			sibling.Status = req.Status
+		}
+		if req.Timezone != "" {
+			sibling.Timezone = req.Timezone
+		}
+		sibling.StartsAt = shiftWallClock(sibling.StartsAt, from, sibling.Zone(), startShift)
+		sibling.EndsAt = shiftWallClock(sibling.EndsAt, from, sibling.Zone(), endShift)
+
+		events = append(events, sibling)
+	}
+
+	if err := s.eventRepo.UpdateEvents(events); err != nil {
+		return nil, nil, errs.ErrInternalServerError
 	}
 
-	err = s.eventRepo.UpdateEvent(event)
+	s.catalogueService.Invalidate(ctx)
+
+	return response.NewEventResponse(event), nil, nil
+}
+
+// CancelEvent cancels an event, or with the "future" scope also every later
+// occurrence of its series. Cancelled events stay visible but cannot be sold.
+func (s *eventService) CancelEvent(ctx context.Context, id uuid.UUID, req *request.CancelEventRequest) (*response.EventResponse, map[string]string, error) {
+	validationErrors := validator.HandleValidationErrors(req)
+	if validationErrors != nil {
+		return nil, validationErrors, nil
+	}
+
+	event, err := s.eventRepo.GetEventByID(id)
 	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil, errs.ErrEventNotFound
+		}
 		return nil, nil, errs.ErrInternalServerError
 	}
 
+	if event.Status == "cancelled" {
+		return nil, nil, errs.ErrEventCancelled
+	}
+
+	event.Status = "cancelled"
+	events := []*entity.Event{event}
+
+	if req.Scope == "future" {
+		if event.SeriesID == nil {
+			return nil, nil, errs.ErrEventNotInSeries
+		}
+
+		siblings, err := s.eventRepo.GetSeriesEvents(*event.SeriesID, event.StartsAt)
+		if err != nil {
+			return nil, nil, errs.ErrInternalServerError
+		}
+
+		for _, sibling := range siblings {
+			if sibling.ID == event.ID || sibling.Status == "cancelled" {
+				continue
+			}
+			sibling.Status = "cancelled"
+			events = append(events, sibling)
+		}
+	}
+
+	if err := s.eventRepo.UpdateEvents(events); err != nil {
+		return nil, nil, errs.ErrInternalServerError
+	}
+
+	s.catalogueService.Invalidate(ctx)
+
 	return response.NewEventResponse(event), nil, nil
 }
 
+// CloneEvent copies an event and its categories into a new draft event. The
+// schedule, sale windows and price tiers move by the requested number of days
+// on the wall clock, and every category starts again with its full stock.
+func (s *eventService) CloneEvent(ctx context.Context, id uuid.UUID, req *request.CloneEventRequest) (*response.EventResponse, map[string]string, error) {
+	validationErrors := validator.HandleValidationErrors(req)
+	if validationErrors != nil {
+		return nil, validationErrors, nil
+	}
+
+	source, err := s.eventRepo.GetEventByID(id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil, errs.ErrEventNotFound
+		}
+		return nil, nil, errs.ErrInternalServerError
+	}
+
+	title, _ := s.eventRepo.GetEventByTitle(req.Title)
+	if title != nil {
+		return nil, nil, errs.ErrEventTitleAlreadyExists
+	}
+
+	zone := source.Zone()
+	offset := time.Duration(req.OffsetDays) * 24 * time.Hour
+
+	clone := &entity.Event{
+		Organizer:   source.Organizer,
+		Title:       req.Title,
+		Description: source.Description,
+		StartsAt:    shiftWallClock(source.StartsAt, zone, zone, offset),
+		EndsAt:      shiftWallClock(source.EndsAt, zone, zone, offset),
+		Timezone:    source.Timezone,
+		Location:    source.Location,
+		Status:      "draft",
+		VenueID:     source.VenueID,
+		SeatMapID:   source.SeatMapID,
+		Venue:       source.Venue,
+	}
+
+	categories, err := s.eventRepo.CloneEvent(source.ID, clone, req.OffsetDays)
+	if err != nil {
+		return nil, nil, errs.ErrInternalServerError
+	}
+
+	for _, category := range categories {
+		if category.InventoryStrategy != "redis" {
+			continue
+		}
+		if err := s.inventoryService.SetStock(ctx, category.ID, category.Quantity); err != nil {
+			return nil, nil, errs.ErrInternalServerError
+		}
+	}
+
+	for _, category := range categories {
+		clone.Categories = append(clone.Categories, *category)
+	}
+
+	return response.NewEventResponse(clone), nil, nil
+}
+
+// wallClock returns the wall clock reading of t in loc as a UTC time, so two
+// readings can be subtracted without daylight saving getting in the way.
+func wallClock(t time.Time, loc *time.Location) time.Time {
+	local := t.In(loc)
+	return time.Date(local.Year(), local.Month(), local.Day(), local.Hour(), local.Minute(), local.Second(), 0, time.UTC)
+}
+
+// shiftWallClock moves t by d on the wall clock, reading it in from and
+// placing the result in to.
+func shiftWallClock(t time.Time, from, to *time.Location, d time.Duration) time.Time {
+	wall := wallClock(t, from).Add(d)
+	return time.Date(wall.Year(), wall.Month(), wall.Day(), wall.Hour(), wall.Minute(), wall.Second(), 0, to).UTC()
+}
+
+const eventTimeFormatMessage = "Invalid time format. Use RFC 3339 (2006-01-02T15:04:05+07:00) or a local date time (2006-01-02T15:04)"
+
+// localTimeLayouts are the accepted layouts for times without an offset, which
+// are read as wall clock time in the event's time zone.
+var localTimeLayouts = []string{
+	"2006-01-02T15:04:05",
+	"2006-01-02T15:04",
+	"2006-01-02 15:04:05",
+	"2006-01-02 15:04",
+}
+
+// parseEventTime parses an RFC 3339 timestamp or a local date time in loc and
+// returns the instant in UTC.
+func parseEventTime(value string, loc *time.Location) (time.Time, error) {
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t.UTC(), nil
+	}
+
+	var err error
+	for _, layout := range localTimeLayouts {
+		var t time.Time
+		if t, err = time.ParseInLocation(layout, value, loc); err == nil {
+			return t.UTC(), nil
+		}
+	}
+	return time.Time{}, err
+}
+
+// checkSeatMap makes sure the seat map exists and, when it is tied to a venue,
+// that it is the event's venue.
+func (s *eventService) checkSeatMap(seatMapID uuid.UUID, venueID *uuid.UUID) error {
+	seatMap, err := s.seatRepo.GetSeatMapByID(seatMapID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return errs.ErrSeatMapNotFound
+		}
+		return errs.ErrInternalServerError
+	}
+
+	if seatMap.VenueID != nil && (venueID == nil || *seatMap.VenueID != *venueID) {
+		return errs.ErrSeatMapVenueMismatch
+	}
+
+	return nil
+}
+
 func (s *eventService) DeleteEvent(ctx context.Context, id uuid.UUID) error {
 	_, err := s.eventRepo.GetEventByID(id)
 	if err != nil {
@@ -253,5 +650,7 @@ func (s *eventService) DeleteEvent(ctx context.Context, id uuid.UUID) error {
 		return errs.ErrInternalServerError
 	}
 
+	s.catalogueService.Invalidate(ctx)
+
 	return nil
 }
@@ -0,0 +1,205 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"log"
+	"ticert/entity"
+	"ticert/repository"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	inventoryHoldTTL           = 15 * time.Minute
+	inventoryReconcileInterval = 30 * time.Second
+)
+
+// InventoryService manages stock for categories using the "redis" inventory
+// strategy. Orders reserve stock as a hold in Redis; holds are confirmed when
+// the order is paid, released when it is cancelled and expire otherwise.
+// Category.Quantity is reconciled from Redis in the background.
+type InventoryService interface {
+	Reserve(ctx context.Context, holdID uuid.UUID, categories []*entity.Category, quantities map[uuid.UUID]int) (bool, error)
+	Release(ctx context.Context, holdID uuid.UUID, categoryIDs []uuid.UUID) error
+	Confirm(ctx context.Context, holdID uuid.UUID, categoryIDs []uuid.UUID) (bool, error)
+	SetStock(ctx context.Context, categoryID uuid.UUID, quantity int) error
+	AdjustStock(ctx context.Context, categoryID uuid.UUID, delta, quantity int) error
+	Detach(ctx context.Context, categoryID uuid.UUID) error
+	Take(ctx context.Context, category *entity.Category, quantity int) (int, error)
+	Return(ctx context.Context, category *entity.Category, quantity int) error
+	Reconcile(ctx context.Context) error
+	StartReconciler(ctx context.Context)
+}
+
+type inventoryService struct {
+	inventoryRepo repository.InventoryRepository
+	categoryRepo  repository.CategoryRepository
+	orderRepo     repository.OrderRepository
+}
+
+func NewInventoryService(inventoryRepo repository.InventoryRepository, categoryRepo repository.CategoryRepository, orderRepo repository.OrderRepository) InventoryService {
+	return &inventoryService{
+		inventoryRepo: inventoryRepo,
+		categoryRepo:  categoryRepo,
+		orderRepo:     orderRepo,
+	}
+}
+
+// Reserve places a single hold across all given categories. Either every
+// category has enough stock and is decremented, or nothing is reserved.
+func (s *inventoryService) Reserve(ctx context.Context, holdID uuid.UUID, categories []*entity.Category, quantities map[uuid.UUID]int) (bool, error) {
+	for _, category := range categories {
+		if err := s.inventoryRepo.PrimeStock(category.ID, category.Quantity); err != nil {
+			return false, err
+		}
+	}
+
+	result, err := s.inventoryRepo.Reserve(holdID, quantities, time.Now().Add(inventoryHoldTTL))
+	if err != nil {
+		return false, err
+	}
+
+	if result == repository.ReserveNotPrimed {
+		return false, errors.New("inventory stock is not primed")
+	}
+
+	return result == repository.ReserveOK, nil
+}
+
+func (s *inventoryService) Release(ctx context.Context, holdID uuid.UUID, categoryIDs []uuid.UUID) error {
+	for _, categoryID := range categoryIDs {
+		if _, err := s.inventoryRepo.Release(categoryID, holdID); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Confirm turns the hold into a permanent sale in every category at once. It
+// reports false, removing nothing, when the hold no longer exists in one of
+// the categories, meaning it has already expired there.
+func (s *inventoryService) Confirm(ctx context.Context, holdID uuid.UUID, categoryIDs []uuid.UUID) (bool, error) {
+	return s.inventoryRepo.Confirm(holdID, categoryIDs)
+}
+
+func (s *inventoryService) SetStock(ctx context.Context, categoryID uuid.UUID, quantity int) error {
+	if err := s.inventoryRepo.SetStock(categoryID, quantity); err != nil {
+		return err
+	}
+	return s.categoryRepo.SyncStock(categoryID, quantity)
+}
+
+// AdjustStock changes the Redis stock of a category by delta, the difference
+// between its new and previous quantity, rather than overwriting it: the live
+// counter is ahead of the category's quantity and excludes the units held by
+// pending orders. A category without Redis stock is primed with quantity.
+func (s *inventoryService) AdjustStock(ctx context.Context, categoryID uuid.UUID, delta, quantity int) error {
+	stock, err := s.inventoryRepo.AdjustStock(categoryID, delta, quantity)
+	if err != nil {
+		return err
+	}
+	return s.categoryRepo.SyncStock(categoryID, max(stock, 0))
+}
+
+// Detach writes the final Redis stock back to the category and removes the
+// Redis keys, used when a category switches back to the database strategy.
+func (s *inventoryService) Detach(ctx context.Context, categoryID uuid.UUID) error {
+	stock, exists, err := s.inventoryRepo.GetStock(categoryID)
+	if err != nil {
+		return err
+	}
+
+	if exists {
+		if err := s.categoryRepo.SyncStock(categoryID, max(stock, 0)); err != nil {
+			return err
+		}
+	}
+
+	return s.inventoryRepo.ClearStock(categoryID)
+}
+
+// Take removes up to quantity units from the Redis stock without placing a
+// hold, used to move stock into waitlist claims. It returns the units taken.
+func (s *inventoryService) Take(ctx context.Context, category *entity.Category, quantity int) (int, error) {
+	if err := s.inventoryRepo.PrimeStock(category.ID, category.Quantity); err != nil {
+		return 0, err
+	}
+	return s.inventoryRepo.TakeStock(category.ID, quantity)
+}
+
+// Return puts units back on general sale, used for refunds and for waitlist
+// claims that were not taken up.
+func (s *inventoryService) Return(ctx context.Context, category *entity.Category, quantity int) error {
+	if quantity <= 0 {
+		return nil
+	}
+	if err := s.inventoryRepo.PrimeStock(category.ID, category.Quantity); err != nil {
+		return err
+	}
+	return s.inventoryRepo.ReturnStock(category.ID, quantity)
+}
+
+func (s *inventoryService) Reconcile(ctx context.Context) error {
+	categories, err := s.categoryRepo.GetCategoriesByInventoryStrategy("redis")
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	for _, category := range categories {
+		expiredHolds, err := s.inventoryRepo.ReleaseExpired(category.ID, now)
+		if err != nil {
+			log.Printf("Failed to release expired holds for category %s: %v", category.ID, err)
+			continue
+		}
+
+		if _, err := s.orderRepo.ExpireOrders(expiredHolds); err != nil {
+			log.Printf("Failed to expire orders for category %s: %v", category.ID, err)
+		}
+
+		stock, exists, err := s.inventoryRepo.GetStock(category.ID)
+		if err != nil {
+			log.Printf("Failed to read stock for category %s: %v", category.ID, err)
+			continue
+		}
+
+		if !exists {
+			if err := s.inventoryRepo.PrimeStock(category.ID, category.Quantity); err != nil {
+				log.Printf("Failed to prime stock for category %s: %v", category.ID, err)
+			}
+			continue
+		}
+
+		// Stock below zero is a reduction still waiting for held units.
+		stock = max(stock, 0)
+
+		soldOut := category.Status == "sold"
+		if stock == category.Quantity && soldOut == (stock <= 0) {
+			continue
+		}
+
+		if err := s.categoryRepo.SyncStock(category.ID, stock); err != nil {
+			log.Printf("Failed to reconcile stock for category %s: %v", category.ID, err)
+		}
+	}
+
+	return nil
+}
+
+func (s *inventoryService) StartReconciler(ctx context.Context) {
+	ticker := time.NewTicker(inventoryReconcileInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := s.Reconcile(ctx); err != nil {
+				log.Printf("Inventory reconciliation failed: %v", err)
+			}
+		}
+	}
+}
@@ -0,0 +1,31 @@
+package service
+
+import (
+	"context"
+	"log"
+	"ticert/entity"
+)
+
+// Notifier tells users about events that need their attention.
+type Notifier interface {
+	NotifyWaitlistClaim(ctx context.Context, entry *entity.WaitlistEntry, category *entity.Category) error
+}
+
+type logNotifier struct{}
+
+// NewLogNotifier returns a Notifier that writes notifications to the log,
+// used until a mail or push provider is configured.
+func NewLogNotifier() Notifier {
+	return &logNotifier{}
+}
+
+func (n *logNotifier) NotifyWaitlistClaim(ctx context.Context, entry *entity.WaitlistEntry, category *entity.Category) error {
+	email := ""
+	if entry.User != nil {
+		email = entry.User.Email
+	}
+
+	log.Printf("Waitlist claim for %s (user %s): %d ticket(s) of %q held until %s",
+		email, entry.UserID, entry.Quantity, category.Name, entry.ClaimExpiresAt.Format("2006-01-02 15:04:05 MST"))
+	return nil
+}
@@ -3,14 +3,22 @@ package service
 import (
 	"context"
 	"errors"
+	"io"
+	"log"
+	"slices"
+	"strings"
 	"ticert/dto/request"
 	"ticert/dto/response"
 	"ticert/entity"
+	"ticert/models"
 	"ticert/repository"
 	"ticert/utils/auth"
+	"ticert/utils/cursor"
 	"ticert/utils/errs"
+	"ticert/utils/export"
 	utils_response "ticert/utils/response"
 	"ticert/utils/validator"
+	"time"
 
 	"github.com/google/uuid"
 	"gorm.io/gorm"
@@ -20,20 +28,25 @@ type OrderService interface {
 	CreateOrder(ctx context.Context, req *request.OrderRequest, userID uuid.UUID) (*response.OrderResponse, map[string]string, error)
 	GetOrders(ctx context.Context, userID uuid.UUID, req *request.GetOrdersRequest) (*response.OrderListResponse, map[string]string, error)
 	GetOrdersAdmin(ctx context.Context, req *request.GetOrdersRequestAdmin) (*response.OrderListResponse, map[string]string, error)
+	ExportOrdersAdmin(ctx context.Context, req *request.GetOrdersRequestAdmin, w io.Writer) (map[string]string, error)
 	GetOrderById(ctx context.Context, orderID uuid.UUID, userCtx *auth.ContextKey) (*response.OrderResponse, error)
 	CancelOrder(ctx context.Context, orderID uuid.UUID, userID uuid.UUID) error
 	VerifyOrderStatus(ctx context.Context, orderID uuid.UUID) error
 	VerifyTicket(ctx context.Context, ticketCode string) error
+	RefundOrder(ctx context.Context, orderID uuid.UUID) error
+	ExpireStaleOrders(ctx context.Context, before time.Time) (int, error)
 }
 
 type orderService struct {
 	orderRepository    repository.OrderRepository
 	userRepository     repository.UserRepository
 	categoryRepository repository.CategoryRepository
+	inventoryService   InventoryService
+	waitlistService    WaitlistService
 }
 
-func NewOrderService(orderRepository repository.OrderRepository, userRepository repository.UserRepository, categoryRepository repository.CategoryRepository) OrderService {
-	return &orderService{orderRepository: orderRepository, userRepository: userRepository, categoryRepository: categoryRepository}
+func NewOrderService(orderRepository repository.OrderRepository, userRepository repository.UserRepository, categoryRepository repository.CategoryRepository, inventoryService InventoryService, waitlistService WaitlistService) OrderService {
+	return &orderService{orderRepository: orderRepository, userRepository: userRepository, categoryRepository: categoryRepository, inventoryService: inventoryService, waitlistService: waitlistService}
 }
 
 func (s *orderService) CreateOrder(ctx context.Context, req *request.OrderRequest, userID uuid.UUID) (*response.OrderResponse, map[string]string, error) {
@@ -47,63 +60,222 @@ func (s *orderService) CreateOrder(ctx context.Context, req *request.OrderReques
 		return nil, nil, err
 	}
 
-	category, err := s.categoryRepository.GetCategoryByID(req.CategoryID)
-	if err != nil {
-		return nil, nil, err
+	order := &entity.Order{
+		ID:        uuid.New(),
+		UserID:    user.ID,
+		Status:    "pending",
+		InvoiceID: uuid.New().String()[26:],
 	}
 
-	stockAvailable, err := s.categoryRepository.CheckStock(req.CategoryID, req.Quantity)
-	if err != nil {
+	var redisCategories []*entity.Category
+	redisQuantities := make(map[uuid.UUID]int)
+
+	for _, itemReq := range req.Items {
+		category, err := s.categoryRepository.GetCategoryByID(itemReq.CategoryID)
+		if err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return nil, nil, errs.ErrCategoryNotFound
+			}
+			return nil, nil, errs.ErrInternalServerError
+		}
+
+		if order.EventID == uuid.Nil {
+			order.EventID = category.EventID
+		} else if order.EventID != category.EventID {
+			return nil, nil, errs.ErrOrderEventMismatch
+		}
+
+		if err := s.checkPurchaseRules(category, user.ID, itemReq.Quantity); err != nil {
+			return nil, nil, err
+		}
+
+		// A user with an open waitlist claim buys from the units held for
+		// them rather than the general stock.
+		claim, err := s.waitlistService.GetClaim(ctx, category.ID, user.ID)
+		if err != nil {
+			return nil, nil, errs.ErrInternalServerError
+		}
+
+		var waitlistEntryID *uuid.UUID
+		if claim != nil {
+			if itemReq.Quantity > claim.Quantity {
+				return nil, nil, errs.ErrWaitlistClaimExceeded
+			}
+			waitlistEntryID = &claim.ID
+		}
+
+		if category.SeatingMode == "reserved" {
+			if len(itemReq.SeatIDs) != itemReq.Quantity {
+				return nil, nil, errs.ErrSeatSelectionMismatch
+			}
+		} else if len(itemReq.SeatIDs) > 0 {
+			return nil, nil, errs.ErrSeatingNotReserved
+		}
+
+		orderDetails, err := buildOrderDetails(&itemReq, req.SameAsOrderer)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		// The item is priced when the order is stored, under the category
+		// lock, and the price is locked onto it so later tier changes do not
+		// affect this order.
+		order.OrderItems = append(order.OrderItems, &entity.OrderItem{
+			CategoryID:      category.ID,
+			WaitlistEntryID: waitlistEntryID,
+			Quantity:        itemReq.Quantity,
+			OrderDetails:    orderDetails,
+		})
+		order.Quantity += itemReq.Quantity
+
+		if category.InventoryStrategy == "redis" && claim == nil {
+			redisCategories = append(redisCategories, category)
+			redisQuantities[category.ID] = itemReq.Quantity
+		}
+	}
+
+	if len(redisCategories) > 0 {
+		reserved, err := s.inventoryService.Reserve(ctx, order.ID, redisCategories, redisQuantities)
+		if err != nil {
+			return nil, nil, errs.ErrInternalServerError
+		}
+
+		if !reserved {
+			return nil, nil, errs.ErrStockNotAvailable
+		}
+	}
+
+	if err := s.orderRepository.CreateOrder(order); err != nil {
+		if len(redisCategories) > 0 {
+			s.inventoryService.Release(ctx, order.ID, categoryIDs(redisCategories))
+		}
+
+		if errors.Is(err, repository.ErrInsufficientStock) {
+			return nil, nil, errs.ErrStockNotAvailable
+		}
+		if errors.Is(err, repository.ErrPurchaseLimit) {
+			return nil, nil, errs.ErrPurchaseLimitExceeded
+		}
+		if errors.Is(err, repository.ErrWaitlistClaimExpired) {
+			return nil, nil, errs.ErrWaitlistClaimExpired
+		}
+		if errors.Is(err, repository.ErrSeatUnavailable) {
+			return nil, nil, errs.ErrSeatUnavailable
+		}
 		return nil, nil, err
 	}
 
-	if !stockAvailable {
-		return nil, nil, errs.ErrStockNotAvailable
+	// Units of a claim the order did not use go to the next person in line.
+	for _, item := range order.OrderItems {
+		if item.WaitlistEntryID != nil {
+			s.offerToWaitlist(ctx, item.CategoryID)
+		}
 	}
 
-	order := &entity.Order{
-		UserID:     user.ID,
-		CategoryID: req.CategoryID,
-		Quantity:   req.Quantity,
-		TotalPrice: float64(req.Quantity) * category.Price,
-		Status:     "pending",
-		InvoiceID:  uuid.New().String()[26:],
+	return response.NewOrderResponse(order), nil, nil
+}
+
+// checkPurchaseRules enforces the category's sale window and purchase limits.
+// Limits of zero mean unlimited. The per-user limit is checked again when the
+// order is stored, under the category lock, as parallel orders of one user
+// could all pass it here.
+func (s *orderService) checkPurchaseRules(category *entity.Category, userID uuid.UUID, quantity int) error {
+	if category.Event != nil && category.Event.Status != "published" {
+		return errs.ErrEventNotOnSale
+	}
+
+	switch category.SaleStatus(time.Now()) {
+	case "upcoming":
+		return errs.ErrSaleNotStarted
+	case "ended":
+		return errs.ErrSaleEnded
+	}
+
+	if quantity < category.MinPerOrder {
+		return errs.ErrBelowMinimumPerOrder
 	}
 
+	if category.MaxPerOrder > 0 && quantity > category.MaxPerOrder {
+		return errs.ErrAboveMaximumPerOrder
+	}
+
+	if category.MaxPerUser > 0 {
+		purchased, err := s.orderRepository.CountUserTickets(userID, category.ID)
+		if err != nil {
+			return errs.ErrInternalServerError
+		}
+
+		if purchased+int64(quantity) > int64(category.MaxPerUser) {
+			return errs.ErrPurchaseLimitExceeded
+		}
+	}
+
+	return nil
+}
+
+// buildOrderDetails creates one ticket per unit of the item. With
+// sameAsOrderer every ticket copies the first holder in the request. Selected
+// seats are given to the tickets in the order they were requested.
+func buildOrderDetails(itemReq *request.OrderItemRequest, sameAsOrderer bool) ([]*entity.OrderDetail, error) {
 	var orderDetails []*entity.OrderDetail
 
-	if req.SameAsOrderer {
-		if len(req.OrderDetails) > 0 {
-			firstDetail := req.OrderDetails[0]
-			for i := 0; i < req.Quantity; i++ {
-				orderDetail := &entity.OrderDetail{
-					TicketCode:     uuid.New().String()[26:],
-					FullName:       firstDetail.FullName,
-					IdentityNumber: firstDetail.IdentityNumber,
-				}
-				orderDetails = append(orderDetails, orderDetail)
-			}
+	if sameAsOrderer {
+		firstDetail := itemReq.OrderDetails[0]
+		for i := 0; i < itemReq.Quantity; i++ {
+			orderDetails = append(orderDetails, &entity.OrderDetail{
+				TicketCode:     uuid.New().String()[26:],
+				FullName:       firstDetail.FullName,
+				IdentityNumber: firstDetail.IdentityNumber,
+			})
 		}
 	} else {
-		if len(req.OrderDetails) != req.Quantity {
-			return nil, nil, errs.ErrQuantityNotMatch
+		if len(itemReq.OrderDetails) != itemReq.Quantity {
+			return nil, errs.ErrQuantityNotMatch
 		}
 
-		orderDetails = make([]*entity.OrderDetail, len(req.OrderDetails))
-		for i, detail := range req.OrderDetails {
-			orderDetails[i] = &entity.OrderDetail{
+		for _, detail := range itemReq.OrderDetails {
+			orderDetails = append(orderDetails, &entity.OrderDetail{
 				TicketCode:     uuid.New().String()[26:],
 				FullName:       detail.FullName,
 				IdentityNumber: detail.IdentityNumber,
-			}
+			})
 		}
 	}
 
-	if err := s.orderRepository.CreateOrder(order, orderDetails, req.CategoryID, req.Quantity); err != nil {
-		return nil, nil, err
+	for i := range itemReq.SeatIDs {
+		orderDetails[i].SeatID = &itemReq.SeatIDs[i]
 	}
 
-	return response.NewOrderResponse(order), nil, nil
+	return orderDetails, nil
+}
+
+// offerToWaitlist hands returned stock to the category's waitlist. Failures
+// are only logged: the waitlist sweeper retries them.
+func (s *orderService) offerToWaitlist(ctx context.Context, categoryID uuid.UUID) {
+	if err := s.waitlistService.Offer(ctx, categoryID); err != nil {
+		log.Printf("Failed to offer waitlist stock for category %s: %v", categoryID, err)
+	}
+}
+
+// redisCategoryIDs lists the categories of an order whose stock is held in
+// Redis. Items bought through a waitlist claim hold no Redis stock.
+// Items split across price tiers share a category, which is listed once.
+func redisCategoryIDs(order *entity.Order) []uuid.UUID {
+	var ids []uuid.UUID
+	for _, item := range order.OrderItems {
+		if item.Category != nil && item.Category.InventoryStrategy == "redis" && item.WaitlistEntryID == nil && !slices.Contains(ids, item.CategoryID) {
+			ids = append(ids, item.CategoryID)
+		}
+	}
+	return ids
+}
+
+func categoryIDs(categories []*entity.Category) []uuid.UUID {
+	ids := make([]uuid.UUID, len(categories))
+	for i, category := range categories {
+		ids[i] = category.ID
+	}
+	return ids
 }
 
 func (s *orderService) GetOrders(ctx context.Context, userID uuid.UUID, req *request.GetOrdersRequest) (*response.OrderListResponse, map[string]string, error) {
@@ -122,6 +294,19 @@ func (s *orderService) GetOrders(ctx context.Context, userID uuid.UUID, req *req
 		return nil, validationErrors, nil
 	}
 
+	c, validationErrors := newCursor(req.Pagination, req.Cursor)
+	if validationErrors != nil {
+		return nil, validationErrors, nil
+	}
+
+	if c != nil {
+		orders, err := s.orderRepository.GetOrdersByCursor(c, req.Limit, userID)
+		if err != nil {
+			return nil, nil, err
+		}
+		return newOrderCursorPage(orders, c, req.Limit), nil, nil
+	}
+
 	orders, total, err := s.orderRepository.GetOrders(req.Page, req.Limit, userID)
 	if err != nil {
 		return nil, nil, err
@@ -174,6 +359,22 @@ func (s *orderService) GetOrdersAdmin(ctx context.Context, req *request.GetOrder
 		return nil, validationErrors, nil
 	}
 
+	c, validationErrors := newCursor(req.Pagination, req.Cursor)
+	if validationErrors == nil {
+		validationErrors = checkCursorOrdering(c, req.OrderBy)
+	}
+	if validationErrors != nil {
+		return nil, validationErrors, nil
+	}
+
+	if c != nil {
+		orders, err := s.orderRepository.GetOrdersAdminByCursor(c, req.Limit, req.Status, req.Search)
+		if err != nil {
+			return nil, nil, err
+		}
+		return newOrderCursorPage(orders, c, req.Limit), nil, nil
+	}
+
 	orders, total, err := s.orderRepository.GetOrdersAdmin(req.Page, req.Limit, req.Status, req.Search, req.OrderBy)
 	if err != nil {
 		return nil, nil, err
@@ -209,6 +410,69 @@ func (s *orderService) GetOrdersAdmin(ctx context.Context, req *request.GetOrder
 	}, nil, nil
 }
 
+// orderExportColumns are the fields of OrderResponse flattened to one row per
+// order. Items summarises the order items, such as "VIP x2; Regular x1".
+var orderExportColumns = []interface{}{
+	"id", "invoice_id", "status", "quantity", "total_price",
+	"event_id", "event_title", "user_id", "user_name", "user_email",
+	"items", "created_at", "updated_at",
+}
+
+// ExportOrdersAdmin streams every order matching the admin filters to w as
+// CSV or XLSX, ignoring pagination. Nothing is written to w until the
+// request is validated, so validation errors can still be returned as JSON.
+func (s *orderService) ExportOrdersAdmin(ctx context.Context, req *request.GetOrdersRequestAdmin, w io.Writer) (map[string]string, error) {
+	validationErrors := validator.HandleValidationErrors(req)
+	if validationErrors != nil {
+		return validationErrors, nil
+	}
+
+	out, err := export.NewWriter(req.Format, w)
+	if err != nil {
+		return map[string]string{"format": "Format must be csv or xlsx"}, nil
+	}
+
+	if err := out.Write(orderExportColumns); err != nil {
+		return nil, err
+	}
+
+	err = s.orderRepository.ExportOrdersAdmin(req.Status, req.Search, req.OrderBy, func(order *models.OrderExportRow) error {
+		return out.Write([]interface{}{
+			order.ID, order.InvoiceID, order.Status, order.Quantity, order.TotalPrice,
+			order.EventID, order.EventTitle, order.UserID,
+			strings.TrimSpace(order.UserFirstName + " " + order.UserLastName), order.UserEmail,
+			order.Items, order.CreatedAt, order.UpdatedAt,
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return nil, out.Close()
+}
+
+// newOrderCursorPage builds a cursor mode listing from the rows fetched
+// around the cursor.
+func newOrderCursorPage(orders []*entity.Order, c *cursor.Cursor, limit int) *response.OrderListResponse {
+	page, next, prev := cursor.Page(orders, c, limit, func(order *entity.Order) (time.Time, uuid.UUID) {
+		return order.CreatedAt, order.ID
+	})
+
+	orderResponses := make([]*response.OrderResponse, 0, len(page))
+	for _, order := range page {
+		orderResponses = append(orderResponses, response.NewOrderResponse(order))
+	}
+
+	return &response.OrderListResponse{
+		Orders: orderResponses,
+		Pagination: &utils_response.Pagination{
+			Limit:      limit,
+			NextCursor: next,
+			PrevCursor: prev,
+		},
+	}
+}
+
 func (s *orderService) GetOrderById(ctx context.Context, orderID uuid.UUID, userCtx *auth.ContextKey) (*response.OrderResponse, error) {
 	order, err := s.orderRepository.GetOrderById(orderID)
 	if err != nil {
@@ -245,8 +509,30 @@ func (s *orderService) CancelOrder(ctx context.Context, orderID uuid.UUID, userI
 		return errs.ErrOrderAlreadyPaid
 	}
 
+	if order.Status == "expired" {
+		return errs.ErrOrderExpired
+	}
+
+	if order.Status == "refunded" {
+		return errs.ErrOrderRefunded
+	}
+
+	// Redis holds are only released by the request that cancelled the order.
 	if err := s.orderRepository.CancelOrder(orderID); err != nil {
-		return err
+		if errors.Is(err, repository.ErrOrderStatusChanged) {
+			return errs.ErrOrderStatusChanged
+		}
+		return errs.ErrInternalServerError
+	}
+
+	if redisIDs := redisCategoryIDs(order); len(redisIDs) > 0 {
+		if err := s.inventoryService.Release(ctx, order.ID, redisIDs); err != nil {
+			log.Printf("Failed to release holds of order %s: %v", order.ID, err)
+		}
+	}
+
+	for _, item := range order.OrderItems {
+		s.offerToWaitlist(ctx, item.CategoryID)
 	}
 
 	return nil
@@ -269,13 +555,108 @@ func (s *orderService) VerifyOrderStatus(ctx context.Context, orderID uuid.UUID)
 		return errs.ErrOrderAlreadyPaid
 	}
 
+	if order.Status == "expired" {
+		return errs.ErrOrderExpired
+	}
+
+	if order.Status == "refunded" {
+		return errs.ErrOrderRefunded
+	}
+
+	if redisIDs := redisCategoryIDs(order); len(redisIDs) > 0 {
+		confirmed, err := s.inventoryService.Confirm(ctx, order.ID, redisIDs)
+		if err != nil {
+			return errs.ErrInternalServerError
+		}
+
+		// The hold expired in at least one category; the units it still
+		// holds elsewhere go back on sale with the order.
+		if !confirmed {
+			expired, err := s.orderRepository.ExpireOrders([]uuid.UUID{order.ID})
+			if err != nil {
+				return errs.ErrInternalServerError
+			}
+			if len(expired) == 0 {
+				return errs.ErrOrderStatusChanged
+			}
+			if err := s.inventoryService.Release(ctx, order.ID, redisIDs); err != nil {
+				return errs.ErrInternalServerError
+			}
+			return errs.ErrOrderExpired
+		}
+	}
+
 	if err := s.orderRepository.VerifyOrderStatus(orderID); err != nil {
-		return err
+		if errors.Is(err, repository.ErrOrderStatusChanged) {
+			// The order was cancelled or expired after its holds were
+			// confirmed, so the confirmed units go back on sale.
+			for _, item := range order.OrderItems {
+				if item.Category != nil && item.Category.InventoryStrategy == "redis" && item.WaitlistEntryID == nil {
+					if err := s.inventoryService.Return(ctx, item.Category, item.Quantity); err != nil {
+						log.Printf("Failed to return stock for category %s: %v", item.CategoryID, err)
+					}
+				}
+			}
+			return errs.ErrOrderStatusChanged
+		}
+		return errs.ErrInternalServerError
 	}
 
 	return nil
 }
 
+// staleOrderBatch is how many pending orders ExpireStaleOrders expires at a
+// time.
+const staleOrderBatch = 100
+
+// ExpireStaleOrders expires the orders still pending payment that were placed
+// before the given time and returns how many it expired. Their stock and
+// seats are returned, their Redis holds released and their categories offered
+// to the waitlist, as when the buyer cancels. Orders paid or cancelled while
+// the batch was loaded are skipped, so a payment racing the sweep keeps its
+// holds.
+func (s *orderService) ExpireStaleOrders(ctx context.Context, before time.Time) (int, error) {
+	total := 0
+
+	for {
+		orders, err := s.orderRepository.GetStalePendingOrders(before, staleOrderBatch)
+		if err != nil {
+			return total, err
+		}
+
+		orderIDs := make([]uuid.UUID, len(orders))
+		for i, order := range orders {
+			orderIDs[i] = order.ID
+		}
+
+		expired, err := s.orderRepository.ExpireOrders(orderIDs)
+		if err != nil {
+			return total, err
+		}
+		total += len(expired)
+
+		for _, order := range orders {
+			if !slices.Contains(expired, order.ID) {
+				continue
+			}
+
+			if redisIDs := redisCategoryIDs(order); len(redisIDs) > 0 {
+				if err := s.inventoryService.Release(ctx, order.ID, redisIDs); err != nil {
+					return total, err
+				}
+			}
+
+			for _, item := range order.OrderItems {
+				s.offerToWaitlist(ctx, item.CategoryID)
+			}
+		}
+
+		if len(orders) < staleOrderBatch {
+			return total, nil
+		}
+	}
+}
+
 func (s *orderService) VerifyTicket(ctx context.Context, ticketCode string) error {
 	orderDetail, err := s.orderRepository.GetOrderDetailByTicketCode(ticketCode)
 	if err != nil {
@@ -293,6 +674,14 @@ func (s *orderService) VerifyTicket(ctx context.Context, ticketCode string) erro
 		return errs.ErrOrderAlreadyCancelled
 	}
 
+	if orderDetail.Order.Status == "expired" {
+		return errs.ErrOrderExpired
+	}
+
+	if orderDetail.Order.Status == "refunded" {
+		return errs.ErrOrderRefunded
+	}
+
 	if orderDetail.Redeemed {
 		return errs.ErrTicketAlreadyRedeemed
 	}
@@ -303,3 +692,45 @@ func (s *orderService) VerifyTicket(ctx context.Context, ticketCode string) erro
 
 	return nil
 }
+
+// RefundOrder refunds a paid order and puts its tickets back on sale, offering
+// them to the waitlist first.
+func (s *orderService) RefundOrder(ctx context.Context, orderID uuid.UUID) error {
+	order, err := s.orderRepository.GetOrderById(orderID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return errs.ErrOrderNotFound
+		}
+		return errs.ErrInternalServerError
+	}
+
+	if order.Status == "refunded" {
+		return errs.ErrOrderRefunded
+	}
+
+	if order.Status != "paid" {
+		return errs.ErrOrderNotRefundable
+	}
+
+	// Redis stock is only returned by the request that refunded the order.
+	if err := s.orderRepository.RefundOrder(orderID); err != nil {
+		if errors.Is(err, repository.ErrOrderStatusChanged) {
+			return errs.ErrOrderStatusChanged
+		}
+		return errs.ErrInternalServerError
+	}
+
+	for _, item := range order.OrderItems {
+		if item.Category != nil && item.Category.InventoryStrategy == "redis" && item.WaitlistEntryID == nil {
+			if err := s.inventoryService.Return(ctx, item.Category, item.Quantity); err != nil {
+				log.Printf("Failed to return stock for category %s: %v", item.CategoryID, err)
+			}
+		}
+	}
+
+	for _, item := range order.OrderItems {
+		s.offerToWaitlist(ctx, item.CategoryID)
+	}
+
+	return nil
+}
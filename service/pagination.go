@@ -0,0 +1,28 @@
+package service
+
+import (
+	"ticert/utils/cursor"
+)
+
+// newCursor returns the keyset cursor of a listing request, or nil when the
+// listing uses offset pagination. Sending a cursor implies cursor mode.
+func newCursor(mode, value string) (*cursor.Cursor, map[string]string) {
+	if mode != "cursor" && value == "" {
+		return nil, nil
+	}
+
+	c, err := cursor.Decode(value)
+	if err != nil {
+		return nil, map[string]string{"cursor": "Invalid cursor"}
+	}
+	return c, nil
+}
+
+// checkCursorOrdering rejects orderings other than newest first in cursor
+// mode, since cursors are keyed on creation time.
+func checkCursorOrdering(c *cursor.Cursor, orderBy string) map[string]string {
+	if c != nil && orderBy != "" && orderBy != "desc" {
+		return map[string]string{"order_by": "Cursor pagination only supports newest first ordering"}
+	}
+	return nil
+}
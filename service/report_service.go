@@ -2,33 +2,55 @@ package service
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"sort"
 	"ticert/dto/request"
 	"ticert/dto/response"
+	"ticert/entity"
+	"ticert/models"
 	"ticert/repository"
+	"ticert/utils/cursor"
 	"ticert/utils/errs"
+	"ticert/utils/export"
 	utils_response "ticert/utils/response"
 	"ticert/utils/validator"
 	"time"
 
 	"github.com/google/uuid"
+	"gorm.io/gorm"
 )
 
 type ReportService interface {
 	GenerateSummaryReport(ctx context.Context, req *request.GenerateReportRequest) (*response.SummaryReportResponse, map[string]string, error)
 	GetReportList(ctx context.Context, req *request.ReportFilterRequest) (*response.ReportListResponse, map[string]string, error)
+	ExportReportList(ctx context.Context, req *request.ReportFilterRequest, w io.Writer) (map[string]string, error)
+	GetAttendanceReport(ctx context.Context, req *request.AttendanceReportRequest) (*response.AttendanceListResponse, map[string]string, error)
+	GetCheckInTimeline(ctx context.Context, eventID uuid.UUID, req *request.CheckInTimelineRequest) (*response.CheckInTimelineResponse, map[string]string, error)
+	GetArrivalReport(ctx context.Context, eventID uuid.UUID, req *request.ArrivalReportRequest) (*response.ArrivalReportResponse, map[string]string, error)
 }
 
+// reportExportColumns are the fields of EventReportResponse flattened to one
+// row per category; an event without categories gets a single row.
+var reportExportColumns = []interface{}{
+	"event_id", "event_title", "event_date", "total_tickets_sold", "total_revenue",
+	"category_id", "category_name", "tickets_sold", "revenue", "remaining_stock", "status",
+}
+
+// reportExportBatch is how many events an export loads per query.
+const reportExportBatch = 500
+
 type reportService struct {
-	reportRepo   repository.ReportRepository
-	eventRepo    repository.EventRepository
-	categoryRepo repository.CategoryRepository
+	reportRepo repository.ReportRepository
+	eventRepo  repository.EventRepository
 }
 
-func NewReportService(reportRepo repository.ReportRepository, eventRepo repository.EventRepository, categoryRepo repository.CategoryRepository) ReportService {
+func NewReportService(reportRepo repository.ReportRepository, eventRepo repository.EventRepository) ReportService {
 	return &reportService{
-		reportRepo:   reportRepo,
-		eventRepo:    eventRepo,
-		categoryRepo: categoryRepo,
+		reportRepo: reportRepo,
+		eventRepo:  eventRepo,
 	}
 }
 
@@ -38,70 +60,35 @@ func (s *reportService) GenerateSummaryReport(ctx context.Context, req *request.
 		return nil, validationErrors, nil
 	}
 
-	var startDate, endDate *time.Time
-	var err error
-
-	if req.StartDate != "" {
-		parsedStartDate, err := time.Parse("2006-01-02", req.StartDate)
-		if err != nil {
-			return nil, map[string]string{"start_date": "Invalid start date format. Use YYYY-MM-DD"}, nil
-		}
-		startDate = &parsedStartDate
-	}
-
-	if req.EndDate != "" {
-		parsedEndDate, err := time.Parse("2006-01-02", req.EndDate)
-		if err != nil {
-			return nil, map[string]string{"end_date": "Invalid end date format. Use YYYY-MM-DD"}, nil
-		}
-		endDate = &parsedEndDate
+	filter, validationErrors := newReportFilter(req.EventID, req.CategoryID, req.StartDate, req.EndDate)
+	if validationErrors != nil {
+		return nil, validationErrors, nil
 	}
 
-	if req.EventID != "" {
-		eventID, err := uuid.Parse(req.EventID)
-		if err != nil {
-			return nil, map[string]string{"event_id": "Invalid event ID format"}, nil
-		}
+	period := reportPeriod(filter)
 
-		event, err := s.eventRepo.GetEventByID(eventID)
+	if filter.EventID != nil {
+		event, err := s.eventRepo.GetEventByID(*filter.EventID)
 		if err != nil {
-			return nil, nil, errs.ErrEventNotFound
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return nil, nil, errs.ErrEventNotFound
+			}
+			return nil, nil, errs.ErrInternalServerError
 		}
 
-		eventStats, err := s.reportRepo.GetEventTicketStats(eventID)
+		eventStats, err := s.reportRepo.GetSalesTotals(filter)
 		if err != nil {
 			return nil, nil, errs.ErrInternalServerError
 		}
 
-		categories, err := s.categoryRepo.GetCategories(eventID)
+		categories, err := s.reportRepo.GetCategoryReports(filter, []uuid.UUID{event.ID})
 		if err != nil {
 			return nil, nil, errs.ErrInternalServerError
 		}
 
 		var categoryReports []response.CategoryReportResponse
 		for _, category := range categories {
-			categoryStats, err := s.reportRepo.GetCategoryTicketStats(category.ID)
-			if err != nil {
-				continue
-			}
-
-			categoryReports = append(categoryReports, response.CategoryReportResponse{
-				CategoryID:     category.ID,
-				CategoryName:   category.Name,
-				TicketsSold:    categoryStats.TotalTickets,
-				Revenue:        categoryStats.TotalRevenue,
-				RemainingStock: category.Quantity,
-				Status:         category.Status,
-			})
-		}
-
-		period := "All Time"
-		if startDate != nil && endDate != nil {
-			period = startDate.Format("02 Jan 2006") + " - " + endDate.Format("02 Jan 2006")
-		} else if startDate != nil {
-			period = "From " + startDate.Format("02 Jan 2006")
-		} else if endDate != nil {
-			period = "Until " + endDate.Format("02 Jan 2006")
+			categoryReports = append(categoryReports, newCategoryReportResponse(category))
 		}
 
 		return &response.SummaryReportResponse{
@@ -113,17 +100,12 @@ func (s *reportService) GenerateSummaryReport(ctx context.Context, req *request.
 			GeneratedAt:      time.Now(),
 			EventID:          &event.ID,
 			EventTitle:       &event.Title,
-			EventDate:        getEventDate(event.StartDate, event.EndDate),
+			EventDate:        getEventDate(event.StartsAt.In(event.Zone()), event.EndsAt.In(event.Zone())),
 			Categories:       categoryReports,
 		}, nil, nil
 	}
 
-	totalTickets, err := s.reportRepo.GetTotalTicketsSold(startDate, endDate)
-	if err != nil {
-		return nil, nil, errs.ErrInternalServerError
-	}
-
-	totalRevenue, err := s.reportRepo.GetTotalRevenue(startDate, endDate)
+	totals, err := s.reportRepo.GetSalesTotals(filter)
 	if err != nil {
 		return nil, nil, errs.ErrInternalServerError
 	}
@@ -138,18 +120,9 @@ func (s *reportService) GenerateSummaryReport(ctx context.Context, req *request.
 		return nil, nil, errs.ErrInternalServerError
 	}
 
-	period := "All Time"
-	if startDate != nil && endDate != nil {
-		period = startDate.Format("02 Jan 2006") + " - " + endDate.Format("02 Jan 2006")
-	} else if startDate != nil {
-		period = "From " + startDate.Format("02 Jan 2006")
-	} else if endDate != nil {
-		period = "Until " + endDate.Format("02 Jan 2006")
-	}
-
 	return &response.SummaryReportResponse{
-		TotalTicketsSold: totalTickets,
-		TotalRevenue:     totalRevenue,
+		TotalTicketsSold: totals.TotalTickets,
+		TotalRevenue:     totals.TotalRevenue,
 		TotalEvents:      totalEvents,
 		TotalCategories:  totalCategories,
 		Period:           period,
@@ -173,97 +146,239 @@ func (s *reportService) GetReportList(ctx context.Context, req *request.ReportFi
 		return nil, validationErrors, nil
 	}
 
-	var eventID *uuid.UUID
-	var startDate, endDate *time.Time
-	var err error
+	filter, validationErrors := newReportFilter(req.EventID, req.CategoryID, req.StartDate, req.EndDate)
+	if validationErrors != nil {
+		return nil, validationErrors, nil
+	}
 
-	if req.EventID != "" {
-		parsedEventID, err := uuid.Parse(req.EventID)
-		if err != nil {
-			return nil, map[string]string{"event_id": "Invalid event ID format"}, nil
-		}
-		eventID = &parsedEventID
+	c, validationErrors := newCursor(req.Pagination, req.Cursor)
+	if validationErrors != nil {
+		return nil, validationErrors, nil
 	}
 
-	if req.StartDate != "" {
-		parsedStartDate, err := time.Parse("2006-01-02", req.StartDate)
+	if c != nil {
+		eventReports, err := s.reportRepo.GetEventReportsByCursor(filter, c, req.Limit)
 		if err != nil {
-			return nil, map[string]string{"start_date": "Invalid start date format. Use YYYY-MM-DD"}, nil
+			return nil, nil, errs.ErrInternalServerError
 		}
-		startDate = &parsedStartDate
-	}
 
-	if req.EndDate != "" {
-		parsedEndDate, err := time.Parse("2006-01-02", req.EndDate)
+		page, next, prev := cursor.Page(eventReports, c, req.Limit, func(report *models.EventReportData) (time.Time, uuid.UUID) {
+			return report.EventCreatedAt, report.EventID
+		})
+
+		reports, err := s.buildEventReports(filter, page)
 		if err != nil {
-			return nil, map[string]string{"end_date": "Invalid end date format. Use YYYY-MM-DD"}, nil
+			return nil, nil, errs.ErrInternalServerError
 		}
-		endDate = &parsedEndDate
+
+		return &response.ReportListResponse{
+			Reports: reports,
+			Pagination: &utils_response.Pagination{
+				Limit:      req.Limit,
+				NextCursor: next,
+				PrevCursor: prev,
+			},
+		}, nil, nil
 	}
 
-	eventReports, err := s.reportRepo.GetEventReports(eventID, startDate, endDate)
+	eventReports, total, err := s.reportRepo.GetEventReports(filter, req.Page, req.Limit)
 	if err != nil {
 		return nil, nil, errs.ErrInternalServerError
 	}
 
-	var eventReportResponses []*response.EventReportResponse
-	for _, report := range eventReports {
-		event, err := s.eventRepo.GetEventByID(report.EventID)
+	reports, err := s.buildEventReports(filter, eventReports)
+	if err != nil {
+		return nil, nil, errs.ErrInternalServerError
+	}
+
+	totalPages := int((total + int64(req.Limit) - 1) / int64(req.Limit))
+
+	return &response.ReportListResponse{
+		Reports: reports,
+		Pagination: &utils_response.Pagination{
+			Page:       req.Page,
+			Limit:      req.Limit,
+			TotalPages: totalPages,
+			Total:      total,
+		},
+	}, nil, nil
+}
+
+// ExportReportList writes every event report matching the filters to w as
+// CSV or XLSX, ignoring pagination. Events are read in batches keyed on
+// their ID, so sales recorded during a long export cannot move an event
+// between batches. Nothing is written to w until the request is validated,
+// so validation errors can still be returned as JSON.
+func (s *reportService) ExportReportList(ctx context.Context, req *request.ReportFilterRequest, w io.Writer) (map[string]string, error) {
+	validationErrors := validator.HandleValidationErrors(req)
+	if validationErrors != nil {
+		return validationErrors, nil
+	}
+
+	filter, validationErrors := newReportFilter(req.EventID, req.CategoryID, req.StartDate, req.EndDate)
+	if validationErrors != nil {
+		return validationErrors, nil
+	}
+
+	out, err := export.NewWriter(req.Format, w)
+	if err != nil {
+		return map[string]string{"format": "Format must be csv or xlsx"}, nil
+	}
+
+	if err := out.Write(reportExportColumns); err != nil {
+		return nil, err
+	}
+
+	var afterID uuid.UUID
+	for {
+		eventReports, err := s.reportRepo.GetEventReportsAfter(filter, afterID, reportExportBatch)
 		if err != nil {
-			continue
+			return nil, err
 		}
 
-		categories, err := s.categoryRepo.GetCategories(report.EventID)
+		reports, err := s.buildEventReports(filter, eventReports)
 		if err != nil {
-			continue
+			return nil, err
 		}
 
-		var categoryReports []response.CategoryReportResponse
-		for _, category := range categories {
-			categoryStats, err := s.reportRepo.GetCategoryTicketStats(category.ID)
-			if err != nil {
-				continue
+		for _, report := range reports {
+			if err := writeEventReportRows(out, report); err != nil {
+				return nil, err
 			}
+		}
 
-			categoryReports = append(categoryReports, response.CategoryReportResponse{
-				CategoryID:     category.ID,
-				CategoryName:   category.Name,
-				TicketsSold:    categoryStats.TotalTickets,
-				Revenue:        categoryStats.TotalRevenue,
-				RemainingStock: category.Quantity,
-				Status:         category.Status,
-			})
+		if len(eventReports) < reportExportBatch {
+			break
 		}
+		afterID = eventReports[len(eventReports)-1].EventID
+	}
 
+	return nil, out.Close()
+}
+
+func writeEventReportRows(out export.Writer, report *response.EventReportResponse) error {
+	event := []interface{}{report.EventID, report.EventTitle, report.EventDate, report.TotalTicketsSold, report.TotalRevenue}
+
+	if len(report.Categories) == 0 {
+		return out.Write(append(event, nil, nil, nil, nil, nil, nil))
+	}
+
+	for _, category := range report.Categories {
+		row := append(event[:len(event):len(event)], category.CategoryID, category.CategoryName, category.TicketsSold, category.Revenue, category.RemainingStock, category.Status)
+		if err := out.Write(row); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// buildEventReports adds the per-category breakdown to a page of event
+// reports, loading the categories of every event on the page at once.
+func (s *reportService) buildEventReports(filter repository.ReportFilter, eventReports []*models.EventReportData) ([]*response.EventReportResponse, error) {
+	eventIDs := make([]uuid.UUID, 0, len(eventReports))
+	for _, report := range eventReports {
+		eventIDs = append(eventIDs, report.EventID)
+	}
+
+	categories, err := s.reportRepo.GetCategoryReports(filter, eventIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	categoryReports := make(map[uuid.UUID][]response.CategoryReportResponse, len(eventReports))
+	for _, category := range categories {
+		categoryReports[category.EventID] = append(categoryReports[category.EventID], newCategoryReportResponse(category))
+	}
+
+	eventReportResponses := make([]*response.EventReportResponse, 0, len(eventReports))
+	for _, report := range eventReports {
+		zone := (&entity.Event{Timezone: report.EventTimezone}).Zone()
 		eventReportResponses = append(eventReportResponses, &response.EventReportResponse{
-			EventID:          event.ID,
-			EventTitle:       event.Title,
-			EventDate:        getEventDate(event.StartDate, event.EndDate),
+			EventID:          report.EventID,
+			EventTitle:       report.EventTitle,
+			EventDate:        getEventDate(report.EventStartsAt.In(zone), report.EventEndsAt.In(zone)),
 			TotalTicketsSold: report.TotalSold,
 			TotalRevenue:     report.TotalRevenue,
-			Categories:       categoryReports,
+			Categories:       categoryReports[report.EventID],
 		})
 	}
 
-	total := int64(len(eventReportResponses))
-	start := (req.Page - 1) * req.Limit
-	end := start + req.Limit
-	if end > int(total) {
-		end = int(total)
+	return eventReportResponses, nil
+}
+
+// GetAttendanceReport compares paid tickets with check-ins per event and
+// category, the latest starting events first. Dates filter on the start of
+// the event.
+func (s *reportService) GetAttendanceReport(ctx context.Context, req *request.AttendanceReportRequest) (*response.AttendanceListResponse, map[string]string, error) {
+	if req.Page < 1 {
+		req.Page = 1
+	}
+	if req.Limit < 1 {
+		req.Limit = 10
+	}
+	if req.Limit > 100 {
+		req.Limit = 100
+	}
+
+	validationErrors := validator.HandleValidationErrors(req)
+	if validationErrors != nil {
+		return nil, validationErrors, nil
+	}
+
+	reportFilter, validationErrors := newReportFilter(req.EventID, req.CategoryID, req.StartDate, req.EndDate)
+	if validationErrors != nil {
+		return nil, validationErrors, nil
 	}
-	if start > int(total) {
-		start = int(total)
+	filter := repository.AttendanceFilter(reportFilter)
+
+	events, total, err := s.reportRepo.GetEventAttendance(filter, req.Page, req.Limit)
+	if err != nil {
+		return nil, nil, errs.ErrInternalServerError
 	}
 
-	var paginatedReports []*response.EventReportResponse
-	if start < int(total) {
-		paginatedReports = eventReportResponses[start:end]
+	eventIDs := make([]uuid.UUID, 0, len(events))
+	for _, event := range events {
+		eventIDs = append(eventIDs, event.EventID)
+	}
+
+	categories, err := s.reportRepo.GetCategoryAttendance(filter, eventIDs)
+	if err != nil {
+		return nil, nil, errs.ErrInternalServerError
+	}
+
+	now := time.Now()
+	ended := make(map[uuid.UUID]bool, len(events))
+	for _, event := range events {
+		ended[event.EventID] = !now.Before(event.EventEndsAt)
+	}
+
+	categoryReports := make(map[uuid.UUID][]response.CategoryAttendanceResponse, len(events))
+	for _, category := range categories {
+		categoryReports[category.EventID] = append(categoryReports[category.EventID], response.CategoryAttendanceResponse{
+			CategoryID:                category.CategoryID,
+			CategoryName:              category.CategoryName,
+			AttendanceFiguresResponse: newAttendanceFigures(category.TicketsSold, category.CheckedIn, ended[category.EventID]),
+		})
+	}
+
+	reports := make([]*response.EventAttendanceResponse, 0, len(events))
+	for _, event := range events {
+		zone := (&entity.Event{Timezone: event.EventTimezone}).Zone()
+		reports = append(reports, &response.EventAttendanceResponse{
+			EventID:                   event.EventID,
+			EventTitle:                event.EventTitle,
+			EventDate:                 getEventDate(event.EventStartsAt.In(zone), event.EventEndsAt.In(zone)),
+			EventStatus:               attendanceStatus(event.EventStartsAt, event.EventEndsAt, now),
+			AttendanceFiguresResponse: newAttendanceFigures(event.TicketsSold, event.CheckedIn, ended[event.EventID]),
+			Categories:                categoryReports[event.EventID],
+		})
 	}
 
 	totalPages := int((total + int64(req.Limit) - 1) / int64(req.Limit))
 
-	return &response.ReportListResponse{
-		Reports: paginatedReports,
+	return &response.AttendanceListResponse{
+		Reports: reports,
 		Pagination: &utils_response.Pagination{
 			Page:       req.Page,
 			Limit:      req.Limit,
@@ -273,6 +388,313 @@ func (s *reportService) GetReportList(ctx context.Context, req *request.ReportFi
 	}, nil, nil
 }
 
+// GetCheckInTimeline counts the check-ins of an event per interval of
+// minutes, from its start until it ends or now, whichever is earlier. The
+// range is widened to cover check-ins made before the start or after the
+// end.
+func (s *reportService) GetCheckInTimeline(ctx context.Context, eventID uuid.UUID, req *request.CheckInTimelineRequest) (*response.CheckInTimelineResponse, map[string]string, error) {
+	if req.Interval == 0 {
+		req.Interval = 15
+	}
+
+	validationErrors := validator.HandleValidationErrors(req)
+	if validationErrors != nil {
+		return nil, validationErrors, nil
+	}
+
+	event, filter, validationErrors, err := s.getAttendanceEvent(eventID, req.CategoryID)
+	if validationErrors != nil || err != nil {
+		return nil, validationErrors, err
+	}
+
+	totals, minutes, undated, err := s.getEventCheckIns(filter)
+	if err != nil {
+		return nil, nil, errs.ErrInternalServerError
+	}
+
+	now := time.Now()
+	zone := event.Zone()
+
+	first := 0
+	last := int(minTime(event.EndsAt, now).Sub(event.StartsAt) / time.Minute)
+	if len(minutes) > 0 {
+		first = min(first, minutes[0].ArrivalMinute)
+		last = max(last, minutes[len(minutes)-1].ArrivalMinute+1)
+	}
+	first = floorDiv(first, req.Interval)
+	last = floorDiv(last+req.Interval-1, req.Interval)
+
+	if last-first > maxSeriesBuckets {
+		return nil, map[string]string{"interval": "Too many buckets for this event; use a larger interval"}, nil
+	}
+
+	buckets := make([]*response.CheckInBucketResponse, 0, max(last-first, 0))
+	for bucket := first; bucket < last; bucket++ {
+		buckets = append(buckets, &response.CheckInBucketResponse{
+			Start: event.StartsAt.Add(time.Duration(bucket*req.Interval) * time.Minute).In(zone),
+		})
+	}
+
+	var cumulative int64
+	next := 0
+	for i, bucket := range buckets {
+		for next < len(minutes) && floorDiv(minutes[next].ArrivalMinute, req.Interval) == first+i {
+			bucket.CheckIns += minutes[next].CheckIns
+			next++
+		}
+		cumulative += bucket.CheckIns
+		bucket.CumulativeCheckIns = cumulative
+		bucket.CheckInRate = percentage(cumulative, totals.TicketsSold)
+	}
+
+	figures := newAttendanceFigures(totals.TicketsSold, totals.CheckedIn, !now.Before(event.EndsAt))
+
+	return &response.CheckInTimelineResponse{
+		EventID:         event.ID,
+		EventTitle:      event.Title,
+		EventStatus:     attendanceStatus(event.StartsAt, event.EndsAt, now),
+		Timezone:        zone.String(),
+		Interval:        req.Interval,
+		StartsAt:        event.StartsAt.In(zone),
+		EndsAt:          event.EndsAt.In(zone),
+		Totals:          &figures,
+		UndatedCheckIns: undated,
+		Buckets:         buckets,
+	}, nil, nil
+}
+
+// arrivalBounds split check-ins by minutes after the start of the event.
+var arrivalBounds = []int{0, 15, 30, 60, 120}
+
+// GetArrivalReport groups the check-ins of an event by how late after its
+// start they were made.
+func (s *reportService) GetArrivalReport(ctx context.Context, eventID uuid.UUID, req *request.ArrivalReportRequest) (*response.ArrivalReportResponse, map[string]string, error) {
+	validationErrors := validator.HandleValidationErrors(req)
+	if validationErrors != nil {
+		return nil, validationErrors, nil
+	}
+
+	event, filter, validationErrors, err := s.getAttendanceEvent(eventID, req.CategoryID)
+	if validationErrors != nil || err != nil {
+		return nil, validationErrors, err
+	}
+
+	_, minutes, undated, err := s.getEventCheckIns(filter)
+	if err != nil {
+		return nil, nil, errs.ErrInternalServerError
+	}
+
+	buckets := make([]*response.ArrivalBucketResponse, 0, len(arrivalBounds)+1)
+	for i := 0; i <= len(arrivalBounds); i++ {
+		bucket := &response.ArrivalBucketResponse{}
+		switch {
+		case i == 0:
+			bucket.Label = "before_start"
+			bucket.ToMinute = &arrivalBounds[0]
+		case i == len(arrivalBounds):
+			bucket.Label = fmt.Sprintf("%dm+", arrivalBounds[i-1])
+			bucket.FromMinute = &arrivalBounds[i-1]
+		default:
+			bucket.Label = fmt.Sprintf("%d-%dm", arrivalBounds[i-1], arrivalBounds[i])
+			bucket.FromMinute = &arrivalBounds[i-1]
+			bucket.ToMinute = &arrivalBounds[i]
+		}
+		buckets = append(buckets, bucket)
+	}
+
+	var checkedIn int64
+	for _, minute := range minutes {
+		i := sort.SearchInts(arrivalBounds, minute.ArrivalMinute+1)
+		buckets[i].CheckIns += minute.CheckIns
+		checkedIn += minute.CheckIns
+	}
+
+	for _, bucket := range buckets {
+		bucket.Percentage = percentage(bucket.CheckIns, checkedIn)
+	}
+
+	var median *int
+	var seen int64
+	for _, minute := range minutes {
+		seen += minute.CheckIns
+		if seen*2 >= checkedIn {
+			median = &minute.ArrivalMinute
+			break
+		}
+	}
+
+	zone := event.Zone()
+
+	return &response.ArrivalReportResponse{
+		EventID:             event.ID,
+		EventTitle:          event.Title,
+		Timezone:            zone.String(),
+		StartsAt:            event.StartsAt.In(zone),
+		CheckedIn:           checkedIn,
+		UndatedCheckIns:     undated,
+		MedianArrivalMinute: median,
+		Buckets:             buckets,
+	}, nil, nil
+}
+
+// getAttendanceEvent loads the event of a check-in report and builds its
+// ticket filter.
+func (s *reportService) getAttendanceEvent(eventID uuid.UUID, categoryID string) (*entity.Event, repository.AttendanceFilter, map[string]string, error) {
+	reportFilter, validationErrors := newReportFilter(eventID.String(), categoryID, "", "")
+	if validationErrors != nil {
+		return nil, repository.AttendanceFilter{}, validationErrors, nil
+	}
+
+	event, err := s.eventRepo.GetEventByID(eventID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, repository.AttendanceFilter{}, nil, errs.ErrEventNotFound
+		}
+		return nil, repository.AttendanceFilter{}, nil, errs.ErrInternalServerError
+	}
+
+	return event, repository.AttendanceFilter(reportFilter), nil, nil
+}
+
+// getEventCheckIns loads the ticket totals and per-minute check-ins of the
+// single event in the filter.
+func (s *reportService) getEventCheckIns(filter repository.AttendanceFilter) (*models.EventAttendanceData, []*models.CheckInMinuteData, int64, error) {
+	totals := &models.EventAttendanceData{}
+	events, _, err := s.reportRepo.GetEventAttendance(filter, 1, 1)
+	if err != nil {
+		return nil, nil, 0, err
+	}
+	if len(events) > 0 {
+		totals = events[0]
+	}
+
+	minutes, undated, err := s.reportRepo.GetCheckInMinutes(filter)
+	if err != nil {
+		return nil, nil, 0, err
+	}
+
+	return totals, minutes, undated, nil
+}
+
+func newAttendanceFigures(sold, checkedIn int64, ended bool) response.AttendanceFiguresResponse {
+	figures := response.AttendanceFiguresResponse{
+		TicketsSold:  sold,
+		CheckedIn:    checkedIn,
+		NotCheckedIn: sold - checkedIn,
+		CheckInRate:  percentage(checkedIn, sold),
+	}
+	if ended {
+		figures.NoShow = figures.NotCheckedIn
+	}
+	return figures
+}
+
+func attendanceStatus(startsAt, endsAt, now time.Time) string {
+	switch {
+	case now.Before(startsAt):
+		return "upcoming"
+	case now.Before(endsAt):
+		return "ongoing"
+	}
+	return "ended"
+}
+
+// percentage returns part as a percentage of whole, rounded to two decimals.
+func percentage(part, whole int64) float64 {
+	if whole == 0 {
+		return 0
+	}
+	return math.Round(float64(part)*10000/float64(whole)) / 100
+}
+
+func floorDiv(a, b int) int {
+	q := a / b
+	if a%b != 0 && (a < 0) != (b < 0) {
+		q--
+	}
+	return q
+}
+
+func minTime(a, b time.Time) time.Time {
+	if a.Before(b) {
+		return a
+	}
+	return b
+}
+
+// newReportFilter parses the filters shared by the report endpoints. Dates
+// are whole UTC days and the end date is inclusive.
+func newReportFilter(eventID, categoryID, startDate, endDate string) (repository.ReportFilter, map[string]string) {
+	var filter repository.ReportFilter
+
+	if eventID != "" {
+		parsedEventID, err := uuid.Parse(eventID)
+		if err != nil {
+			return filter, map[string]string{"event_id": "Invalid event ID format"}
+		}
+		filter.EventID = &parsedEventID
+	}
+
+	if categoryID != "" {
+		parsedCategoryID, err := uuid.Parse(categoryID)
+		if err != nil {
+			return filter, map[string]string{"category_id": "Invalid category ID format"}
+		}
+		filter.CategoryID = &parsedCategoryID
+	}
+
+	if startDate != "" {
+		parsedStartDate, err := time.Parse("2006-01-02", startDate)
+		if err != nil {
+			return filter, map[string]string{"start_date": "Invalid start date format. Use YYYY-MM-DD"}
+		}
+		filter.StartDate = &parsedStartDate
+	}
+
+	if endDate != "" {
+		parsedEndDate, err := time.Parse("2006-01-02", endDate)
+		if err != nil {
+			return filter, map[string]string{"end_date": "Invalid end date format. Use YYYY-MM-DD"}
+		}
+		parsedEndDate = parsedEndDate.AddDate(0, 0, 1)
+		filter.EndDate = &parsedEndDate
+	}
+
+	if filter.StartDate != nil && filter.EndDate != nil && !filter.EndDate.After(*filter.StartDate) {
+		return filter, map[string]string{"end_date": "End date must not be before the start date"}
+	}
+
+	return filter, nil
+}
+
+func reportPeriod(filter repository.ReportFilter) string {
+	var lastDay time.Time
+	if filter.EndDate != nil {
+		lastDay = filter.EndDate.AddDate(0, 0, -1)
+	}
+
+	switch {
+	case filter.StartDate != nil && filter.EndDate != nil:
+		return filter.StartDate.Format("02 Jan 2006") + " - " + lastDay.Format("02 Jan 2006")
+	case filter.StartDate != nil:
+		return "From " + filter.StartDate.Format("02 Jan 2006")
+	case filter.EndDate != nil:
+		return "Until " + lastDay.Format("02 Jan 2006")
+	}
+	return "All Time"
+}
+
+func newCategoryReportResponse(category *models.CategoryReportData) response.CategoryReportResponse {
+	return response.CategoryReportResponse{
+		CategoryID:     category.CategoryID,
+		CategoryName:   category.CategoryName,
+		TicketsSold:    category.TotalTickets,
+		Revenue:        category.TotalRevenue,
+		RemainingStock: category.RemainingStock,
+		Status:         category.Status,
+	}
+}
+
 func getEventDate(startDate, endDate time.Time) string {
 	if startDate.Month() == endDate.Month() && startDate.Year() == endDate.Year() {
 		return startDate.Format("02") + " - " + endDate.Format("02 Jan 2006")
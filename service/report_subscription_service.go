@@ -0,0 +1,561 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"ticert/dto/request"
+	"ticert/dto/response"
+	"ticert/entity"
+	"ticert/repository"
+	"ticert/utils/cron"
+	"ticert/utils/errs"
+	"ticert/utils/export"
+	"ticert/utils/mail"
+	utils_response "ticert/utils/response"
+	"ticert/utils/validator"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+const (
+	reportSchedulerInterval = time.Minute
+	reportSchedulerBatch    = 20
+)
+
+// reportAttachmentColumns are the columns of the file attached to report
+// emails: one row per category of the reported event, followed by a totals
+// row with the category columns left empty.
+var reportAttachmentColumns = []interface{}{
+	"event_id", "event_title", "period", "category_id", "category_name",
+	"tickets_sold", "revenue", "remaining_stock", "status",
+}
+
+// ReportSubscriptionService emails summary reports on a schedule. An
+// in-process scheduler claims the subscriptions that are due, renders the
+// same report as GenerateSummaryReport and records every delivery as a run.
+type ReportSubscriptionService interface {
+	CreateSubscription(ctx context.Context, req *request.CreateReportSubscriptionRequest, userID uuid.UUID) (*response.ReportSubscriptionResponse, map[string]string, error)
+	GetSubscriptionByID(ctx context.Context, id uuid.UUID) (*response.ReportSubscriptionResponse, error)
+	GetSubscriptions(ctx context.Context, req *request.GetReportSubscriptionsRequest) (*response.ReportSubscriptionListResponse, map[string]string, error)
+	UpdateSubscription(ctx context.Context, id uuid.UUID, req *request.UpdateReportSubscriptionRequest) (*response.ReportSubscriptionResponse, map[string]string, error)
+	DeleteSubscription(ctx context.Context, id uuid.UUID) error
+	GetRuns(ctx context.Context, id uuid.UUID, req *request.GetReportRunsRequest) (*response.ReportRunListResponse, map[string]string, error)
+	RunDue(ctx context.Context) error
+	StartScheduler(ctx context.Context)
+}
+
+type reportSubscriptionService struct {
+	subscriptionRepo repository.ReportSubscriptionRepository
+	eventRepo        repository.EventRepository
+	reportService    ReportService
+	mailer           mail.Mailer
+}
+
+func NewReportSubscriptionService(subscriptionRepo repository.ReportSubscriptionRepository, eventRepo repository.EventRepository, reportService ReportService, mailer mail.Mailer) ReportSubscriptionService {
+	return &reportSubscriptionService{
+		subscriptionRepo: subscriptionRepo,
+		eventRepo:        eventRepo,
+		reportService:    reportService,
+		mailer:           mailer,
+	}
+}
+
+func (s *reportSubscriptionService) CreateSubscription(ctx context.Context, req *request.CreateReportSubscriptionRequest, userID uuid.UUID) (*response.ReportSubscriptionResponse, map[string]string, error) {
+	validationErrors := validator.HandleValidationErrors(req)
+	if validationErrors != nil {
+		return nil, validationErrors, nil
+	}
+
+	subscription := &entity.ReportSubscription{
+		Recipient: req.Recipient,
+		Kind:      req.Kind,
+		Schedule:  req.Schedule,
+		Timezone:  req.Timezone,
+		Format:    req.Format,
+		Active:    true,
+		CreatedBy: userID,
+	}
+
+	if subscription.Kind == "" {
+		subscription.Kind = "digest"
+	}
+	if subscription.Format == "" {
+		subscription.Format = "csv"
+	}
+
+	if req.EventID != "" {
+		eventID := uuid.MustParse(req.EventID)
+		subscription.EventID = &eventID
+	}
+
+	validationErrors, err := s.schedule(subscription)
+	if validationErrors != nil || err != nil {
+		return nil, validationErrors, err
+	}
+
+	if err := s.subscriptionRepo.CreateSubscription(subscription); err != nil {
+		return nil, nil, errs.ErrInternalServerError
+	}
+
+	return response.NewReportSubscriptionResponse(subscription), nil, nil
+}
+
+func (s *reportSubscriptionService) GetSubscriptionByID(ctx context.Context, id uuid.UUID) (*response.ReportSubscriptionResponse, error) {
+	subscription, err := s.getSubscription(id)
+	if err != nil {
+		return nil, err
+	}
+
+	return response.NewReportSubscriptionResponse(subscription), nil
+}
+
+func (s *reportSubscriptionService) GetSubscriptions(ctx context.Context, req *request.GetReportSubscriptionsRequest) (*response.ReportSubscriptionListResponse, map[string]string, error) {
+	if req.Page < 1 {
+		req.Page = 1
+	}
+	if req.Limit < 1 {
+		req.Limit = 10
+	}
+
+	validationErrors := validator.HandleValidationErrors(req)
+	if validationErrors != nil {
+		return nil, validationErrors, nil
+	}
+
+	subscriptions, total, err := s.subscriptionRepo.GetSubscriptions(req.Page, req.Limit)
+	if err != nil {
+		return nil, nil, errs.ErrInternalServerError
+	}
+
+	subscriptionResponses := make([]*response.ReportSubscriptionResponse, 0, len(subscriptions))
+	for _, subscription := range subscriptions {
+		subscriptionResponses = append(subscriptionResponses, response.NewReportSubscriptionResponse(subscription))
+	}
+
+	totalPages := int((total + int64(req.Limit) - 1) / int64(req.Limit))
+
+	return &response.ReportSubscriptionListResponse{
+		Subscriptions: subscriptionResponses,
+		Pagination: &utils_response.Pagination{
+			Page:       req.Page,
+			Limit:      req.Limit,
+			TotalPages: totalPages,
+			Total:      total,
+		},
+	}, nil, nil
+}
+
+// UpdateSubscription changes a subscription and schedules its next run
+// again. Reactivating a final report that was already sent sends it again.
+func (s *reportSubscriptionService) UpdateSubscription(ctx context.Context, id uuid.UUID, req *request.UpdateReportSubscriptionRequest) (*response.ReportSubscriptionResponse, map[string]string, error) {
+	validationErrors := validator.HandleValidationErrors(req)
+	if validationErrors != nil {
+		return nil, validationErrors, nil
+	}
+
+	subscription, err := s.getSubscription(id)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if req.Recipient != "" {
+		subscription.Recipient = req.Recipient
+	}
+	if req.Kind != "" {
+		subscription.Kind = req.Kind
+	}
+	if req.Schedule != "" {
+		subscription.Schedule = req.Schedule
+	}
+	if req.Timezone != "" {
+		subscription.Timezone = req.Timezone
+	}
+	if req.Format != "" {
+		subscription.Format = req.Format
+	}
+	if req.Active != nil {
+		subscription.Active = *req.Active
+	}
+
+	switch req.EventID {
+	case "":
+	case "none":
+		subscription.EventID = nil
+		subscription.Event = nil
+	default:
+		eventID, err := uuid.Parse(req.EventID)
+		if err != nil {
+			return nil, map[string]string{"event_id": "Invalid event ID format"}, nil
+		}
+		subscription.EventID = &eventID
+		subscription.Event = nil
+	}
+
+	validationErrors, err = s.schedule(subscription)
+	if validationErrors != nil || err != nil {
+		return nil, validationErrors, err
+	}
+
+	if err := s.subscriptionRepo.UpdateSubscription(subscription); err != nil {
+		return nil, nil, errs.ErrInternalServerError
+	}
+
+	return response.NewReportSubscriptionResponse(subscription), nil, nil
+}
+
+func (s *reportSubscriptionService) DeleteSubscription(ctx context.Context, id uuid.UUID) error {
+	if _, err := s.getSubscription(id); err != nil {
+		return err
+	}
+
+	if err := s.subscriptionRepo.DeleteSubscription(id); err != nil {
+		return errs.ErrInternalServerError
+	}
+
+	return nil
+}
+
+func (s *reportSubscriptionService) GetRuns(ctx context.Context, id uuid.UUID, req *request.GetReportRunsRequest) (*response.ReportRunListResponse, map[string]string, error) {
+	if req.Page < 1 {
+		req.Page = 1
+	}
+	if req.Limit < 1 {
+		req.Limit = 10
+	}
+
+	validationErrors := validator.HandleValidationErrors(req)
+	if validationErrors != nil {
+		return nil, validationErrors, nil
+	}
+
+	if _, err := s.getSubscription(id); err != nil {
+		return nil, nil, err
+	}
+
+	runs, total, err := s.subscriptionRepo.GetRuns(id, req.Page, req.Limit)
+	if err != nil {
+		return nil, nil, errs.ErrInternalServerError
+	}
+
+	runResponses := make([]*response.ReportRunResponse, 0, len(runs))
+	for _, run := range runs {
+		runResponses = append(runResponses, response.NewReportRunResponse(run))
+	}
+
+	totalPages := int((total + int64(req.Limit) - 1) / int64(req.Limit))
+
+	return &response.ReportRunListResponse{
+		Runs: runResponses,
+		Pagination: &utils_response.Pagination{
+			Page:       req.Page,
+			Limit:      req.Limit,
+			TotalPages: totalPages,
+			Total:      total,
+		},
+	}, nil, nil
+}
+
+// RunDue sends the reports that are due. Missed runs, for example while the
+// server was down, are sent once and the schedule continues from now.
+func (s *reportSubscriptionService) RunDue(ctx context.Context) error {
+	now := time.Now()
+
+	runs, err := s.subscriptionRepo.ClaimDueRuns(now, reportSchedulerBatch, func(subscription *entity.ReportSubscription) *entity.ReportRun {
+		return planRun(subscription, now)
+	})
+	if err != nil {
+		return err
+	}
+
+	for _, run := range runs {
+		run.Status = "sent"
+		if err := s.deliver(ctx, run); err != nil {
+			log.Printf("Report subscription %s failed: %v", run.SubscriptionID, err)
+			run.Status = "failed"
+			run.Error = err.Error()
+		}
+
+		finishedAt := time.Now()
+		run.FinishedAt = &finishedAt
+		if err := s.subscriptionRepo.FinishRun(run); err != nil {
+			log.Printf("Failed to record report run %s: %v", run.ID, err)
+		}
+	}
+
+	return nil
+}
+
+func (s *reportSubscriptionService) StartScheduler(ctx context.Context) {
+	ticker := time.NewTicker(reportSchedulerInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := s.RunDue(ctx); err != nil {
+				log.Printf("Report scheduler failed: %v", err)
+			}
+		}
+	}
+}
+
+// schedule checks a new or changed subscription and sets its time zone and
+// next run.
+func (s *reportSubscriptionService) schedule(subscription *entity.ReportSubscription) (map[string]string, error) {
+	schedule, err := cron.Parse(subscription.Schedule)
+	if err != nil {
+		return map[string]string{"schedule": "Invalid cron schedule: " + strings.TrimPrefix(err.Error(), "cron: ")}, nil
+	}
+
+	if subscription.EventID != nil && subscription.Event == nil {
+		event, err := s.eventRepo.GetEventByID(*subscription.EventID)
+		if err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return nil, errs.ErrEventNotFound
+			}
+			return nil, errs.ErrInternalServerError
+		}
+		subscription.Event = event
+	}
+
+	if subscription.Kind == "final" && subscription.Event == nil {
+		return map[string]string{"event_id": "A final report needs an event"}, nil
+	}
+
+	if subscription.Timezone == "" {
+		subscription.Timezone = "UTC"
+		if subscription.Event != nil {
+			subscription.Timezone = subscription.Event.Timezone
+		}
+	}
+
+	subscription.NextRunAt = nil
+	if !subscription.Active {
+		return nil, nil
+	}
+
+	next := nextRun(subscription, schedule, time.Now())
+	if next == nil {
+		return map[string]string{"schedule": "Schedule never fires"}, nil
+	}
+	subscription.NextRunAt = next
+
+	return nil, nil
+}
+
+func (s *reportSubscriptionService) getSubscription(id uuid.UUID) (*entity.ReportSubscription, error) {
+	subscription, err := s.subscriptionRepo.GetSubscriptionByID(id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errs.ErrReportSubscriptionNotFound
+		}
+		return nil, errs.ErrInternalServerError
+	}
+	return subscription, nil
+}
+
+// deliver renders the report of a run and emails it with the report
+// attached.
+func (s *reportSubscriptionService) deliver(ctx context.Context, run *entity.ReportRun) error {
+	subscription := run.Subscription
+
+	req := &request.GenerateReportRequest{}
+	if subscription.EventID != nil {
+		req.EventID = subscription.EventID.String()
+	}
+	if run.PeriodStart != nil && run.PeriodEnd != nil {
+		req.StartDate = run.PeriodStart.Format("2006-01-02")
+		req.EndDate = run.PeriodEnd.Format("2006-01-02")
+	}
+
+	report, validationErrors, err := s.reportService.GenerateSummaryReport(ctx, req)
+	if validationErrors != nil {
+		return fmt.Errorf("invalid report filters: %v", validationErrors)
+	}
+	if err != nil {
+		return err
+	}
+
+	var attachment bytes.Buffer
+	if err := writeReportAttachment(subscription.Format, &attachment, report); err != nil {
+		return err
+	}
+
+	filename := fmt.Sprintf("sales-report-%s.%s", run.ScheduledAt.In(subscription.Zone()).Format("20060102-1504"), subscription.Format)
+
+	return s.mailer.Send(ctx, &mail.Message{
+		To:      []string{subscription.Recipient},
+		Subject: reportEmailSubject(subscription, report),
+		Body:    reportEmailBody(subscription, report, filename),
+		Attachments: []mail.Attachment{{
+			Filename:    filename,
+			ContentType: export.ContentType(subscription.Format),
+			Data:        attachment.Bytes(),
+		}},
+	})
+}
+
+// planRun moves a claimed subscription to its next run and returns the run
+// to send now, or nil if nothing is due after all.
+func planRun(subscription *entity.ReportSubscription, now time.Time) *entity.ReportRun {
+	scheduledAt := *subscription.NextRunAt
+
+	schedule, err := cron.Parse(subscription.Schedule)
+	if err != nil {
+		log.Printf("Report subscription %s has an invalid schedule %q: %v", subscription.ID, subscription.Schedule, err)
+		subscription.Active = false
+		subscription.NextRunAt = nil
+		return nil
+	}
+
+	run := &entity.ReportRun{
+		ScheduledAt: scheduledAt,
+		Status:      "running",
+		StartedAt:   now,
+	}
+
+	if subscription.Kind == "final" {
+		// The event may have been moved since the run was scheduled.
+		if next := nextRun(subscription, schedule, now); next != nil && subscription.Event != nil &&
+			next.After(now) && !subscription.Event.EndsAt.Before(now) {
+			subscription.NextRunAt = next
+			return nil
+		}
+
+		subscription.Active = false
+		subscription.NextRunAt = nil
+		subscription.LastRunAt = &scheduledAt
+		return run
+	}
+
+	previous := subscription.CreatedAt
+	if subscription.LastRunAt != nil {
+		previous = *subscription.LastRunAt
+	}
+	start, end := digestPeriod(previous, scheduledAt)
+	run.PeriodStart = &start
+	run.PeriodEnd = &end
+
+	subscription.LastRunAt = &scheduledAt
+	subscription.NextRunAt = nextRun(subscription, schedule, now)
+	if subscription.NextRunAt == nil {
+		subscription.Active = false
+	}
+
+	return run
+}
+
+// nextRun returns the first scheduled time after the given one, or for a
+// final report the first one after its event ends, evaluated in the
+// subscription's time zone. It is nil when the schedule never fires.
+func nextRun(subscription *entity.ReportSubscription, schedule *cron.Schedule, after time.Time) *time.Time {
+	if subscription.Kind == "final" && subscription.Event != nil && subscription.Event.EndsAt.After(after) {
+		after = subscription.Event.EndsAt
+	}
+
+	next := schedule.Next(after.In(subscription.Zone()))
+	if next.IsZero() {
+		return nil
+	}
+	return &next
+}
+
+// digestPeriod returns the UTC days a digest covers: the whole days since
+// the previous digest, or the current day so far when the previous digest
+// was sent earlier the same day.
+func digestPeriod(previous, scheduledAt time.Time) (time.Time, time.Time) {
+	start := utcDay(previous)
+	end := utcDay(scheduledAt).AddDate(0, 0, -1)
+	if end.Before(start) {
+		return utcDay(scheduledAt), utcDay(scheduledAt)
+	}
+	return start, end
+}
+
+func utcDay(t time.Time) time.Time {
+	t = t.UTC()
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
+
+func reportEmailSubject(subscription *entity.ReportSubscription, report *response.SummaryReportResponse) string {
+	title := "All events"
+	if report.EventTitle != nil {
+		title = *report.EventTitle
+	}
+
+	if subscription.Kind == "final" {
+		return "Final sales report: " + title
+	}
+	return fmt.Sprintf("Sales digest: %s (%s)", title, report.Period)
+}
+
+func reportEmailBody(subscription *entity.ReportSubscription, report *response.SummaryReportResponse, filename string) string {
+	var b strings.Builder
+
+	if report.EventTitle != nil {
+		fmt.Fprintf(&b, "Event: %s (%s)\n", *report.EventTitle, report.EventDate)
+	} else {
+		fmt.Fprintf(&b, "Events: %d\nCategories: %d\n", report.TotalEvents, report.TotalCategories)
+	}
+	fmt.Fprintf(&b, "Period: %s\n", report.Period)
+	fmt.Fprintf(&b, "Tickets sold: %d\n", report.TotalTicketsSold)
+	fmt.Fprintf(&b, "Revenue: %.2f\n", report.TotalRevenue)
+
+	if len(report.Categories) > 0 {
+		b.WriteString("\nCategories:\n")
+		for _, category := range report.Categories {
+			fmt.Fprintf(&b, "- %s: %d sold, %.2f revenue, %d remaining (%s)\n",
+				category.CategoryName, category.TicketsSold, category.Revenue, category.RemainingStock, category.Status)
+		}
+	}
+
+	fmt.Fprintf(&b, "\nGenerated at %s. The figures are attached as %s.\n",
+		report.GeneratedAt.In(subscription.Zone()).Format("02 Jan 2006 15:04 MST"), filename)
+
+	return b.String()
+}
+
+func writeReportAttachment(format string, w *bytes.Buffer, report *response.SummaryReportResponse) error {
+	out, err := export.NewWriter(format, w)
+	if err != nil {
+		return err
+	}
+
+	if err := out.Write(reportAttachmentColumns); err != nil {
+		return err
+	}
+
+	var eventID, eventTitle interface{}
+	if report.EventID != nil {
+		eventID = *report.EventID
+	}
+	if report.EventTitle != nil {
+		eventTitle = *report.EventTitle
+	}
+
+	for _, category := range report.Categories {
+		if err := out.Write([]interface{}{
+			eventID, eventTitle, report.Period, category.CategoryID, category.CategoryName,
+			category.TicketsSold, category.Revenue, category.RemainingStock, category.Status,
+		}); err != nil {
+			return err
+		}
+	}
+
+	if err := out.Write([]interface{}{
+		eventID, eventTitle, report.Period, nil, nil,
+		report.TotalTicketsSold, report.TotalRevenue, nil, nil,
+	}); err != nil {
+		return err
+	}
+
+	return out.Close()
+}
@@ -0,0 +1,100 @@
+package service
+
+import (
+	"context"
+	"math"
+	"sort"
+	"ticert/models"
+	"ticert/repository"
+
+	"github.com/google/uuid"
+)
+
+type SalesRollupService interface {
+	Rebuild(ctx context.Context) error
+	Check(ctx context.Context) (*models.SalesRollupCheck, error)
+}
+
+type salesRollupService struct {
+	salesRollupRepo repository.SalesRollupRepository
+}
+
+func NewSalesRollupService(salesRollupRepo repository.SalesRollupRepository) SalesRollupService {
+	return &salesRollupService{salesRollupRepo: salesRollupRepo}
+}
+
+// Rebuild backfills the rollup from the orders. Reports read from the rollup
+// once it has been rebuilt at least once.
+func (s *salesRollupService) Rebuild(ctx context.Context) error {
+	return s.salesRollupRepo.Rebuild()
+}
+
+// Check compares every category day of the rollup with the same figures
+// aggregated from the orders.
+func (s *salesRollupService) Check(ctx context.Context) (*models.SalesRollupCheck, error) {
+	rebuiltAt, err := s.salesRollupRepo.GetRebuiltAt()
+	if err != nil {
+		return nil, err
+	}
+
+	rollupRows, err := s.salesRollupRepo.GetRollupRows()
+	if err != nil {
+		return nil, err
+	}
+
+	rawRows, err := s.salesRollupRepo.GetRawRows()
+	if err != nil {
+		return nil, err
+	}
+
+	type rowKey struct {
+		eventID    uuid.UUID
+		categoryID uuid.UUID
+		day        string
+	}
+	key := func(row *models.SalesRollupRow) rowKey {
+		return rowKey{row.EventID, row.CategoryID, row.Day.Format("2006-01-02")}
+	}
+
+	mismatches := make(map[rowKey]*models.SalesRollupMismatch)
+	mismatch := func(row *models.SalesRollupRow) *models.SalesRollupMismatch {
+		k := key(row)
+		if mismatches[k] == nil {
+			mismatches[k] = &models.SalesRollupMismatch{EventID: row.EventID, CategoryID: row.CategoryID, Day: row.Day}
+		}
+		return mismatches[k]
+	}
+
+	for _, row := range rollupRows {
+		mismatch(row).Rollup = *row
+	}
+	for _, row := range rawRows {
+		mismatch(row).Raw = *row
+	}
+
+	check := &models.SalesRollupCheck{RebuiltAt: rebuiltAt, Rows: len(mismatches)}
+	for _, m := range mismatches {
+		if !sameSales(m.Rollup, m.Raw) {
+			check.Mismatches = append(check.Mismatches, *m)
+		}
+	}
+
+	sort.Slice(check.Mismatches, func(i, j int) bool {
+		a, b := check.Mismatches[i], check.Mismatches[j]
+		if !a.Day.Equal(b.Day) {
+			return a.Day.Before(b.Day)
+		}
+		if a.EventID != b.EventID {
+			return a.EventID.String() < b.EventID.String()
+		}
+		return a.CategoryID.String() < b.CategoryID.String()
+	})
+
+	return check, nil
+}
+
+func sameSales(a, b models.SalesRollupRow) bool {
+	return a.OrderCount == b.OrderCount &&
+		a.TicketsSold == b.TicketsSold &&
+		math.Abs(a.Revenue-b.Revenue) < 0.005
+}
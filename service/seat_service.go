@@ -0,0 +1,309 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"ticert/dto/request"
+	"ticert/dto/response"
+	"ticert/entity"
+	"ticert/repository"
+	"ticert/utils/errs"
+	"ticert/utils/validator"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+const (
+	seatHoldTTL           = 15 * time.Minute
+	seatHoldSweepInterval = 30 * time.Second
+)
+
+// SeatService manages seat maps and reserved seating. Seats chosen in an
+// order are held while the order is pending and booked once it is paid; holds
+// of orders left unpaid for longer than seatHoldTTL are released by expiring
+// the order.
+type SeatService interface {
+	CreateSeatMap(ctx context.Context, req *request.CreateSeatMapRequest) (*response.SeatMapResponse, map[string]string, error)
+	GetSeatMaps(ctx context.Context) ([]*response.SeatMapResponse, error)
+	GetSeatMapByID(ctx context.Context, id uuid.UUID) (*response.SeatMapResponse, error)
+	DeleteSeatMap(ctx context.Context, id uuid.UUID) error
+	AssignCategorySeats(ctx context.Context, categoryID uuid.UUID, req *request.AssignCategorySeatsRequest) (*response.CategoryResponse, map[string]string, error)
+	GetEventSeats(ctx context.Context, eventID uuid.UUID) (*response.EventSeatsResponse, error)
+	ExpireLapsedHolds(ctx context.Context) error
+	StartSweeper(ctx context.Context)
+}
+
+type seatService struct {
+	seatRepo     repository.SeatRepository
+	eventRepo    repository.EventRepository
+	categoryRepo repository.CategoryRepository
+	orderRepo    repository.OrderRepository
+	venueRepo    repository.VenueRepository
+}
+
+func NewSeatService(seatRepo repository.SeatRepository, eventRepo repository.EventRepository, categoryRepo repository.CategoryRepository, orderRepo repository.OrderRepository, venueRepo repository.VenueRepository) SeatService {
+	return &seatService{
+		seatRepo:     seatRepo,
+		eventRepo:    eventRepo,
+		categoryRepo: categoryRepo,
+		orderRepo:    orderRepo,
+		venueRepo:    venueRepo,
+	}
+}
+
+func (s *seatService) CreateSeatMap(ctx context.Context, req *request.CreateSeatMapRequest) (*response.SeatMapResponse, map[string]string, error) {
+	validationErrors := validator.HandleValidationErrors(req)
+	if validationErrors != nil {
+		return nil, validationErrors, nil
+	}
+
+	if req.VenueID != nil {
+		if _, err := s.venueRepo.GetVenueByID(*req.VenueID); err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return nil, nil, errs.ErrVenueNotFound
+			}
+			return nil, nil, errs.ErrInternalServerError
+		}
+	}
+
+	seatMap := &entity.SeatMap{
+		VenueID:     req.VenueID,
+		Name:        req.Name,
+		Description: req.Description,
+	}
+
+	for i, sectionReq := range req.Sections {
+		section := entity.SeatSection{
+			Name:     sectionReq.Name,
+			Position: i,
+		}
+
+		rows := make(map[string]bool, len(sectionReq.Rows))
+		position := 0
+		for j, rowReq := range sectionReq.Rows {
+			if rows[rowReq.Label] {
+				return nil, map[string]string{fmt.Sprintf("sections[%d].rows[%d].label", i, j): "Row label must be unique within a section"}, nil
+			}
+			rows[rowReq.Label] = true
+
+			start := rowReq.StartNumber
+			if start == 0 {
+				start = 1
+			}
+			end := start + rowReq.Seats - 1
+
+			accessible, invalid := seatNumberSet(rowReq.Accessible, start, end)
+			if invalid {
+				return nil, map[string]string{fmt.Sprintf("sections[%d].rows[%d].accessible", i, j): "Seat numbers must be within the row"}, nil
+			}
+
+			companion, invalid := seatNumberSet(rowReq.Companion, start, end)
+			if invalid {
+				return nil, map[string]string{fmt.Sprintf("sections[%d].rows[%d].companion", i, j): "Seat numbers must be within the row"}, nil
+			}
+
+			for number := start; number <= end; number++ {
+				section.Seats = append(section.Seats, entity.Seat{
+					Row:        rowReq.Label,
+					Number:     number,
+					Accessible: accessible[number],
+					Companion:  companion[number],
+					Position:   position,
+				})
+				position++
+			}
+		}
+
+		seatMap.Sections = append(seatMap.Sections, section)
+	}
+
+	if err := s.seatRepo.CreateSeatMap(seatMap); err != nil {
+		return nil, nil, errs.ErrInternalServerError
+	}
+
+	return response.NewSeatMapResponse(seatMap), nil, nil
+}
+
+// seatNumberSet turns a list of seat numbers into a set, reporting whether any
+// number falls outside the row.
+func seatNumberSet(numbers []int, start, end int) (map[int]bool, bool) {
+	set := make(map[int]bool, len(numbers))
+	for _, number := range numbers {
+		if number < start || number > end {
+			return nil, true
+		}
+		set[number] = true
+	}
+	return set, false
+}
+
+func (s *seatService) GetSeatMaps(ctx context.Context) ([]*response.SeatMapResponse, error) {
+	seatMaps, err := s.seatRepo.GetSeatMaps()
+	if err != nil {
+		return nil, errs.ErrInternalServerError
+	}
+
+	seatMapResponses := make([]*response.SeatMapResponse, 0, len(seatMaps))
+	for _, seatMap := range seatMaps {
+		seatMapResponses = append(seatMapResponses, response.NewSeatMapResponse(seatMap))
+	}
+
+	return seatMapResponses, nil
+}
+
+func (s *seatService) GetSeatMapByID(ctx context.Context, id uuid.UUID) (*response.SeatMapResponse, error) {
+	seatMap, err := s.seatRepo.GetSeatMapByID(id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errs.ErrSeatMapNotFound
+		}
+		return nil, errs.ErrInternalServerError
+	}
+
+	return response.NewSeatMapResponse(seatMap), nil
+}
+
+func (s *seatService) DeleteSeatMap(ctx context.Context, id uuid.UUID) error {
+	if _, err := s.seatRepo.GetSeatMapByID(id); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return errs.ErrSeatMapNotFound
+		}
+		return errs.ErrInternalServerError
+	}
+
+	events, err := s.seatRepo.CountEventsUsingSeatMap(id)
+	if err != nil {
+		return errs.ErrInternalServerError
+	}
+
+	if events > 0 {
+		return errs.ErrSeatMapInUse
+	}
+
+	if err := s.seatRepo.DeleteSeatMap(id); err != nil {
+		return errs.ErrInternalServerError
+	}
+
+	return nil
+}
+
+// AssignCategorySeats sets the seats sold through a category, given as whole
+// sections and/or individual seats of the event's seat map. An empty request
+// turns the category back into general admission.
+func (s *seatService) AssignCategorySeats(ctx context.Context, categoryID uuid.UUID, req *request.AssignCategorySeatsRequest) (*response.CategoryResponse, map[string]string, error) {
+	validationErrors := validator.HandleValidationErrors(req)
+	if validationErrors != nil {
+		return nil, validationErrors, nil
+	}
+
+	category, err := s.categoryRepo.GetCategoryByID(categoryID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil, errs.ErrCategoryNotFound
+		}
+		return nil, nil, errs.ErrInternalServerError
+	}
+
+	if category.InventoryStrategy == "redis" {
+		return nil, nil, errs.ErrReservedSeatingStrategy
+	}
+
+	event, err := s.eventRepo.GetEventByID(category.EventID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil, errs.ErrEventNotFound
+		}
+		return nil, nil, errs.ErrInternalServerError
+	}
+
+	if event.SeatMapID == nil {
+		return nil, nil, errs.ErrEventHasNoSeatMap
+	}
+
+	seatIDs, err := s.seatRepo.FindSeatIDs(*event.SeatMapID, req.SectionIDs, req.SeatIDs)
+	if err != nil {
+		return nil, nil, errs.ErrInternalServerError
+	}
+
+	found := make(map[uuid.UUID]bool, len(seatIDs))
+	for _, seatID := range seatIDs {
+		found[seatID] = true
+	}
+	for _, seatID := range req.SeatIDs {
+		if !found[seatID] {
+			return nil, map[string]string{"seat_ids": fmt.Sprintf("Seat %s is not part of the event's seat map", seatID)}, nil
+		}
+	}
+
+	if len(req.SectionIDs) > 0 && len(seatIDs) == 0 {
+		return nil, map[string]string{"section_ids": "Sections are not part of the event's seat map"}, nil
+	}
+
+	if err := s.seatRepo.ReplaceCategorySeats(category, seatIDs); err != nil {
+		if errors.Is(err, repository.ErrSeatsInUse) {
+			return nil, nil, errs.ErrSeatsInUse
+		}
+		if errors.Is(err, repository.ErrSeatAssignedElsewhere) {
+			return nil, nil, errs.ErrSeatAssignedElsewhere
+		}
+		return nil, nil, errs.ErrInternalServerError
+	}
+
+	return response.NewCategoryResponse(category), nil, nil
+}
+
+func (s *seatService) GetEventSeats(ctx context.Context, eventID uuid.UUID) (*response.EventSeatsResponse, error) {
+	event, err := s.eventRepo.GetEventByID(eventID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errs.ErrEventNotFound
+		}
+		return nil, errs.ErrInternalServerError
+	}
+
+	if event.SeatMapID == nil {
+		return nil, errs.ErrEventHasNoSeatMap
+	}
+
+	seatMap, err := s.seatRepo.GetSeatMapByID(*event.SeatMapID)
+	if err != nil {
+		return nil, errs.ErrInternalServerError
+	}
+
+	categorySeats, err := s.seatRepo.GetEventSeats(eventID)
+	if err != nil {
+		return nil, errs.ErrInternalServerError
+	}
+
+	return response.NewEventSeatsResponse(event, seatMap, categorySeats), nil
+}
+
+func (s *seatService) ExpireLapsedHolds(ctx context.Context) error {
+	orderIDs, err := s.seatRepo.GetLapsedHoldOrderIDs(time.Now().Add(-seatHoldTTL))
+	if err != nil {
+		return err
+	}
+
+	_, err = s.orderRepo.ExpireOrders(orderIDs)
+	return err
+}
+
+func (s *seatService) StartSweeper(ctx context.Context) {
+	ticker := time.NewTicker(seatHoldSweepInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := s.ExpireLapsedHolds(ctx); err != nil {
+				log.Printf("Seat hold sweep failed: %v", err)
+			}
+		}
+	}
+}
@@ -0,0 +1,288 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"ticert/dto/request"
+	"ticert/dto/response"
+	"ticert/entity"
+	"ticert/repository"
+	"ticert/utils/errs"
+	"ticert/utils/validator"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type TagService interface {
+	CreateTag(ctx context.Context, req *request.CreateTagRequest) (*response.TagResponse, map[string]string, error)
+	GetTags(ctx context.Context, req *request.GetTagsRequest) (*response.TagListResponse, map[string]string, error)
+	UpdateTag(ctx context.Context, id uuid.UUID, req *request.UpdateTagRequest) (*response.TagResponse, map[string]string, error)
+	DeleteTag(ctx context.Context, id uuid.UUID) error
+	SetEventTags(ctx context.Context, eventID uuid.UUID, req *request.SetEventTagsRequest) ([]*response.EventTagResponse, map[string]string, error)
+}
+
+type tagService struct {
+	tagRepo          repository.TagRepository
+	eventRepo        repository.EventRepository
+	catalogueService CatalogueService
+}
+
+func NewTagService(tagRepo repository.TagRepository, eventRepo repository.EventRepository, catalogueService CatalogueService) TagService {
+	return &tagService{tagRepo: tagRepo, eventRepo: eventRepo, catalogueService: catalogueService}
+}
+
+func (s *tagService) CreateTag(ctx context.Context, req *request.CreateTagRequest) (*response.TagResponse, map[string]string, error) {
+	validationErrors := validator.HandleValidationErrors(req)
+	if validationErrors != nil {
+		return nil, validationErrors, nil
+	}
+
+	slug := req.Slug
+	if slug == "" {
+		slug = req.Name
+	}
+	slug = slugify(slug)
+	if slug == "" {
+		return nil, map[string]string{"slug": "Slug must contain letters or digits"}, nil
+	}
+
+	if existing, _ := s.tagRepo.GetTagBySlug(slug); existing != nil {
+		return nil, nil, errs.ErrTagSlugAlreadyExists
+	}
+
+	tag := &entity.Tag{
+		Name: req.Name,
+		Slug: slug,
+	}
+
+	if req.ParentID != "" {
+		parent, validationErrors, err := s.getParent(uuid.MustParse(req.ParentID))
+		if validationErrors != nil || err != nil {
+			return nil, validationErrors, err
+		}
+		tag.ParentID = &parent.ID
+	}
+
+	if err := s.tagRepo.CreateTag(tag); err != nil {
+		return nil, nil, errs.ErrInternalServerError
+	}
+
+	return response.NewTagResponse(tag, 0), nil, nil
+}
+
+// GetTags lists tags as a tree of top-level tags and their children, each
+// with the number of published events under it. Children whose parent is
+// filtered out by the search are listed at the top level.
+func (s *tagService) GetTags(ctx context.Context, req *request.GetTagsRequest) (*response.TagListResponse, map[string]string, error) {
+	validationErrors := validator.HandleValidationErrors(req)
+	if validationErrors != nil {
+		return nil, validationErrors, nil
+	}
+
+	tags, err := s.tagRepo.GetTags(req.Search)
+	if err != nil {
+		return nil, nil, errs.ErrInternalServerError
+	}
+
+	counts, err := s.tagRepo.GetTagEventCounts()
+	if err != nil {
+		return nil, nil, errs.ErrInternalServerError
+	}
+
+	tagResponses := make(map[uuid.UUID]*response.TagResponse, len(tags))
+	for _, tag := range tags {
+		tagResponses[tag.ID] = response.NewTagResponse(tag, counts[tag.ID])
+	}
+
+	roots := make([]*response.TagResponse, 0, len(tags))
+	for _, tag := range tags {
+		tagResponse := tagResponses[tag.ID]
+		if tag.ParentID != nil {
+			if parent, ok := tagResponses[*tag.ParentID]; ok {
+				parent.Children = append(parent.Children, tagResponse)
+				continue
+			}
+		}
+		roots = append(roots, tagResponse)
+	}
+
+	return &response.TagListResponse{Tags: roots}, nil, nil
+}
+
+func (s *tagService) UpdateTag(ctx context.Context, id uuid.UUID, req *request.UpdateTagRequest) (*response.TagResponse, map[string]string, error) {
+	validationErrors := validator.HandleValidationErrors(req)
+	if validationErrors != nil {
+		return nil, validationErrors, nil
+	}
+
+	tag, err := s.tagRepo.GetTagByID(id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil, errs.ErrTagNotFound
+		}
+		return nil, nil, errs.ErrInternalServerError
+	}
+
+	if req.Name != "" {
+		tag.Name = req.Name
+	}
+
+	if req.Slug != "" {
+		slug := slugify(req.Slug)
+		if slug == "" {
+			return nil, map[string]string{"slug": "Slug must contain letters or digits"}, nil
+		}
+		if existing, _ := s.tagRepo.GetTagBySlug(slug); existing != nil && existing.ID != tag.ID {
+			return nil, nil, errs.ErrTagSlugAlreadyExists
+		}
+		tag.Slug = slug
+	}
+
+	switch req.ParentID {
+	case "":
+	case "none":
+		tag.ParentID = nil
+	default:
+		parentID, err := uuid.Parse(req.ParentID)
+		if err != nil {
+			return nil, map[string]string{"parent_id": "Parent ID must be a valid UUID or \"none\""}, nil
+		}
+		if parentID == tag.ID {
+			return nil, map[string]string{"parent_id": "A tag cannot be its own parent"}, nil
+		}
+
+		children, err := s.tagRepo.CountChildren(tag.ID)
+		if err != nil {
+			return nil, nil, errs.ErrInternalServerError
+		}
+		if children > 0 {
+			return nil, map[string]string{"parent_id": "A tag with child tags must stay top-level"}, nil
+		}
+
+		parent, validationErrors, err := s.getParent(parentID)
+		if validationErrors != nil || err != nil {
+			return nil, validationErrors, err
+		}
+		tag.ParentID = &parent.ID
+	}
+
+	if err := s.tagRepo.UpdateTag(tag); err != nil {
+		return nil, nil, errs.ErrInternalServerError
+	}
+
+	s.catalogueService.Invalidate(ctx)
+
+	counts, err := s.tagRepo.GetTagEventCounts()
+	if err != nil {
+		return nil, nil, errs.ErrInternalServerError
+	}
+
+	return response.NewTagResponse(tag, counts[tag.ID]), nil, nil
+}
+
+func (s *tagService) DeleteTag(ctx context.Context, id uuid.UUID) error {
+	if _, err := s.tagRepo.GetTagByID(id); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return errs.ErrTagNotFound
+		}
+		return errs.ErrInternalServerError
+	}
+
+	children, err := s.tagRepo.CountChildren(id)
+	if err != nil {
+		return errs.ErrInternalServerError
+	}
+	if children > 0 {
+		return errs.ErrTagHasChildren
+	}
+
+	if err := s.tagRepo.DeleteTag(id); err != nil {
+		return errs.ErrInternalServerError
+	}
+
+	s.catalogueService.Invalidate(ctx)
+	return nil
+}
+
+// SetEventTags replaces the tags of an event with the given ones.
+func (s *tagService) SetEventTags(ctx context.Context, eventID uuid.UUID, req *request.SetEventTagsRequest) ([]*response.EventTagResponse, map[string]string, error) {
+	validationErrors := validator.HandleValidationErrors(req)
+	if validationErrors != nil {
+		return nil, validationErrors, nil
+	}
+
+	if _, err := s.eventRepo.GetEventByID(eventID); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil, errs.ErrEventNotFound
+		}
+		return nil, nil, errs.ErrInternalServerError
+	}
+
+	seen := make(map[uuid.UUID]bool, len(req.TagIDs))
+	tagIDs := make([]uuid.UUID, 0, len(req.TagIDs))
+	for _, value := range req.TagIDs {
+		tagID := uuid.MustParse(value)
+		if !seen[tagID] {
+			seen[tagID] = true
+			tagIDs = append(tagIDs, tagID)
+		}
+	}
+
+	tags, err := s.tagRepo.GetTagsByIDs(tagIDs)
+	if err != nil {
+		return nil, nil, errs.ErrInternalServerError
+	}
+	if len(tags) != len(tagIDs) {
+		return nil, map[string]string{"tag_ids": "One or more tags do not exist"}, nil
+	}
+
+	if err := s.tagRepo.SetEventTags(eventID, tagIDs); err != nil {
+		return nil, nil, errs.ErrInternalServerError
+	}
+
+	s.catalogueService.Invalidate(ctx)
+
+	eventTags := make([]entity.Tag, 0, len(tags))
+	for _, tag := range tags {
+		eventTags = append(eventTags, *tag)
+	}
+	return response.NewEventTagListResponse(eventTags), nil, nil
+}
+
+// getParent loads a tag that is to become a parent. Only top-level tags can
+// have children, which keeps the hierarchy two levels deep.
+func (s *tagService) getParent(id uuid.UUID) (*entity.Tag, map[string]string, error) {
+	parent, err := s.tagRepo.GetTagByID(id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, map[string]string{"parent_id": "Parent tag not found"}, nil
+		}
+		return nil, nil, errs.ErrInternalServerError
+	}
+
+	if parent.ParentID != nil {
+		return nil, map[string]string{"parent_id": "Parent tag must be a top-level tag"}, nil
+	}
+	return parent, nil, nil
+}
+
+// slugify lowercases the value and joins its runs of ASCII letters and digits
+// with hyphens, so "Music & Arts" becomes "music-arts".
+func slugify(value string) string {
+	var b strings.Builder
+	pendingHyphen := false
+	for _, r := range strings.ToLower(value) {
+		if ('a' <= r && r <= 'z') || ('0' <= r && r <= '9') {
+			if pendingHyphen && b.Len() > 0 {
+				b.WriteByte('-')
+			}
+			pendingHyphen = false
+			b.WriteRune(r)
+			continue
+		}
+		pendingHyphen = true
+	}
+	return b.String()
+}
@@ -26,6 +26,10 @@ type UserService interface {
 	UpdatePassword(ctx context.Context, userCtx auth.ContextKey, req *request.UpdatePasswordRequest) (*response.UserResponse, map[string]string, error)
 	UpdateEmail(ctx context.Context, userCtx auth.ContextKey, req *request.UpdateEmailRequest) (*response.UserResponse, map[string]string, error)
 	DeleteUser(ctx context.Context, userCtx auth.ContextKey) error
+	CreateAdmin(ctx context.Context, req *request.CreateUserRequest) (*response.UserResponse, map[string]string, error)
+	PromoteToAdmin(ctx context.Context, email string) (*response.UserResponse, error)
+	ResetPassword(ctx context.Context, req *request.ResetPasswordRequest) (*response.UserResponse, map[string]string, error)
+	RevokeSessions(ctx context.Context, email string) error
 }
 
 type userService struct {
@@ -316,3 +320,112 @@ func (s *userService) DeleteUser(ctx context.Context, userCtx auth.ContextKey) e
 
 	return nil
 }
+
+func (s *userService) CreateAdmin(ctx context.Context, req *request.CreateUserRequest) (*response.UserResponse, map[string]string, error) {
+	validationErrors := validator.HandleValidationErrors(req)
+	if validationErrors != nil {
+		return nil, validationErrors, nil
+	}
+
+	existingUser, _ := s.userRepo.GetUserByEmail(req.Email)
+	if existingUser != nil {
+		return nil, nil, errs.ErrEmailAlreadyExists
+	}
+
+	user := &entity.User{
+		Email:     req.Email,
+		FirstName: req.FirstName,
+		LastName:  req.LastName,
+		Role:      "admin",
+	}
+
+	if err := user.HashPassword(req.Password); err != nil {
+		return nil, nil, errs.ErrInternalServerError
+	}
+
+	if err := s.userRepo.CreateUser(user); err != nil {
+		return nil, nil, errs.ErrInternalServerError
+	}
+
+	return response.NewUserResponse(user), nil, nil
+}
+
+// PromoteToAdmin gives a user the admin role. Access tokens carry the role
+// they were issued with, so the user's sessions are revoked and the role
+// applies from their next login.
+func (s *userService) PromoteToAdmin(ctx context.Context, email string) (*response.UserResponse, error) {
+	user, err := s.getUserByEmail(email)
+	if err != nil {
+		return nil, err
+	}
+
+	if user.Role == "admin" {
+		return response.NewUserResponse(user), nil
+	}
+
+	user.Role = "admin"
+
+	if err := s.authRepo.RevokeAllUserTokens(user.ID); err != nil {
+		return nil, errs.ErrInternalServerError
+	}
+
+	updatedUser, err := s.userRepo.UpdateUser(user)
+	if err != nil {
+		return nil, errs.ErrInternalServerError
+	}
+
+	return response.NewUserResponse(updatedUser), nil
+}
+
+// ResetPassword sets a user's password without the old one and signs them
+// out everywhere.
+func (s *userService) ResetPassword(ctx context.Context, req *request.ResetPasswordRequest) (*response.UserResponse, map[string]string, error) {
+	validationErrors := validator.HandleValidationErrors(req)
+	if validationErrors != nil {
+		return nil, validationErrors, nil
+	}
+
+	user, err := s.getUserByEmail(req.Email)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if err := user.HashPassword(req.Password); err != nil {
+		return nil, nil, errs.ErrInternalServerError
+	}
+
+	if err := s.authRepo.RevokeAllUserTokens(user.ID); err != nil {
+		return nil, nil, errs.ErrInternalServerError
+	}
+
+	updatedUser, err := s.userRepo.UpdateUser(user)
+	if err != nil {
+		return nil, nil, errs.ErrInternalServerError
+	}
+
+	return response.NewUserResponse(updatedUser), nil, nil
+}
+
+func (s *userService) RevokeSessions(ctx context.Context, email string) error {
+	user, err := s.getUserByEmail(email)
+	if err != nil {
+		return err
+	}
+
+	if err := s.authRepo.RevokeAllUserTokens(user.ID); err != nil {
+		return errs.ErrInternalServerError
+	}
+
+	return nil
+}
+
+func (s *userService) getUserByEmail(email string) (*entity.User, error) {
+	user, err := s.userRepo.GetUserByEmail(email)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errs.ErrUserNotFound
+		}
+		return nil, errs.ErrInternalServerError
+	}
+	return user, nil
+}
@@ -0,0 +1,188 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"ticert/dto/request"
+	"ticert/dto/response"
+	"ticert/entity"
+	"ticert/repository"
+	"ticert/utils/errs"
+	utils_response "ticert/utils/response"
+	"ticert/utils/validator"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type VenueService interface {
+	CreateVenue(ctx context.Context, req *request.CreateVenueRequest) (*response.VenueResponse, map[string]string, error)
+	GetVenueByID(ctx context.Context, id uuid.UUID) (*response.VenueResponse, error)
+	GetVenues(ctx context.Context, req *request.GetVenuesRequest) (*response.VenueListResponse, map[string]string, error)
+	UpdateVenue(ctx context.Context, id uuid.UUID, req *request.UpdateVenueRequest) (*response.VenueResponse, map[string]string, error)
+	DeleteVenue(ctx context.Context, id uuid.UUID) error
+}
+
+type venueService struct {
+	venueRepo    repository.VenueRepository
+	categoryRepo repository.CategoryRepository
+}
+
+func NewVenueService(venueRepo repository.VenueRepository, categoryRepo repository.CategoryRepository) VenueService {
+	return &venueService{venueRepo: venueRepo, categoryRepo: categoryRepo}
+}
+
+func (s *venueService) CreateVenue(ctx context.Context, req *request.CreateVenueRequest) (*response.VenueResponse, map[string]string, error) {
+	validationErrors := validator.HandleValidationErrors(req)
+	if validationErrors != nil {
+		return nil, validationErrors, nil
+	}
+
+	venue := &entity.Venue{
+		Name:      req.Name,
+		Address:   req.Address,
+		Latitude:  req.Latitude,
+		Longitude: req.Longitude,
+		Timezone:  req.Timezone,
+		Capacity:  req.Capacity,
+	}
+
+	if err := s.venueRepo.CreateVenue(venue); err != nil {
+		return nil, nil, errs.ErrInternalServerError
+	}
+
+	return response.NewVenueResponse(venue), nil, nil
+}
+
+func (s *venueService) GetVenueByID(ctx context.Context, id uuid.UUID) (*response.VenueResponse, error) {
+	venue, err := s.venueRepo.GetVenueByID(id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errs.ErrVenueNotFound
+		}
+		return nil, errs.ErrInternalServerError
+	}
+
+	return response.NewVenueResponse(venue), nil
+}
+
+func (s *venueService) GetVenues(ctx context.Context, req *request.GetVenuesRequest) (*response.VenueListResponse, map[string]string, error) {
+	if req.Page < 1 {
+		req.Page = 1
+	}
+	if req.Limit < 1 {
+		req.Limit = 10
+	}
+	if req.Limit > 100 {
+		req.Limit = 100
+	}
+
+	validationErrors := validator.HandleValidationErrors(req)
+	if validationErrors != nil {
+		return nil, validationErrors, nil
+	}
+
+	venues, total, err := s.venueRepo.GetVenues(req.Page, req.Limit, req.Search)
+	if err != nil {
+		return nil, nil, errs.ErrInternalServerError
+	}
+
+	venueResponses := make([]*response.VenueResponse, 0, len(venues))
+	for _, venue := range venues {
+		venueResponses = append(venueResponses, response.NewVenueResponse(venue))
+	}
+
+	totalPages := int((total + int64(req.Limit) - 1) / int64(req.Limit))
+
+	return &response.VenueListResponse{
+		Venues: venueResponses,
+		Pagination: &utils_response.Pagination{
+			Page:       req.Page,
+			Limit:      req.Limit,
+			TotalPages: totalPages,
+			Total:      total,
+		},
+	}, nil, nil
+}
+
+func (s *venueService) UpdateVenue(ctx context.Context, id uuid.UUID, req *request.UpdateVenueRequest) (*response.VenueResponse, map[string]string, error) {
+	validationErrors := validator.HandleValidationErrors(req)
+	if validationErrors != nil {
+		return nil, validationErrors, nil
+	}
+
+	venue, err := s.venueRepo.GetVenueByID(id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil, errs.ErrVenueNotFound
+		}
+		return nil, nil, errs.ErrInternalServerError
+	}
+
+	if req.Name != "" {
+		venue.Name = req.Name
+	}
+	if req.Address != "" {
+		venue.Address = req.Address
+	}
+	if req.Latitude != nil {
+		venue.Latitude = req.Latitude
+	}
+	if req.Longitude != nil {
+		venue.Longitude = req.Longitude
+	}
+	if req.Timezone != "" {
+		venue.Timezone = req.Timezone
+	}
+
+	// Capacity cannot drop below what events at the venue already allocate.
+	if req.Capacity != 0 && req.Capacity < venue.Capacity {
+		eventIDs, err := s.venueRepo.GetEventIDsByVenue(id)
+		if err != nil {
+			return nil, nil, errs.ErrInternalServerError
+		}
+
+		peak, err := s.categoryRepo.GetPeakAllocation(eventIDs)
+		if err != nil {
+			return nil, nil, errs.ErrInternalServerError
+		}
+
+		if peak > req.Capacity {
+			return nil, map[string]string{"capacity": fmt.Sprintf("Events at this venue already allocate %d tickets on a single date", peak)}, nil
+		}
+	}
+	if req.Capacity != 0 {
+		venue.Capacity = req.Capacity
+	}
+
+	if err := s.venueRepo.UpdateVenue(venue); err != nil {
+		return nil, nil, errs.ErrInternalServerError
+	}
+
+	return response.NewVenueResponse(venue), nil, nil
+}
+
+func (s *venueService) DeleteVenue(ctx context.Context, id uuid.UUID) error {
+	if _, err := s.venueRepo.GetVenueByID(id); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return errs.ErrVenueNotFound
+		}
+		return errs.ErrInternalServerError
+	}
+
+	eventIDs, err := s.venueRepo.GetEventIDsByVenue(id)
+	if err != nil {
+		return errs.ErrInternalServerError
+	}
+
+	if len(eventIDs) > 0 {
+		return errs.ErrVenueInUse
+	}
+
+	if err := s.venueRepo.DeleteVenue(id); err != nil {
+		return errs.ErrInternalServerError
+	}
+
+	return nil
+}
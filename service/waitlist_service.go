@@ -0,0 +1,253 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"log"
+	"ticert/dto/request"
+	"ticert/dto/response"
+	"ticert/entity"
+	"ticert/repository"
+	"ticert/utils/errs"
+	"ticert/utils/validator"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+const (
+	waitlistClaimWindow   = 30 * time.Minute
+	waitlistSweepInterval = 30 * time.Second
+)
+
+// WaitlistService queues users for sold-out categories. Whenever stock comes
+// back, Offer hands it to the people in line, who get an exclusive claim
+// window to order before the tickets return to general sale.
+type WaitlistService interface {
+	JoinWaitlist(ctx context.Context, categoryID, userID uuid.UUID, req *request.JoinWaitlistRequest) (*response.WaitlistResponse, map[string]string, error)
+	GetWaitlistEntry(ctx context.Context, categoryID, userID uuid.UUID) (*response.WaitlistResponse, error)
+	LeaveWaitlist(ctx context.Context, categoryID, userID uuid.UUID) error
+	GetClaim(ctx context.Context, categoryID, userID uuid.UUID) (*entity.WaitlistEntry, error)
+	Offer(ctx context.Context, categoryID uuid.UUID) error
+	Sweep(ctx context.Context) error
+	StartSweeper(ctx context.Context)
+}
+
+type waitlistService struct {
+	waitlistRepo     repository.WaitlistRepository
+	categoryRepo     repository.CategoryRepository
+	inventoryService InventoryService
+	notifier         Notifier
+}
+
+func NewWaitlistService(waitlistRepo repository.WaitlistRepository, categoryRepo repository.CategoryRepository, inventoryService InventoryService, notifier Notifier) WaitlistService {
+	return &waitlistService{
+		waitlistRepo:     waitlistRepo,
+		categoryRepo:     categoryRepo,
+		inventoryService: inventoryService,
+		notifier:         notifier,
+	}
+}
+
+func (s *waitlistService) JoinWaitlist(ctx context.Context, categoryID, userID uuid.UUID, req *request.JoinWaitlistRequest) (*response.WaitlistResponse, map[string]string, error) {
+	validationErrors := validator.HandleValidationErrors(req)
+	if validationErrors != nil {
+		return nil, validationErrors, nil
+	}
+
+	category, err := s.categoryRepo.GetCategoryByID(categoryID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil, errs.ErrCategoryNotFound
+		}
+		return nil, nil, errs.ErrInternalServerError
+	}
+
+	switch category.SaleStatus(time.Now()) {
+	case "upcoming":
+		return nil, nil, errs.ErrSaleNotStarted
+	case "ended":
+		return nil, nil, errs.ErrSaleEnded
+	case "on_sale":
+		return nil, nil, errs.ErrWaitlistNotSoldOut
+	}
+
+	if req.Quantity < category.MinPerOrder {
+		return nil, nil, errs.ErrBelowMinimumPerOrder
+	}
+
+	if category.MaxPerOrder > 0 && req.Quantity > category.MaxPerOrder {
+		return nil, nil, errs.ErrAboveMaximumPerOrder
+	}
+
+	if _, err := s.waitlistRepo.GetActiveEntry(categoryID, userID); err == nil {
+		return nil, nil, errs.ErrWaitlistAlreadyJoined
+	} else if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil, errs.ErrInternalServerError
+	}
+
+	entry := &entity.WaitlistEntry{
+		CategoryID: categoryID,
+		UserID:     userID,
+		Quantity:   req.Quantity,
+		Status:     "waiting",
+	}
+
+	if err := s.waitlistRepo.CreateEntry(entry); err != nil {
+		return nil, nil, errs.ErrInternalServerError
+	}
+
+	ahead, err := s.waitlistRepo.CountAhead(entry)
+	if err != nil {
+		return nil, nil, errs.ErrInternalServerError
+	}
+
+	return response.NewWaitlistResponse(entry, ahead+1), nil, nil
+}
+
+func (s *waitlistService) GetWaitlistEntry(ctx context.Context, categoryID, userID uuid.UUID) (*response.WaitlistResponse, error) {
+	entry, err := s.waitlistRepo.GetActiveEntry(categoryID, userID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errs.ErrWaitlistEntryNotFound
+		}
+		return nil, errs.ErrInternalServerError
+	}
+
+	var position int64
+	if entry.Status == "waiting" {
+		ahead, err := s.waitlistRepo.CountAhead(entry)
+		if err != nil {
+			return nil, errs.ErrInternalServerError
+		}
+		position = ahead + 1
+	}
+
+	return response.NewWaitlistResponse(entry, position), nil
+}
+
+func (s *waitlistService) LeaveWaitlist(ctx context.Context, categoryID, userID uuid.UUID) error {
+	entry, err := s.waitlistRepo.GetActiveEntry(categoryID, userID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return errs.ErrWaitlistEntryNotFound
+		}
+		return errs.ErrInternalServerError
+	}
+
+	if err := s.waitlistRepo.CancelEntry(entry.ID); err != nil {
+		return errs.ErrInternalServerError
+	}
+
+	// A declined claim frees its units for the next person in line.
+	if entry.Status == "notified" {
+		if err := s.Offer(ctx, categoryID); err != nil {
+			log.Printf("Failed to offer waitlist stock for category %s: %v", categoryID, err)
+		}
+	}
+
+	return nil
+}
+
+// GetClaim returns the user's open claim for a category, or nil when the user
+// has not been offered tickets or the claim window has passed.
+func (s *waitlistService) GetClaim(ctx context.Context, categoryID, userID uuid.UUID) (*entity.WaitlistEntry, error) {
+	entry, err := s.waitlistRepo.GetActiveEntry(categoryID, userID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	if entry.Status != "notified" || entry.ClaimExpiresAt == nil || time.Now().After(*entry.ClaimExpiresAt) {
+		return nil, nil
+	}
+
+	return entry, nil
+}
+
+// Offer hands free stock of a category to the people waiting for it and
+// notifies them. For redis-strategy categories the stock is first taken out
+// of Redis; whatever is not claimed is given back.
+func (s *waitlistService) Offer(ctx context.Context, categoryID uuid.UUID) error {
+	category, err := s.categoryRepo.GetCategoryByID(categoryID)
+	if err != nil {
+		return err
+	}
+
+	taken := 0
+	if category.InventoryStrategy == "redis" {
+		demand, err := s.waitlistRepo.SumWaitingQuantity(categoryID)
+		if err != nil {
+			return err
+		}
+
+		if demand > 0 {
+			taken, err = s.inventoryService.Take(ctx, category, demand)
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	notified, leftover, err := s.waitlistRepo.AllocateClaims(categoryID, taken, time.Now().Add(waitlistClaimWindow))
+	if err != nil {
+		if taken > 0 {
+			if err := s.inventoryService.Return(ctx, category, taken); err != nil {
+				log.Printf("Failed to return stock for category %s: %v", categoryID, err)
+			}
+		}
+		return err
+	}
+
+	if err := s.inventoryService.Return(ctx, category, leftover); err != nil {
+		return err
+	}
+
+	for _, entry := range notified {
+		if err := s.notifier.NotifyWaitlistClaim(ctx, entry, category); err != nil {
+			log.Printf("Failed to notify waitlist entry %s: %v", entry.ID, err)
+		}
+	}
+
+	return nil
+}
+
+// Sweep expires claims whose window has passed and offers their units, along
+// with any other returned stock, to the next people in line.
+func (s *waitlistService) Sweep(ctx context.Context) error {
+	if err := s.waitlistRepo.ExpireClaims(time.Now()); err != nil {
+		return err
+	}
+
+	categoryIDs, err := s.waitlistRepo.GetCategoryIDsToAllocate()
+	if err != nil {
+		return err
+	}
+
+	for _, categoryID := range categoryIDs {
+		if err := s.Offer(ctx, categoryID); err != nil {
+			log.Printf("Failed to offer waitlist stock for category %s: %v", categoryID, err)
+		}
+	}
+
+	return nil
+}
+
+func (s *waitlistService) StartSweeper(ctx context.Context) {
+	ticker := time.NewTicker(waitlistSweepInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := s.Sweep(ctx); err != nil {
+				log.Printf("Waitlist sweep failed: %v", err)
+			}
+		}
+	}
+}
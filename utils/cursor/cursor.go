@@ -0,0 +1,83 @@
+// Package cursor implements opaque keyset pagination cursors. Listings in
+// cursor mode are ordered newest first by (created_at, id); a cursor names
+// the row to continue after and the direction to read in.
+package cursor
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+var ErrInvalidCursor = errors.New("cursor: invalid cursor")
+
+// Cursor points at a row of a listing. The zero value requests the first
+// page. Backward cursors read the page before the row instead of after it.
+type Cursor struct {
+	CreatedAt time.Time `json:"t"`
+	ID        uuid.UUID `json:"id"`
+	Backward  bool      `json:"b,omitempty"`
+}
+
+// IsFirst reports whether the cursor requests the first page.
+func (c *Cursor) IsFirst() bool {
+	return c.ID == uuid.Nil
+}
+
+func Encode(c Cursor) string {
+	payload, _ := json.Marshal(c)
+	return base64.RawURLEncoding.EncodeToString(payload)
+}
+
+// Decode parses a cursor from a request. An empty string is the first page.
+func Decode(value string) (*Cursor, error) {
+	if value == "" {
+		return &Cursor{}, nil
+	}
+
+	payload, err := base64.RawURLEncoding.DecodeString(value)
+	if err != nil {
+		return nil, ErrInvalidCursor
+	}
+
+	var c Cursor
+	if err := json.Unmarshal(payload, &c); err != nil || c.ID == uuid.Nil {
+		return nil, ErrInvalidCursor
+	}
+	return &c, nil
+}
+
+// Page turns up to limit+1 rows fetched in cursor order into one page in
+// newest first order, and returns the cursors of its neighbouring pages.
+// Empty cursors mean there is no page in that direction.
+func Page[T any](rows []T, c *Cursor, limit int, key func(T) (time.Time, uuid.UUID)) (page []T, next, prev string) {
+	hasMore := len(rows) > limit
+	if hasMore {
+		rows = rows[:limit]
+	}
+
+	if c.Backward {
+		for i, j := 0, len(rows)-1; i < j; i, j = i+1, j-1 {
+			rows[i], rows[j] = rows[j], rows[i]
+		}
+	}
+
+	if len(rows) == 0 {
+		return rows, "", ""
+	}
+
+	firstAt, firstID := key(rows[0])
+	lastAt, lastID := key(rows[len(rows)-1])
+
+	if hasMore || c.Backward {
+		next = Encode(Cursor{CreatedAt: lastAt, ID: lastID})
+	}
+	if (hasMore && c.Backward) || (!c.Backward && !c.IsFirst()) {
+		prev = Encode(Cursor{CreatedAt: firstAt, ID: firstID, Backward: true})
+	}
+
+	return rows, next, prev
+}
@@ -20,4 +20,9 @@ var (
 		Message:    "Stock not available",
 		StatusCode: http.StatusBadRequest,
 	}
+
+	ErrInventoryStrategyLocked = response.ErrorModel{
+		Message:    "Inventory strategy cannot be changed while the category has pending orders",
+		StatusCode: http.StatusConflict,
+	}
 )
@@ -15,4 +15,39 @@ var (
 		Message:    "Event with this title already exists",
 		StatusCode: http.StatusBadRequest,
 	}
+
+	ErrEventNotOnSale = response.ErrorModel{
+		Message:    "Event is not open for sale",
+		StatusCode: http.StatusBadRequest,
+	}
+
+	ErrEventCancelled = response.ErrorModel{
+		Message:    "Event has been cancelled",
+		StatusCode: http.StatusConflict,
+	}
+
+	ErrEventNotInSeries = response.ErrorModel{
+		Message:    "Event is not part of a series",
+		StatusCode: http.StatusBadRequest,
+	}
+
+	ErrEventAlreadyInSeries = response.ErrorModel{
+		Message:    "Event already belongs to a series",
+		StatusCode: http.StatusConflict,
+	}
+
+	ErrEventSeriesNotFound = response.ErrorModel{
+		Message:    "Event series not found",
+		StatusCode: http.StatusNotFound,
+	}
+
+	ErrEventImageNotFound = response.ErrorModel{
+		Message:    "Event image not found",
+		StatusCode: http.StatusNotFound,
+	}
+
+	ErrEventGalleryFull = response.ErrorModel{
+		Message:    "Event gallery has reached the maximum number of images",
+		StatusCode: http.StatusConflict,
+	}
 )
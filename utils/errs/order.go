@@ -39,4 +39,54 @@ var (
 		Message:    "Order not paid",
 		StatusCode: http.StatusBadRequest,
 	}
+
+	ErrOrderEventMismatch = response.ErrorModel{
+		Message:    "All items in an order must belong to the same event",
+		StatusCode: http.StatusBadRequest,
+	}
+
+	ErrSaleNotStarted = response.ErrorModel{
+		Message:    "Ticket sales for this category have not started yet",
+		StatusCode: http.StatusBadRequest,
+	}
+
+	ErrSaleEnded = response.ErrorModel{
+		Message:    "Ticket sales for this category have ended",
+		StatusCode: http.StatusBadRequest,
+	}
+
+	ErrBelowMinimumPerOrder = response.ErrorModel{
+		Message:    "Quantity is below the minimum allowed per order for this category",
+		StatusCode: http.StatusBadRequest,
+	}
+
+	ErrAboveMaximumPerOrder = response.ErrorModel{
+		Message:    "Quantity exceeds the maximum allowed per order for this category",
+		StatusCode: http.StatusBadRequest,
+	}
+
+	ErrPurchaseLimitExceeded = response.ErrorModel{
+		Message:    "You have reached the purchase limit for this category",
+		StatusCode: http.StatusBadRequest,
+	}
+
+	ErrOrderExpired = response.ErrorModel{
+		Message:    "Order reservation has expired",
+		StatusCode: http.StatusBadRequest,
+	}
+
+	ErrOrderNotRefundable = response.ErrorModel{
+		Message:    "Only paid orders can be refunded",
+		StatusCode: http.StatusBadRequest,
+	}
+
+	ErrOrderRefunded = response.ErrorModel{
+		Message:    "Order has been refunded",
+		StatusCode: http.StatusBadRequest,
+	}
+
+	ErrOrderStatusChanged = response.ErrorModel{
+		Message:    "Order status changed while it was being updated, please reload the order",
+		StatusCode: http.StatusConflict,
+	}
 )
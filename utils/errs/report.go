@@ -0,0 +1,13 @@
+package errs
+
+import (
+	"net/http"
+	"ticert/utils/response"
+)
+
+var (
+	ErrReportSubscriptionNotFound = response.ErrorModel{
+		Message:    "Report subscription not found",
+		StatusCode: http.StatusNotFound,
+	}
+)
@@ -0,0 +1,58 @@
+package errs
+
+import (
+	"net/http"
+	"ticert/utils/response"
+)
+
+var (
+	ErrSeatMapNotFound = response.ErrorModel{
+		Message:    "Seat map not found",
+		StatusCode: http.StatusNotFound,
+	}
+
+	ErrSeatMapInUse = response.ErrorModel{
+		Message:    "Seat map is used by one or more events",
+		StatusCode: http.StatusConflict,
+	}
+
+	ErrSeatMapLocked = response.ErrorModel{
+		Message:    "Seat map cannot be changed after seats are assigned to categories",
+		StatusCode: http.StatusConflict,
+	}
+
+	ErrEventHasNoSeatMap = response.ErrorModel{
+		Message:    "Event does not have a seat map",
+		StatusCode: http.StatusBadRequest,
+	}
+
+	ErrSeatsInUse = response.ErrorModel{
+		Message:    "Seats that are held or booked cannot be removed from the category",
+		StatusCode: http.StatusConflict,
+	}
+
+	ErrSeatAssignedElsewhere = response.ErrorModel{
+		Message:    "One or more seats are already assigned to another category of this event",
+		StatusCode: http.StatusConflict,
+	}
+
+	ErrSeatUnavailable = response.ErrorModel{
+		Message:    "One or more selected seats are no longer available",
+		StatusCode: http.StatusConflict,
+	}
+
+	ErrSeatSelectionMismatch = response.ErrorModel{
+		Message:    "Select exactly one seat for every ticket in a reserved seating category",
+		StatusCode: http.StatusBadRequest,
+	}
+
+	ErrSeatingNotReserved = response.ErrorModel{
+		Message:    "Seats can only be selected for reserved seating categories",
+		StatusCode: http.StatusBadRequest,
+	}
+
+	ErrReservedSeatingStrategy = response.ErrorModel{
+		Message:    "Reserved seating categories must use the database inventory strategy",
+		StatusCode: http.StatusBadRequest,
+	}
+)
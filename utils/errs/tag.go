@@ -0,0 +1,23 @@
+package errs
+
+import (
+	"net/http"
+	"ticert/utils/response"
+)
+
+var (
+	ErrTagNotFound = response.ErrorModel{
+		Message:    "Tag not found",
+		StatusCode: http.StatusNotFound,
+	}
+
+	ErrTagSlugAlreadyExists = response.ErrorModel{
+		Message:    "Tag with this slug already exists",
+		StatusCode: http.StatusBadRequest,
+	}
+
+	ErrTagHasChildren = response.ErrorModel{
+		Message:    "Tag has child tags",
+		StatusCode: http.StatusConflict,
+	}
+)
@@ -0,0 +1,28 @@
+package errs
+
+import (
+	"net/http"
+	"ticert/utils/response"
+)
+
+var (
+	ErrVenueNotFound = response.ErrorModel{
+		Message:    "Venue not found",
+		StatusCode: http.StatusNotFound,
+	}
+
+	ErrVenueInUse = response.ErrorModel{
+		Message:    "Venue is used by one or more events",
+		StatusCode: http.StatusConflict,
+	}
+
+	ErrVenueCapacityExceeded = response.ErrorModel{
+		Message:    "Ticket quantities of the event exceed the venue capacity",
+		StatusCode: http.StatusBadRequest,
+	}
+
+	ErrSeatMapVenueMismatch = response.ErrorModel{
+		Message:    "Seat map belongs to a different venue",
+		StatusCode: http.StatusBadRequest,
+	}
+)
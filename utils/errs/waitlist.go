@@ -0,0 +1,33 @@
+package errs
+
+import (
+	"net/http"
+	"ticert/utils/response"
+)
+
+var (
+	ErrWaitlistNotSoldOut = response.ErrorModel{
+		Message:    "Waitlist is only available for sold-out categories",
+		StatusCode: http.StatusBadRequest,
+	}
+
+	ErrWaitlistAlreadyJoined = response.ErrorModel{
+		Message:    "You are already on the waitlist for this category",
+		StatusCode: http.StatusConflict,
+	}
+
+	ErrWaitlistEntryNotFound = response.ErrorModel{
+		Message:    "You are not on the waitlist for this category",
+		StatusCode: http.StatusNotFound,
+	}
+
+	ErrWaitlistClaimExpired = response.ErrorModel{
+		Message:    "Your waitlist claim has expired",
+		StatusCode: http.StatusBadRequest,
+	}
+
+	ErrWaitlistClaimExceeded = response.ErrorModel{
+		Message:    "Quantity exceeds the tickets held for your waitlist claim",
+		StatusCode: http.StatusBadRequest,
+	}
+)
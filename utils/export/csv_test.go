@@ -0,0 +1,42 @@
+package export
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestCSVWriterEscapesFormulas(t *testing.T) {
+	tests := []struct {
+		name  string
+		value interface{}
+		want  string
+	}{
+		{name: "plain text", value: "Konser Musik", want: "Konser Musik"},
+		{name: "equals", value: "=HYPERLINK(\"http://x\")", want: "\"'=HYPERLINK(\"\"http://x\"\")\""},
+		{name: "plus", value: "+62812", want: "'+62812"},
+		{name: "minus", value: "-1+1", want: "'-1+1"},
+		{name: "at", value: "@SUM(A1)", want: "'@SUM(A1)"},
+		{name: "tab", value: "\t=1", want: "'\t=1"},
+		{name: "carriage return", value: "\r=1", want: "\"'\r=1\""},
+		{name: "formula character inside", value: "A=B", want: "A=B"},
+		{name: "empty", value: "", want: ""},
+		{name: "negative number", value: -12.5, want: "-12.5"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			w := newCSVWriter(&buf)
+			if err := w.Write([]interface{}{tt.value}); err != nil {
+				t.Fatal(err)
+			}
+			if err := w.Close(); err != nil {
+				t.Fatal(err)
+			}
+
+			if got := buf.String(); got != tt.want+"\n" {
+				t.Errorf("Write(%q) = %q, want %q", tt.value, got, tt.want+"\n")
+			}
+		})
+	}
+}
@@ -0,0 +1,80 @@
+// Package export writes tabular exports row by row straight to an
+// io.Writer, so large exports never have to be held in memory.
+package export
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+var ErrUnsupportedFormat = errors.New("export: unsupported format")
+
+// Writer writes one table. The first row written is the header. Values may
+// be strings, integers, floats, bools, times, UUIDs or nil.
+type Writer interface {
+	Write(row []interface{}) error
+	// Close finishes the file. It must be called for the output to be valid.
+	Close() error
+}
+
+func NewWriter(format string, w io.Writer) (Writer, error) {
+	switch format {
+	case "csv":
+		return newCSVWriter(w), nil
+	case "xlsx":
+		return newXLSXWriter(w), nil
+	}
+	return nil, ErrUnsupportedFormat
+}
+
+func ContentType(format string) string {
+	switch format {
+	case "csv":
+		return "text/csv; charset=utf-8"
+	case "xlsx":
+		return "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
+	}
+	return "application/octet-stream"
+}
+
+// formatValue renders a value as text. Times use RFC 3339 so they keep their
+// offset.
+func formatValue(value interface{}) string {
+	switch v := value.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	case int:
+		return strconv.Itoa(v)
+	case int64:
+		return strconv.FormatInt(v, 10)
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	case bool:
+		return strconv.FormatBool(v)
+	case time.Time:
+		if v.IsZero() {
+			return ""
+		}
+		return v.Format(time.RFC3339)
+	case *time.Time:
+		if v == nil {
+			return ""
+		}
+		return formatValue(*v)
+	case uuid.UUID:
+		return v.String()
+	case *uuid.UUID:
+		if v == nil {
+			return ""
+		}
+		return v.String()
+	}
+	return fmt.Sprint(value)
+}
@@ -0,0 +1,136 @@
+// Package imaging checks uploaded images and renders their thumbnails using
+// only the standard library decoders (JPEG, PNG and GIF).
+package imaging
+
+import (
+	"bytes"
+	"errors"
+	"image"
+	"image/color"
+	"image/draw"
+	_ "image/gif"
+	"image/jpeg"
+	_ "image/png"
+	"net/http"
+)
+
+// MaxPixels bounds the decoded size of an image, so a small file cannot
+// expand into a huge bitmap.
+const MaxPixels = 25_000_000
+
+var (
+	ErrUnsupportedType = errors.New("imaging: unsupported image type")
+	ErrTooLarge        = errors.New("imaging: image dimensions too large")
+)
+
+var supportedTypes = map[string]string{
+	"image/jpeg": ".jpg",
+	"image/png":  ".png",
+	"image/gif":  ".gif",
+}
+
+type Info struct {
+	ContentType string
+	Extension   string
+	Width       int
+	Height      int
+}
+
+// Inspect sniffs the content type of data from its bytes, ignoring whatever
+// the client claimed, and reads the image dimensions.
+func Inspect(data []byte) (*Info, error) {
+	contentType := http.DetectContentType(data)
+	extension, ok := supportedTypes[contentType]
+	if !ok {
+		return nil, ErrUnsupportedType
+	}
+
+	config, _, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return nil, ErrUnsupportedType
+	}
+	if config.Width*config.Height > MaxPixels {
+		return nil, ErrTooLarge
+	}
+
+	return &Info{
+		ContentType: contentType,
+		Extension:   extension,
+		Width:       config.Width,
+		Height:      config.Height,
+	}, nil
+}
+
+// Thumbnail scales the image down to fit within maxWidth by maxHeight,
+// keeping its aspect ratio, and encodes it as JPEG. Smaller images keep their
+// size. Transparent areas are flattened onto white.
+func Thumbnail(data []byte, maxWidth, maxHeight int) ([]byte, image.Point, error) {
+	src, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, image.Point{}, ErrUnsupportedType
+	}
+
+	bounds := src.Bounds()
+	size := fit(bounds.Dx(), bounds.Dy(), maxWidth, maxHeight)
+
+	flat := image.NewRGBA(image.Rect(0, 0, bounds.Dx(), bounds.Dy()))
+	draw.Draw(flat, flat.Bounds(), image.NewUniform(color.White), image.Point{}, draw.Src)
+	draw.Draw(flat, flat.Bounds(), src, bounds.Min, draw.Over)
+
+	var buf bytes.Buffer
+	if err := jpeg.Encode(&buf, resize(flat, size.X, size.Y), &jpeg.Options{Quality: 85}); err != nil {
+		return nil, image.Point{}, err
+	}
+	return buf.Bytes(), size, nil
+}
+
+func fit(width, height, maxWidth, maxHeight int) image.Point {
+	if width <= maxWidth && height <= maxHeight {
+		return image.Pt(width, height)
+	}
+
+	if width*maxHeight > height*maxWidth {
+		return image.Pt(maxWidth, max(1, height*maxWidth/width))
+	}
+	return image.Pt(max(1, width*maxHeight/height), maxHeight)
+}
+
+// resize scales src to width by height by averaging the source pixels each
+// target pixel covers, which is good enough for downscaling photos.
+func resize(src *image.RGBA, width, height int) *image.RGBA {
+	srcWidth, srcHeight := src.Bounds().Dx(), src.Bounds().Dy()
+	if srcWidth == width && srcHeight == height {
+		return src
+	}
+
+	dst := image.NewRGBA(image.Rect(0, 0, width, height))
+	for y := 0; y < height; y++ {
+		y0 := y * srcHeight / height
+		y1 := max(y0+1, (y+1)*srcHeight/height)
+
+		for x := 0; x < width; x++ {
+			x0 := x * srcWidth / width
+			x1 := max(x0+1, (x+1)*srcWidth/width)
+
+			var r, g, b, a, n int
+			for sy := y0; sy < y1; sy++ {
+				row := src.Pix[sy*src.Stride:]
+				for sx := x0; sx < x1; sx++ {
+					p := row[sx*4 : sx*4+4]
+					r += int(p[0])
+					g += int(p[1])
+					b += int(p[2])
+					a += int(p[3])
+					n++
+				}
+			}
+
+			i := dst.PixOffset(x, y)
+			dst.Pix[i] = uint8(r / n)
+			dst.Pix[i+1] = uint8(g / n)
+			dst.Pix[i+2] = uint8(b / n)
+			dst.Pix[i+3] = uint8(a / n)
+		}
+	}
+	return dst
+}
@@ -0,0 +1,487 @@
+// Package migrate applies versioned SQL migrations to a MySQL database.
+//
+// Migrations are pairs of files named "<version>_<name>.up.sql" and
+// "<version>_<name>.down.sql", where version is a positive number. Applied
+// versions are recorded in the schema_migrations table. A MySQL advisory lock
+// is held while migrating, so when several instances start at once only one
+// of them migrates and the others wait for it to finish.
+//
+// MySQL commits schema changes implicitly, so a migration that fails part way
+// cannot be rolled back. Its version is left marked dirty and no further
+// migrations run until the schema has been repaired by hand and the version
+// marked clean again.
+package migrate
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	table       = "schema_migrations"
+	lockName    = "schema_migrations"
+	lockTimeout = 10 * time.Minute
+)
+
+var (
+	fileName = regexp.MustCompile(`^(\d+)_(\w+)\.(up|down)\.sql$`)
+	newName  = regexp.MustCompile(`^[a-z0-9_]+$`)
+)
+
+var (
+	ErrLocked  = errors.New("migrate: timed out waiting for another instance to finish migrating")
+	ErrNoDown  = errors.New("migrate: migration has no down file")
+	ErrUnknown = errors.New("migrate: applied version has no migration file")
+)
+
+// DirtyError reports a migration that failed part way.
+type DirtyError struct {
+	Version int64
+}
+
+func (e *DirtyError) Error() string {
+	return fmt.Sprintf("migrate: version %d is dirty; repair the schema, then set dirty = FALSE for it in %s if it was applied or delete its row if it was not", e.Version, table)
+}
+
+// Migration is one version with the SQL to apply and revert it.
+type Migration struct {
+	Version int64
+	Name    string
+	Up      string
+	Down    string
+}
+
+// Status describes a migration and whether it was applied. Migrations that
+// were applied but whose files are gone are listed with Missing set.
+type Status struct {
+	Version   int64
+	Name      string
+	AppliedAt *time.Time
+	Dirty     bool
+	Missing   bool
+}
+
+type applied struct {
+	Version   int64
+	Name      string
+	Dirty     bool
+	AppliedAt time.Time
+}
+
+// Migrator applies the migrations found in a file system.
+type Migrator struct {
+	db         *sql.DB
+	migrations []*Migration
+
+	// Adopt, when set, runs before the first migration is applied to a
+	// database without a schema_migrations table, while the lock is held.
+	// It lets a database created by other means be brought to the state the
+	// first migration expects.
+	Adopt func(ctx context.Context) error
+}
+
+// New reads the migrations in the root of source.
+func New(db *sql.DB, source fs.FS) (*Migrator, error) {
+	migrations, err := Load(source)
+	if err != nil {
+		return nil, err
+	}
+	return &Migrator{db: db, migrations: migrations}, nil
+}
+
+// Load reads the migrations in the root of source, sorted by version.
+func Load(source fs.FS) ([]*Migration, error) {
+	entries, err := fs.ReadDir(source, ".")
+	if err != nil {
+		return nil, err
+	}
+
+	byVersion := make(map[int64]*Migration)
+	hasUp := make(map[int64]bool)
+	for _, entry := range entries {
+		match := fileName.FindStringSubmatch(entry.Name())
+		if entry.IsDir() || match == nil {
+			continue
+		}
+
+		version, err := strconv.ParseInt(match[1], 10, 64)
+		if err != nil || version < 1 {
+			return nil, fmt.Errorf("migrate: invalid version in %s", entry.Name())
+		}
+
+		migration, ok := byVersion[version]
+		if !ok {
+			migration = &Migration{Version: version, Name: match[2]}
+			byVersion[version] = migration
+		} else if migration.Name != match[2] {
+			return nil, fmt.Errorf("migrate: version %d is used by %s and %s", version, migration.Name, match[2])
+		}
+
+		data, err := fs.ReadFile(source, entry.Name())
+		if err != nil {
+			return nil, err
+		}
+		if match[3] == "up" {
+			migration.Up = string(data)
+			hasUp[version] = true
+		} else {
+			migration.Down = string(data)
+		}
+	}
+
+	migrations := make([]*Migration, 0, len(byVersion))
+	for _, migration := range byVersion {
+		if !hasUp[migration.Version] {
+			return nil, fmt.Errorf("migrate: version %d has no up file", migration.Version)
+		}
+		migrations = append(migrations, migration)
+	}
+	sort.Slice(migrations, func(i, j int) bool { return migrations[i].Version < migrations[j].Version })
+
+	return migrations, nil
+}
+
+// Up applies the pending migrations in version order, at most limit of them
+// unless limit is 0, and returns those it applied.
+func (m *Migrator) Up(ctx context.Context, limit int) ([]*Migration, error) {
+	var done []*Migration
+
+	err := m.locked(ctx, func(conn *sql.Conn) error {
+		exists, err := m.tableExists(ctx, conn)
+		if err != nil {
+			return err
+		}
+		if !exists && m.Adopt != nil {
+			if err := m.Adopt(ctx); err != nil {
+				return err
+			}
+		}
+		if err := m.createTable(ctx, conn); err != nil {
+			return err
+		}
+
+		versions, err := m.applied(ctx, conn)
+		if err != nil {
+			return err
+		}
+
+		for _, migration := range m.migrations {
+			if limit > 0 && len(done) == limit {
+				break
+			}
+			if _, ok := versions[migration.Version]; ok {
+				continue
+			}
+
+			if err := m.apply(ctx, conn, migration); err != nil {
+				return err
+			}
+			done = append(done, migration)
+		}
+
+		return nil
+	})
+
+	return done, err
+}
+
+// Down reverts the latest applied migrations, steps of them, and returns
+// those it reverted.
+func (m *Migrator) Down(ctx context.Context, steps int) ([]*Migration, error) {
+	var done []*Migration
+
+	err := m.locked(ctx, func(conn *sql.Conn) error {
+		if err := m.createTable(ctx, conn); err != nil {
+			return err
+		}
+
+		versions, err := m.applied(ctx, conn)
+		if err != nil {
+			return err
+		}
+
+		latest := make([]int64, 0, len(versions))
+		for version := range versions {
+			latest = append(latest, version)
+		}
+		sort.Slice(latest, func(i, j int) bool { return latest[i] > latest[j] })
+
+		for _, version := range latest {
+			if len(done) == steps {
+				break
+			}
+
+			migration := m.find(version)
+			if migration == nil {
+				return fmt.Errorf("%w: %d", ErrUnknown, version)
+			}
+			if strings.TrimSpace(migration.Down) == "" {
+				return fmt.Errorf("%w: %d_%s", ErrNoDown, migration.Version, migration.Name)
+			}
+
+			if err := m.revert(ctx, conn, migration); err != nil {
+				return err
+			}
+			done = append(done, migration)
+		}
+
+		return nil
+	})
+
+	return done, err
+}
+
+// Status lists every migration, known or applied, in version order.
+func (m *Migrator) Status(ctx context.Context) ([]*Status, error) {
+	conn, err := m.db.Conn(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+
+	versions := make(map[int64]*applied)
+	exists, err := m.tableExists(ctx, conn)
+	if err != nil {
+		return nil, err
+	}
+	if exists {
+		rows, err := conn.QueryContext(ctx, "SELECT version, name, dirty, applied_at FROM "+table)
+		if err != nil {
+			return nil, err
+		}
+		defer rows.Close()
+
+		for rows.Next() {
+			var a applied
+			if err := rows.Scan(&a.Version, &a.Name, &a.Dirty, &a.AppliedAt); err != nil {
+				return nil, err
+			}
+			versions[a.Version] = &a
+		}
+		if err := rows.Err(); err != nil {
+			return nil, err
+		}
+	}
+
+	statuses := make([]*Status, 0, len(m.migrations))
+	for _, migration := range m.migrations {
+		status := &Status{Version: migration.Version, Name: migration.Name}
+		if a, ok := versions[migration.Version]; ok {
+			status.AppliedAt = &a.AppliedAt
+			status.Dirty = a.Dirty
+			delete(versions, migration.Version)
+		}
+		statuses = append(statuses, status)
+	}
+	for _, a := range versions {
+		statuses = append(statuses, &Status{Version: a.Version, Name: a.Name, AppliedAt: &a.AppliedAt, Dirty: a.Dirty, Missing: true})
+	}
+	sort.Slice(statuses, func(i, j int) bool { return statuses[i].Version < statuses[j].Version })
+
+	return statuses, nil
+}
+
+// Create writes empty up and down files for a new migration to dir,
+// numbered after the highest version already there, and returns their
+// paths.
+func Create(dir, name string) (string, string, error) {
+	if !newName.MatchString(name) {
+		return "", "", fmt.Errorf("migrate: name %q must only contain lowercase letters, digits and underscores", name)
+	}
+
+	migrations, err := Load(os.DirFS(dir))
+	if err != nil {
+		return "", "", err
+	}
+
+	var version int64 = 1
+	if len(migrations) > 0 {
+		version = migrations[len(migrations)-1].Version + 1
+	}
+
+	base := filepath.Join(dir, fmt.Sprintf("%04d_%s", version, name))
+	up, down := base+".up.sql", base+".down.sql"
+	for _, path := range []string{up, down} {
+		f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0o644)
+		if err != nil {
+			return "", "", err
+		}
+		if err := f.Close(); err != nil {
+			return "", "", err
+		}
+	}
+
+	return up, down, nil
+}
+
+// locked runs fn on a connection holding the migration lock. The lock is
+// tied to the connection and released with it should the process die.
+func (m *Migrator) locked(ctx context.Context, fn func(conn *sql.Conn) error) error {
+	conn, err := m.db.Conn(ctx)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	var acquired sql.NullInt64
+	if err := conn.QueryRowContext(ctx, "SELECT GET_LOCK(?, ?)", lockName, int(lockTimeout.Seconds())).Scan(&acquired); err != nil {
+		return err
+	}
+	if acquired.Int64 != 1 {
+		return ErrLocked
+	}
+	defer conn.ExecContext(context.Background(), "DO RELEASE_LOCK(?)", lockName)
+
+	return fn(conn)
+}
+
+func (m *Migrator) tableExists(ctx context.Context, conn *sql.Conn) (bool, error) {
+	var count int
+	if err := conn.QueryRowContext(ctx, `SELECT COUNT(*) FROM information_schema.TABLES
+		WHERE TABLE_SCHEMA = DATABASE() AND TABLE_NAME = ?`, table).Scan(&count); err != nil {
+		return false, err
+	}
+	return count > 0, nil
+}
+
+func (m *Migrator) createTable(ctx context.Context, conn *sql.Conn) error {
+	_, err := conn.ExecContext(ctx, `CREATE TABLE IF NOT EXISTS `+table+` (
+		version BIGINT NOT NULL PRIMARY KEY,
+		name VARCHAR(255) NOT NULL,
+		dirty BOOLEAN NOT NULL DEFAULT FALSE,
+		applied_at DATETIME NOT NULL
+	)`)
+	return err
+}
+
+// applied returns the applied versions, failing if one of them is dirty.
+func (m *Migrator) applied(ctx context.Context, conn *sql.Conn) (map[int64]bool, error) {
+	rows, err := conn.QueryContext(ctx, "SELECT version, dirty FROM "+table)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	versions := make(map[int64]bool)
+	for rows.Next() {
+		var version int64
+		var dirty bool
+		if err := rows.Scan(&version, &dirty); err != nil {
+			return nil, err
+		}
+		if dirty {
+			return nil, &DirtyError{Version: version}
+		}
+		versions[version] = true
+	}
+
+	return versions, rows.Err()
+}
+
+func (m *Migrator) apply(ctx context.Context, conn *sql.Conn, migration *Migration) error {
+	if _, err := conn.ExecContext(ctx, "INSERT INTO "+table+" (version, name, dirty, applied_at) VALUES (?, ?, TRUE, ?)",
+		migration.Version, migration.Name, time.Now().UTC()); err != nil {
+		return err
+	}
+
+	if err := execAll(ctx, conn, migration.Up); err != nil {
+		return fmt.Errorf("migrate: applying %d_%s: %w", migration.Version, migration.Name, err)
+	}
+
+	_, err := conn.ExecContext(ctx, "UPDATE "+table+" SET dirty = FALSE WHERE version = ?", migration.Version)
+	return err
+}
+
+func (m *Migrator) revert(ctx context.Context, conn *sql.Conn, migration *Migration) error {
+	if _, err := conn.ExecContext(ctx, "UPDATE "+table+" SET dirty = TRUE WHERE version = ?", migration.Version); err != nil {
+		return err
+	}
+
+	if err := execAll(ctx, conn, migration.Down); err != nil {
+		return fmt.Errorf("migrate: reverting %d_%s: %w", migration.Version, migration.Name, err)
+	}
+
+	_, err := conn.ExecContext(ctx, "DELETE FROM "+table+" WHERE version = ?", migration.Version)
+	return err
+}
+
+func (m *Migrator) find(version int64) *Migration {
+	for _, migration := range m.migrations {
+		if migration.Version == version {
+			return migration
+		}
+	}
+	return nil
+}
+
+func execAll(ctx context.Context, conn *sql.Conn, script string) error {
+	for _, statement := range Split(script) {
+		if _, err := conn.ExecContext(ctx, statement); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Split breaks a script into statements at semicolons outside quotes,
+// backticks and comments. Comments are kept with the statement they precede
+// and statements holding nothing but comments are dropped.
+func Split(script string) []string {
+	var statements []string
+	start := 0
+	meaningful := false
+
+	flush := func(end int) {
+		if meaningful {
+			statements = append(statements, strings.TrimSpace(script[start:end]))
+		}
+		start = end + 1
+		meaningful = false
+	}
+
+	for i := 0; i < len(script); i++ {
+		switch c := script[i]; {
+		case c == '\'' || c == '"' || c == '`':
+			meaningful = true
+			for i++; i < len(script) && script[i] != c; i++ {
+				if script[i] == '\\' && c != '`' {
+					i++
+				}
+			}
+		case c == '#' || c == '-' && isLineComment(script[i:]):
+			for i < len(script) && script[i] != '\n' {
+				i++
+			}
+		case c == '/' && strings.HasPrefix(script[i:], "/*"):
+			end := strings.Index(script[i+2:], "*/")
+			if end < 0 {
+				i = len(script)
+			} else {
+				i += end + 3
+			}
+		case c == ';':
+			flush(i)
+		case c != ' ' && c != '\t' && c != '\n' && c != '\r':
+			meaningful = true
+		}
+	}
+	flush(len(script))
+
+	return statements
+}
+
+// isLineComment reports whether s starts with "--" followed by whitespace,
+// which MySQL reads as a comment.
+func isLineComment(s string) bool {
+	return strings.HasPrefix(s, "--") && (len(s) == 2 || strings.ContainsRune(" \t\r\n", rune(s[2])))
+}
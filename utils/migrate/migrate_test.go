@@ -0,0 +1,354 @@
+package migrate
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+	"testing/fstest"
+
+	"github.com/DATA-DOG/go-sqlmock"
+)
+
+func TestSplit(t *testing.T) {
+	tests := []struct {
+		name   string
+		script string
+		want   []string
+	}{
+		{name: "empty", script: "", want: nil},
+		{name: "single without semicolon", script: "SELECT 1", want: []string{"SELECT 1"}},
+		{
+			name:   "several statements",
+			script: "CREATE TABLE a (id INT);\n\nCREATE TABLE b (id INT);\n",
+			want:   []string{"CREATE TABLE a (id INT)", "CREATE TABLE b (id INT)"},
+		},
+		{
+			name:   "semicolons in quotes",
+			script: `INSERT INTO a VALUES ('x;y', "p;q");` + "SELECT `odd;name` FROM a;",
+			want:   []string{`INSERT INTO a VALUES ('x;y', "p;q")`, "SELECT `odd;name` FROM a"},
+		},
+		{
+			name:   "escaped quote",
+			script: `INSERT INTO a VALUES ('it\'s;fine'); SELECT 2;`,
+			want:   []string{`INSERT INTO a VALUES ('it\'s;fine')`, "SELECT 2"},
+		},
+		{
+			name:   "comments kept with the next statement",
+			script: "-- users; the first table\nCREATE TABLE users (id INT);\n# roles;\nCREATE TABLE roles (id INT);",
+			want:   []string{"-- users; the first table\nCREATE TABLE users (id INT)", "# roles;\nCREATE TABLE roles (id INT)"},
+		},
+		{
+			name:   "block comment",
+			script: "/* a; b */ SELECT 1; SELECT /* ; */ 2;",
+			want:   []string{"/* a; b */ SELECT 1", "SELECT /* ; */ 2"},
+		},
+		{
+			name:   "comment only statements dropped",
+			script: "SELECT 1;\n-- trailing note;\n/* done */;\n",
+			want:   []string{"SELECT 1"},
+		},
+		{
+			name:   "double dash without space is not a comment",
+			script: "SELECT 5--1; SELECT 2;",
+			want:   []string{"SELECT 5--1", "SELECT 2"},
+		},
+		{name: "double dash at end of script", script: "SELECT 1;\n--", want: []string{"SELECT 1"}},
+		{name: "unterminated block comment", script: "SELECT 1; /* ; SELECT 2;", want: []string{"SELECT 1"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Split(tt.script); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Split(%q) = %q, want %q", tt.script, got, tt.want)
+			}
+		})
+	}
+}
+
+var testMigrations = fstest.MapFS{
+	"0001_create_users.up.sql":   {Data: []byte("CREATE TABLE users (id INT);\nCREATE INDEX idx_users_id ON users (id);")},
+	"0001_create_users.down.sql": {Data: []byte("DROP TABLE users;")},
+	"0002_create_orders.up.sql":  {Data: []byte("CREATE TABLE orders (id INT);")},
+	"0003_seed_roles.up.sql":     {Data: []byte("INSERT INTO roles VALUES (1);")},
+	"README.md":                  {Data: []byte("not a migration")},
+}
+
+func newTestMigrator(t *testing.T) (*Migrator, sqlmock.Sqlmock) {
+	t.Helper()
+
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { db.Close() })
+
+	m, err := New(db, testMigrations)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return m, mock
+}
+
+func expectLock(mock sqlmock.Sqlmock, acquired int) {
+	mock.ExpectQuery(`SELECT GET_LOCK\(\?, \?\)`).
+		WithArgs(lockName, int(lockTimeout.Seconds())).
+		WillReturnRows(sqlmock.NewRows([]string{"acquired"}).AddRow(acquired))
+}
+
+func expectUnlock(mock sqlmock.Sqlmock) {
+	mock.ExpectExec(`DO RELEASE_LOCK\(\?\)`).WithArgs(lockName).WillReturnResult(sqlmock.NewResult(0, 0))
+}
+
+func expectTable(mock sqlmock.Sqlmock, exists bool) {
+	count := 0
+	if exists {
+		count = 1
+	}
+	mock.ExpectQuery(`FROM information_schema.TABLES`).
+		WithArgs(table).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(count))
+}
+
+func expectApplied(mock sqlmock.Sqlmock, versions map[int64]bool) {
+	mock.ExpectExec(`CREATE TABLE IF NOT EXISTS schema_migrations`).WillReturnResult(sqlmock.NewResult(0, 0))
+
+	rows := sqlmock.NewRows([]string{"version", "dirty"})
+	for _, version := range []int64{1, 2, 3} {
+		if dirty, ok := versions[version]; ok {
+			rows.AddRow(version, dirty)
+		}
+	}
+	mock.ExpectQuery(`SELECT version, dirty FROM schema_migrations`).WillReturnRows(rows)
+}
+
+func expectApply(mock sqlmock.Sqlmock, version int64, name string, statements ...string) {
+	mock.ExpectExec(`INSERT INTO schema_migrations \(version, name, dirty, applied_at\) VALUES \(\?, \?, TRUE, \?\)`).
+		WithArgs(version, name, sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	for _, statement := range statements {
+		mock.ExpectExec(statement).WillReturnResult(sqlmock.NewResult(0, 0))
+	}
+	mock.ExpectExec(`UPDATE schema_migrations SET dirty = FALSE WHERE version = \?`).
+		WithArgs(version).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+}
+
+func versions(migrations []*Migration) []int64 {
+	var versions []int64
+	for _, migration := range migrations {
+		versions = append(versions, migration.Version)
+	}
+	return versions
+}
+
+func TestLoad(t *testing.T) {
+	migrations, err := Load(testMigrations)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := versions(migrations); !reflect.DeepEqual(got, []int64{1, 2, 3}) {
+		t.Fatalf("versions = %v, want [1 2 3]", got)
+	}
+	if migrations[0].Name != "create_users" || migrations[0].Down != "DROP TABLE users;" {
+		t.Errorf("migration 1 = %+v", migrations[0])
+	}
+
+	for name, source := range map[string]fstest.MapFS{
+		"down without up":    {"0001_a.down.sql": {}},
+		"version name clash": {"0001_a.up.sql": {}, "0001_b.up.sql": {}},
+		"version zero":       {"0000_a.up.sql": {}},
+	} {
+		if _, err := Load(source); err == nil {
+			t.Errorf("Load(%s) succeeded, want an error", name)
+		}
+	}
+}
+
+func TestMigratorUp(t *testing.T) {
+	m, mock := newTestMigrator(t)
+
+	adopted := false
+	m.Adopt = func(ctx context.Context) error {
+		adopted = true
+		return nil
+	}
+
+	expectLock(mock, 1)
+	expectTable(mock, false)
+	expectApplied(mock, nil)
+	expectApply(mock, 1, "create_users", `CREATE TABLE users`, `CREATE INDEX idx_users_id`)
+	expectApply(mock, 2, "create_orders", `CREATE TABLE orders`)
+	expectUnlock(mock)
+
+	done, err := m.Up(context.Background(), 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := versions(done); !reflect.DeepEqual(got, []int64{1, 2}) {
+		t.Errorf("applied %v, want [1 2]", got)
+	}
+	if !adopted {
+		t.Error("Adopt was not called for a database without schema_migrations")
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestMigratorUpSkipsApplied(t *testing.T) {
+	m, mock := newTestMigrator(t)
+	m.Adopt = func(ctx context.Context) error {
+		t.Error("Adopt called for a database with schema_migrations")
+		return nil
+	}
+
+	expectLock(mock, 1)
+	expectTable(mock, true)
+	expectApplied(mock, map[int64]bool{1: false})
+	expectApply(mock, 2, "create_orders", `CREATE TABLE orders`)
+	expectApply(mock, 3, "seed_roles", `INSERT INTO roles`)
+	expectUnlock(mock)
+
+	done, err := m.Up(context.Background(), 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := versions(done); !reflect.DeepEqual(got, []int64{2, 3}) {
+		t.Errorf("applied %v, want [2 3]", got)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestMigratorUpLeavesFailedMigrationDirty(t *testing.T) {
+	m, mock := newTestMigrator(t)
+	failure := errors.New("index already exists")
+
+	expectLock(mock, 1)
+	expectTable(mock, true)
+	expectApplied(mock, nil)
+	mock.ExpectExec(`INSERT INTO schema_migrations`).WithArgs(int64(1), "create_users", sqlmock.AnyArg()).WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(`CREATE TABLE users`).WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec(`CREATE INDEX idx_users_id`).WillReturnError(failure)
+	expectUnlock(mock)
+
+	done, err := m.Up(context.Background(), 0)
+	if !errors.Is(err, failure) {
+		t.Fatalf("Up() error = %v, want %v", err, failure)
+	}
+	if len(done) != 0 {
+		t.Errorf("applied %v, want none", versions(done))
+	}
+
+	// The next run finds the version dirty and refuses to go on.
+	expectLock(mock, 1)
+	expectTable(mock, true)
+	expectApplied(mock, map[int64]bool{1: true})
+	expectUnlock(mock)
+
+	_, err = m.Up(context.Background(), 0)
+	var dirty *DirtyError
+	if !errors.As(err, &dirty) || dirty.Version != 1 {
+		t.Fatalf("Up() error = %v, want a DirtyError for version 1", err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestMigratorUpLocked(t *testing.T) {
+	m, mock := newTestMigrator(t)
+
+	expectLock(mock, 0)
+
+	if _, err := m.Up(context.Background(), 0); !errors.Is(err, ErrLocked) {
+		t.Fatalf("Up() error = %v, want %v", err, ErrLocked)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestMigratorDown(t *testing.T) {
+	m, mock := newTestMigrator(t)
+
+	expectLock(mock, 1)
+	expectApplied(mock, map[int64]bool{1: false})
+	mock.ExpectExec(`UPDATE schema_migrations SET dirty = TRUE WHERE version = \?`).WithArgs(int64(1)).WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(`DROP TABLE users`).WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec(`DELETE FROM schema_migrations WHERE version = \?`).WithArgs(int64(1)).WillReturnResult(sqlmock.NewResult(0, 1))
+	expectUnlock(mock)
+
+	done, err := m.Down(context.Background(), 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := versions(done); !reflect.DeepEqual(got, []int64{1}) {
+		t.Errorf("reverted %v, want [1]", got)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestMigratorDownErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		applied map[int64]bool
+		want    error
+		dirty   bool
+	}{
+		{name: "latest has no down file", applied: map[int64]bool{1: false, 2: false}, want: ErrNoDown},
+		{name: "dirty version", applied: map[int64]bool{1: false, 2: true}, dirty: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m, mock := newTestMigrator(t)
+
+			expectLock(mock, 1)
+			expectApplied(mock, tt.applied)
+			expectUnlock(mock)
+
+			done, err := m.Down(context.Background(), 1)
+			if tt.dirty {
+				var dirty *DirtyError
+				if !errors.As(err, &dirty) || dirty.Version != 2 {
+					t.Fatalf("Down() error = %v, want a DirtyError for version 2", err)
+				}
+			} else if !errors.Is(err, tt.want) {
+				t.Fatalf("Down() error = %v, want %v", err, tt.want)
+			}
+			if len(done) != 0 {
+				t.Errorf("reverted %v, want none", versions(done))
+			}
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Error(err)
+			}
+		})
+	}
+
+	t.Run("unknown version", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer db.Close()
+		m := &Migrator{db: db}
+
+		expectLock(mock, 1)
+		mock.ExpectExec(`CREATE TABLE IF NOT EXISTS schema_migrations`).WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectQuery(`SELECT version, dirty FROM schema_migrations`).
+			WillReturnRows(sqlmock.NewRows([]string{"version", "dirty"}).AddRow(int64(7), false))
+		expectUnlock(mock)
+
+		if _, err := m.Down(context.Background(), 1); !errors.Is(err, ErrUnknown) {
+			t.Fatalf("Down() error = %v, want %v", err, ErrUnknown)
+		}
+		if err := mock.ExpectationsWereMet(); err != nil {
+			t.Error(err)
+		}
+	})
+}
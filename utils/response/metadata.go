@@ -5,4 +5,9 @@ type Pagination struct {
 	Limit      int   `json:"limit"`
 	TotalPages int   `json:"total_pages"`
 	Total      int64 `json:"total"`
+
+	// NextCursor and PrevCursor are only set in cursor mode, where page and
+	// total counts are not computed.
+	NextCursor string `json:"next_cursor,omitempty"`
+	PrevCursor string `json:"prev_cursor,omitempty"`
 }
@@ -0,0 +1,240 @@
+// Package rrule implements the subset of RFC 5545 recurrence rules used by
+// event series: FREQ (DAILY, WEEKLY, MONTHLY), INTERVAL, COUNT, UNTIL, BYDAY
+// for weekly rules and BYMONTHDAY for monthly rules.
+package rrule
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var (
+	ErrUnbounded          = errors.New("rrule: COUNT or UNTIL is required")
+	ErrTooManyOccurrences = errors.New("rrule: too many occurrences")
+	ErrNoOccurrences      = errors.New("rrule: rule stops producing occurrences")
+)
+
+// maxEmptyPeriods is how many periods in a row may pass without a candidate
+// before a rule is taken to produce none anymore, such as a yearly step
+// through February on day 30. Monthly rules repeat their months at least
+// every 12 periods; the longest real gap, day 29 in February every 12
+// months, is 8 years.
+const maxEmptyPeriods = 120
+
+var weekdays = map[string]time.Weekday{
+	"SU": time.Sunday,
+	"MO": time.Monday,
+	"TU": time.Tuesday,
+	"WE": time.Wednesday,
+	"TH": time.Thursday,
+	"FR": time.Friday,
+	"SA": time.Saturday,
+}
+
+type Rule struct {
+	Freq       string
+	Interval   int
+	Count      int
+	Until      *time.Time
+	ByDay      []time.Weekday
+	ByMonthDay []int
+}
+
+// Parse reads a rule such as "FREQ=WEEKLY;BYDAY=FR,SA;COUNT=10". The "RRULE:"
+// prefix is optional. Rules must be bounded by COUNT or UNTIL.
+func Parse(value string) (*Rule, error) {
+	value = strings.TrimPrefix(strings.TrimSpace(value), "RRULE:")
+	rule := &Rule{Interval: 1}
+
+	for _, part := range strings.Split(value, ";") {
+		key, val, ok := strings.Cut(part, "=")
+		if !ok || val == "" {
+			return nil, fmt.Errorf("rrule: invalid part %q", part)
+		}
+
+		switch strings.ToUpper(key) {
+		case "FREQ":
+			switch strings.ToUpper(val) {
+			case "DAILY", "WEEKLY", "MONTHLY":
+				rule.Freq = strings.ToUpper(val)
+			default:
+				return nil, fmt.Errorf("rrule: unsupported FREQ %q", val)
+			}
+		case "INTERVAL":
+			n, err := strconv.Atoi(val)
+			if err != nil || n < 1 {
+				return nil, fmt.Errorf("rrule: invalid INTERVAL %q", val)
+			}
+			rule.Interval = n
+		case "COUNT":
+			n, err := strconv.Atoi(val)
+			if err != nil || n < 1 {
+				return nil, fmt.Errorf("rrule: invalid COUNT %q", val)
+			}
+			rule.Count = n
+		case "UNTIL":
+			until, err := parseUntil(val)
+			if err != nil {
+				return nil, err
+			}
+			rule.Until = &until
+		case "BYDAY":
+			for _, day := range strings.Split(val, ",") {
+				weekday, ok := weekdays[strings.ToUpper(day)]
+				if !ok {
+					return nil, fmt.Errorf("rrule: unsupported BYDAY %q", day)
+				}
+				rule.ByDay = append(rule.ByDay, weekday)
+			}
+		case "BYMONTHDAY":
+			for _, day := range strings.Split(val, ",") {
+				n, err := strconv.Atoi(day)
+				if err != nil || n < 1 || n > 31 {
+					return nil, fmt.Errorf("rrule: invalid BYMONTHDAY %q", day)
+				}
+				rule.ByMonthDay = append(rule.ByMonthDay, n)
+			}
+		default:
+			return nil, fmt.Errorf("rrule: unsupported part %q", key)
+		}
+	}
+
+	if rule.Freq == "" {
+		return nil, errors.New("rrule: FREQ is required")
+	}
+	if rule.Count > 0 && rule.Until != nil {
+		return nil, errors.New("rrule: COUNT and UNTIL cannot be combined")
+	}
+	if rule.Count == 0 && rule.Until == nil {
+		return nil, ErrUnbounded
+	}
+	if len(rule.ByDay) > 0 && rule.Freq != "WEEKLY" {
+		return nil, errors.New("rrule: BYDAY is only supported with FREQ=WEEKLY")
+	}
+	if len(rule.ByMonthDay) > 0 && rule.Freq != "MONTHLY" {
+		return nil, errors.New("rrule: BYMONTHDAY is only supported with FREQ=MONTHLY")
+	}
+
+	sort.Slice(rule.ByDay, func(i, j int) bool {
+		return weekdayOffset(rule.ByDay[i]) < weekdayOffset(rule.ByDay[j])
+	})
+	sort.Ints(rule.ByMonthDay)
+
+	return rule, nil
+}
+
+// Occurrences expands the rule from start. As in RFC 5545 the start always is
+// the first occurrence and counts towards COUNT. Occurrences keep the wall
+// clock time of start in its location, so they stay put across daylight
+// saving changes. More than limit occurrences is an error, as is a rule that
+// stops producing occurrences before reaching its COUNT.
+func (r *Rule) Occurrences(start time.Time, limit int) ([]time.Time, error) {
+	occurrences := []time.Time{start}
+
+	empty := 0
+	for period := 0; ; period++ {
+		candidates := r.period(start, period)
+		if candidates == nil {
+			break
+		}
+
+		if len(candidates) == 0 {
+			empty++
+			if empty > maxEmptyPeriods {
+				return nil, ErrNoOccurrences
+			}
+			continue
+		}
+		empty = 0
+
+		for _, candidate := range candidates {
+			if !candidate.After(start) {
+				continue
+			}
+			if r.Until != nil && candidate.After(*r.Until) {
+				return occurrences, nil
+			}
+			if r.Count > 0 && len(occurrences) >= r.Count {
+				return occurrences, nil
+			}
+			if len(occurrences) >= limit {
+				return nil, ErrTooManyOccurrences
+			}
+			occurrences = append(occurrences, candidate)
+		}
+	}
+
+	return occurrences, nil
+}
+
+// period returns the candidates of the n-th period after start in order, or
+// nil once a period starts past UNTIL.
+func (r *Rule) period(start time.Time, n int) []time.Time {
+	loc := start.Location()
+	hour, min, sec := start.Clock()
+	at := func(year int, month time.Month, day int) time.Time {
+		return time.Date(year, month, day, hour, min, sec, 0, loc)
+	}
+
+	var periodStart time.Time
+	var candidates []time.Time
+
+	switch r.Freq {
+	case "DAILY":
+		periodStart = at(start.Year(), start.Month(), start.Day()+n*r.Interval)
+		candidates = []time.Time{periodStart}
+	case "WEEKLY":
+		// Weeks start on Monday, the RFC 5545 default for WKST.
+		monday := start.Day() - weekdayOffset(start.Weekday()) + n*r.Interval*7
+		periodStart = at(start.Year(), start.Month(), monday)
+		days := r.ByDay
+		if len(days) == 0 {
+			days = []time.Weekday{start.Weekday()}
+		}
+		for _, day := range days {
+			candidates = append(candidates, at(start.Year(), start.Month(), monday+weekdayOffset(day)))
+		}
+	case "MONTHLY":
+		first := time.Date(start.Year(), start.Month()+time.Month(n*r.Interval), 1, 0, 0, 0, 0, loc)
+		periodStart = at(first.Year(), first.Month(), 1)
+		days := r.ByMonthDay
+		if len(days) == 0 {
+			days = []int{start.Day()}
+		}
+		for _, day := range days {
+			// Months without the day are skipped, as RFC 5545 requires.
+			if candidate := at(first.Year(), first.Month(), day); candidate.Month() == first.Month() {
+				candidates = append(candidates, candidate)
+			}
+		}
+	}
+
+	if r.Until != nil && periodStart.After(*r.Until) {
+		return nil
+	}
+	if candidates == nil {
+		return []time.Time{}
+	}
+	return candidates
+}
+
+// parseUntil accepts the date and UTC date-time forms of UNTIL.
+func parseUntil(value string) (time.Time, error) {
+	for _, layout := range []string{"20060102T150405Z", "20060102"} {
+		if until, err := time.Parse(layout, value); err == nil {
+			if layout == "20060102" {
+				until = until.Add(24*time.Hour - time.Second)
+			}
+			return until, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("rrule: invalid UNTIL %q", value)
+}
+
+func weekdayOffset(day time.Weekday) int {
+	return (int(day) + 6) % 7
+}
@@ -0,0 +1,188 @@
+package rrule
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestParse(t *testing.T) {
+	until := time.Date(2025, 3, 31, 23, 59, 59, 0, time.UTC)
+
+	tests := []struct {
+		name  string
+		value string
+		want  *Rule
+		err   string
+	}{
+		{
+			name:  "weekly by day",
+			value: "FREQ=WEEKLY;BYDAY=SU,FR,MO;COUNT=10",
+			want:  &Rule{Freq: "WEEKLY", Interval: 1, Count: 10, ByDay: []time.Weekday{time.Monday, time.Friday, time.Sunday}},
+		},
+		{
+			name:  "prefix and lowercase",
+			value: " RRULE:freq=daily;interval=2;count=3 ",
+			want:  &Rule{Freq: "DAILY", Interval: 2, Count: 3},
+		},
+		{
+			name:  "monthly by month day",
+			value: "FREQ=MONTHLY;BYMONTHDAY=31,1;UNTIL=20250331",
+			want:  &Rule{Freq: "MONTHLY", Interval: 1, Until: &until, ByMonthDay: []int{1, 31}},
+		},
+		{
+			name:  "until date time",
+			value: "FREQ=DAILY;UNTIL=20250331T235959Z",
+			want:  &Rule{Freq: "DAILY", Interval: 1, Until: &until},
+		},
+		{name: "missing freq", value: "COUNT=3", err: "rrule: FREQ is required"},
+		{name: "unsupported freq", value: "FREQ=YEARLY;COUNT=3", err: `rrule: unsupported FREQ "YEARLY"`},
+		{name: "unbounded", value: "FREQ=DAILY", err: ErrUnbounded.Error()},
+		{name: "count and until", value: "FREQ=DAILY;COUNT=3;UNTIL=20250331", err: "rrule: COUNT and UNTIL cannot be combined"},
+		{name: "zero interval", value: "FREQ=DAILY;INTERVAL=0;COUNT=3", err: `rrule: invalid INTERVAL "0"`},
+		{name: "zero count", value: "FREQ=DAILY;COUNT=0", err: `rrule: invalid COUNT "0"`},
+		{name: "invalid until", value: "FREQ=DAILY;UNTIL=2025-03-31", err: `rrule: invalid UNTIL "2025-03-31"`},
+		{name: "unknown day", value: "FREQ=WEEKLY;BYDAY=XX;COUNT=3", err: `rrule: unsupported BYDAY "XX"`},
+		{name: "month day out of range", value: "FREQ=MONTHLY;BYMONTHDAY=32;COUNT=3", err: `rrule: invalid BYMONTHDAY "32"`},
+		{name: "by day needs weekly", value: "FREQ=DAILY;BYDAY=MO;COUNT=3", err: "rrule: BYDAY is only supported with FREQ=WEEKLY"},
+		{name: "by month day needs monthly", value: "FREQ=WEEKLY;BYMONTHDAY=1;COUNT=3", err: "rrule: BYMONTHDAY is only supported with FREQ=MONTHLY"},
+		{name: "unknown part", value: "FREQ=DAILY;COUNT=3;BYHOUR=9", err: `rrule: unsupported part "BYHOUR"`},
+		{name: "empty value", value: "FREQ=DAILY;COUNT=", err: `rrule: invalid part "COUNT="`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rule, err := Parse(tt.value)
+			if tt.err != "" {
+				if err == nil || err.Error() != tt.err {
+					t.Fatalf("Parse(%q) error = %v, want %q", tt.value, err, tt.err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Parse(%q) error = %v", tt.value, err)
+			}
+			if !reflect.DeepEqual(rule, tt.want) {
+				t.Errorf("Parse(%q) = %+v, want %+v", tt.value, rule, tt.want)
+			}
+		})
+	}
+}
+
+func TestOccurrences(t *testing.T) {
+	jakarta, err := time.LoadLocation("Asia/Jakarta")
+	if err != nil {
+		t.Fatal(err)
+	}
+	berlin, err := time.LoadLocation("Europe/Berlin")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	day := func(loc *time.Location, year int, month time.Month, day, hour int) time.Time {
+		return time.Date(year, month, day, hour, 0, 0, 0, loc)
+	}
+
+	tests := []struct {
+		name  string
+		rule  string
+		start time.Time
+		limit int
+		want  []time.Time
+		err   error
+	}{
+		{
+			name:  "daily with interval",
+			rule:  "FREQ=DAILY;INTERVAL=2;COUNT=3",
+			start: day(jakarta, 2025, 1, 30, 19),
+			want:  []time.Time{day(jakarta, 2025, 1, 30, 19), day(jakarta, 2025, 2, 1, 19), day(jakarta, 2025, 2, 3, 19)},
+		},
+		{
+			name:  "weekly by day skips days before start",
+			rule:  "FREQ=WEEKLY;BYDAY=MO,FR;COUNT=4",
+			start: day(jakarta, 2025, 1, 1, 20), // a Wednesday
+			want: []time.Time{
+				day(jakarta, 2025, 1, 1, 20), day(jakarta, 2025, 1, 3, 20),
+				day(jakarta, 2025, 1, 6, 20), day(jakarta, 2025, 1, 10, 20),
+			},
+		},
+		{
+			name:  "weekly until is inclusive",
+			rule:  "FREQ=WEEKLY;UNTIL=20250115",
+			start: day(jakarta, 2025, 1, 1, 20),
+			want:  []time.Time{day(jakarta, 2025, 1, 1, 20), day(jakarta, 2025, 1, 8, 20), day(jakarta, 2025, 1, 15, 20)},
+		},
+		{
+			name:  "monthly skips months without the day",
+			rule:  "FREQ=MONTHLY;BYMONTHDAY=31;COUNT=3",
+			start: day(jakarta, 2025, 1, 31, 19),
+			want:  []time.Time{day(jakarta, 2025, 1, 31, 19), day(jakarta, 2025, 3, 31, 19), day(jakarta, 2025, 5, 31, 19)},
+		},
+		{
+			name:  "yearly step through february 29",
+			rule:  "FREQ=MONTHLY;INTERVAL=12;BYMONTHDAY=29;COUNT=2",
+			start: day(jakarta, 2024, 2, 29, 19),
+			want:  []time.Time{day(jakarta, 2024, 2, 29, 19), day(jakarta, 2028, 2, 29, 19)},
+		},
+		{
+			name:  "wall clock kept across daylight saving",
+			rule:  "FREQ=WEEKLY;COUNT=2",
+			start: day(berlin, 2025, 3, 29, 20),
+			want:  []time.Time{day(berlin, 2025, 3, 29, 20), day(berlin, 2025, 4, 5, 20)},
+		},
+		{
+			name:  "too many occurrences",
+			rule:  "FREQ=DAILY;COUNT=10",
+			start: day(jakarta, 2025, 1, 1, 19),
+			limit: 5,
+			err:   ErrTooManyOccurrences,
+		},
+		{
+			name:  "rule without further occurrences",
+			rule:  "FREQ=MONTHLY;INTERVAL=12;BYMONTHDAY=30;COUNT=2",
+			start: day(jakarta, 2025, 2, 1, 19),
+			err:   ErrNoOccurrences,
+		},
+		{
+			name:  "until ends a rule without further occurrences",
+			rule:  "FREQ=MONTHLY;INTERVAL=12;BYMONTHDAY=30;UNTIL=20300101",
+			start: day(jakarta, 2025, 2, 1, 19),
+			want:  []time.Time{day(jakarta, 2025, 2, 1, 19)},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rule, err := Parse(tt.rule)
+			if err != nil {
+				t.Fatalf("Parse(%q) error = %v", tt.rule, err)
+			}
+
+			limit := tt.limit
+			if limit == 0 {
+				limit = 100
+			}
+
+			got, err := rule.Occurrences(tt.start, limit)
+			if tt.err != nil {
+				if !errors.Is(err, tt.err) {
+					t.Fatalf("Occurrences() error = %v, want %v", err, tt.err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Occurrences() error = %v", err)
+			}
+
+			if len(got) != len(tt.want) {
+				t.Fatalf("Occurrences() = %v, want %v", got, tt.want)
+			}
+			for i := range got {
+				if !got[i].Equal(tt.want[i]) {
+					t.Errorf("occurrence %d = %v, want %v", i, got[i], tt.want[i])
+				}
+			}
+		})
+	}
+}
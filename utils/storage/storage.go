@@ -0,0 +1,39 @@
+// Package storage stores uploaded files. Backends are addressed by object
+// keys such as "events/<id>/poster.jpg" and serve the stored objects from a
+// public base URL.
+package storage
+
+import (
+	"context"
+	"errors"
+	"io"
+	"strings"
+)
+
+var ErrInvalidKey = errors.New("storage: invalid key")
+
+type Storage interface {
+	// Put stores size bytes read from body under key, replacing any object
+	// already stored there.
+	Put(ctx context.Context, key string, body io.Reader, size int64, contentType string) error
+	Delete(ctx context.Context, key string) error
+	// URL returns the public URL of the object stored under key.
+	URL(key string) string
+}
+
+// validKey rejects keys that could escape the storage root.
+func validKey(key string) bool {
+	if key == "" || strings.HasPrefix(key, "/") || strings.Contains(key, "\\") {
+		return false
+	}
+	for _, part := range strings.Split(key, "/") {
+		if part == "" || part == "." || part == ".." {
+			return false
+		}
+	}
+	return true
+}
+
+func joinURL(base, key string) string {
+	return strings.TrimSuffix(base, "/") + "/" + key
+}
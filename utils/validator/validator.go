@@ -75,7 +75,7 @@ func getErrorMessage(fe validator.FieldError, jsonFieldName string) string {
 	fieldDisplayName := strings.ReplaceAll(jsonFieldName, "_", " ")
 
 	switch fe.Tag() {
-	case "required":
+	case "required", "required_without":
 		return fmt.Sprintf("please enter your %s", fieldDisplayName)
 	case "email":
 		return "please enter a valid email address"
@@ -89,6 +89,10 @@ func getErrorMessage(fe validator.FieldError, jsonFieldName string) string {
 		return fmt.Sprintf("%s must be at least %s", fieldDisplayName, fe.Param())
 	case "len":
 		return fmt.Sprintf("%s must be exactly %s characters long", fieldDisplayName, fe.Param())
+	case "latitude", "longitude":
+		return fmt.Sprintf("please enter a valid %s", fieldDisplayName)
+	case "timezone":
+		return fmt.Sprintf("please enter a valid IANA time zone for %s, e.g. Asia/Jakarta", fieldDisplayName)
 	case "lte":
 		return fmt.Sprintf("%s cannot be more than %s", fieldDisplayName, fe.Param())
 	case "alpha":